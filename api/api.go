@@ -1,14 +1,22 @@
 package api
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/mimicode/flutterbuilder/pkg/artifact"
 	"github.com/mimicode/flutterbuilder/pkg/builder"
+	"github.com/mimicode/flutterbuilder/pkg/diagnosis"
+	"github.com/mimicode/flutterbuilder/pkg/events"
 	"github.com/mimicode/flutterbuilder/pkg/hooks"
 	"github.com/mimicode/flutterbuilder/pkg/logger"
+	"github.com/mimicode/flutterbuilder/pkg/publish"
 	"github.com/mimicode/flutterbuilder/pkg/types"
 )
 
@@ -16,39 +24,129 @@ import (
 type Platform = builder.Platform
 
 const (
-	PlatformAPK = builder.PlatformAPK
-	PlatformIOS = builder.PlatformIOS
+	PlatformAPK   = builder.PlatformAPK
+	PlatformAAB   = builder.PlatformAAB
+	PlatformIOS   = builder.PlatformIOS
+	PlatformWeb   = builder.PlatformWeb
+	PlatformLinux = builder.PlatformLinux
 )
 
+// BuildMode 构建模式
+type BuildMode = builder.BuildMode
+
+const (
+	BuildModeDebug   = builder.BuildModeDebug
+	BuildModeProfile = builder.BuildModeProfile
+	BuildModeRelease = builder.BuildModeRelease
+)
+
+// Stage 构建阶段
+type Stage = builder.Stage
+
+const (
+	StageClean         = builder.StageClean
+	StageGetDeps       = builder.StageGetDeps
+	StageCodeGen       = builder.StageCodeGen
+	StageSecurityCheck = builder.StageSecurityCheck
+	StageBuild         = builder.StageBuild
+	StagePostProcess   = builder.StagePostProcess
+)
+
+// StageResult 单个阶段的执行记录（状态为 success、failed 或 skipped）
+type StageResult = builder.StageResult
+
+// RetryPolicy 阶段命令的重试策略（次数、等待时间、重试条件）
+type RetryPolicy = builder.RetryPolicy
+
+// CommandAttempt 设置了重试策略的阶段中一次命令执行的记录
+type CommandAttempt = builder.CommandAttempt
+
+// TimeoutError 构建或阶段超时错误（Stage 为超时的阶段），可通过 errors.As 判断
+type TimeoutError = builder.TimeoutError
+
+// Publisher 产物发布后端，构建成功且产物验证通过后调用（可自行实现）
+type Publisher = publish.Publisher
+
+// FilesystemPublisher 将产物复制到本地目录或挂载的网络目录（如 NFS）
+type FilesystemPublisher = publish.FilesystemPublisher
+
+// S3Publisher 将产物上传到 S3 兼容的对象存储（AWS S3、MinIO 等）
+type S3Publisher = publish.S3Publisher
+
+// DefaultOutputNameTemplate 默认的产物命名模板（BuildConfig.OutputNameTemplate 为空时使用）
+const DefaultOutputNameTemplate = builder.DefaultOutputNameTemplate
+
 // IOSConfig iOS构建配置
 type IOSConfig = types.IOSConfig
 
 // ArtifactValidationConfig 产物验证配置
 type ArtifactValidationConfig = artifact.ArtifactValidationConfig
 
+// Artifact 产物文件信息（ABI、路径、大小、SHA-256）
+type Artifact = artifact.ArtifactFile
+
+// AndroidManifest 从APK中解析出的清单信息（包名、versionCode、versionName、SDK版本、权限、是否可调试）
+type AndroidManifest = artifact.AndroidManifest
+
+// ManifestExpectation APK清单的预期值，设置到 ArtifactValidationConfig.ExpectedManifest 后不符时验证失败
+type ManifestExpectation = artifact.ManifestExpectation
+
+// Event 构建事件，具体类型见 events 包（*events.StageStarted、*events.CommandOutputLine 等）
+type Event = events.Event
+
+// EventHandler 构建事件回调
+type EventHandler = events.Handler
+
+// Diagnosis 失败原因诊断（代码、描述、修复建议、输出摘录）
+type Diagnosis = diagnosis.Diagnosis
+
 // BuildConfig 构建配置
 type BuildConfig struct {
-	Platform         Platform                   // 构建平台
-	SourcePath       string                     // Flutter项目源代码路径
-	IOSConfig        *IOSConfig                 // iOS配置（可选）
-	CustomArgs       map[string]interface{}     // 自定义构建参数
-	HooksConfig      *hooks.HooksConfig         // 钩子配置（可选）
-	Logger           Logger                     // 日志接口（可选）
-	Verbose          bool                       // 是否显示详细日志
-	ValidationConfig *ArtifactValidationConfig // 产物验证配置（可选）
+	Platform           Platform                  // 构建平台
+	SourcePath         string                    // Flutter项目源代码路径
+	IOSConfig          *IOSConfig                // iOS配置（可选）
+	CustomArgs         map[string]interface{}    // 自定义构建参数
+	HooksConfig        *hooks.HooksConfig        // 钩子配置（可选）
+	Logger             Logger                    // 日志接口（可选）
+	Verbose            bool                      // 是否显示详细日志
+	ValidationConfig   *ArtifactValidationConfig // 产物验证配置（可选）
+	SplitPerABI        bool                      // 按ABI拆分APK（仅APK平台）
+	Flavor             string                    // 产品风味（如 dev、prod，对应 --flavor，Web/Linux 不支持）
+	Target             string                    // 入口文件（如 lib/main_prod.dart，对应 --target）
+	BuildMode          BuildMode                 // 构建模式（debug/profile/release，默认release）
+	BuildID            string                    // 构建ID（可选，为空时自动生成），作为本次构建日志的前缀
+	LogOutput          io.Writer                 // 控制台日志和命令输出的目标（可选，默认标准输出）
+	SkipStages         []Stage                   // 跳过的阶段（连同其前置/后置钩子）
+	OnlyStages         []Stage                   // 只执行的阶段（不能与 SkipStages、ResumeFrom 同时使用）
+	ResumeFrom         Stage                     // 从该阶段开始执行，跳过之前的阶段（如签名问题排查时从 build 继续）
+	Incremental        bool                      // 增量构建：依赖指纹与上次成功构建一致时跳过 clean、get_deps、code_gen
+	OnEvent            EventHandler              // 构建事件回调（可选），在构建流程中同步调用，不会并发调用
+	RetryPolicies      map[Stage]*RetryPolicy    // 各阶段命令的重试策略（可选，仅 get_deps、code_gen、build）
+	Timeout            time.Duration             // 整个构建的超时时间（0 表示不限制）
+	StageTimeouts      map[Stage]time.Duration   // 各阶段的超时时间（包括阶段的钩子和重试）
+	OutputDir          string                    // 产物收集目录（可选，相对路径相对 SourcePath），构建成功后将产物、调试信息和构建信息复制到该目录
+	OutputNameTemplate string                    // 收集和发布产物的命名模板（可选，默认 DefaultOutputNameTemplate）
+	Publishers         []Publisher               // 产物发布后端（可选），产物按命名模板命名后依次发布到每个后端
 }
 
 // BuildResult 构建结果
 type BuildResult struct {
-	Success          bool                         // 是否成功
-	Platform         Platform                     // 构建平台
-	BuildTime        time.Duration                // 构建耗时
-	OutputPath       string                       // 输出路径
-	Error            error                        // 错误信息
+	Success    bool          // 是否成功
+	Platform   Platform      // 构建平台
+	BuildTime  time.Duration // 构建耗时
+	OutputPath string        // 输出路径
+	Error      error         // 错误信息
 	// 新增验证相关字段
-	ArtifactSize     int64                        // 产物文件大小
-	ValidationResult *artifact.ValidationResult   // 验证结果详情
-	Verified         bool                         // 是否通过验证
+	ArtifactSize     int64                      // 产物文件大小
+	ValidationResult *artifact.ValidationResult // 验证结果详情
+	Verified         bool                       // 是否通过验证
+	Artifacts        []Artifact                 // 产物文件列表（拆分APK时每个ABI一项，目录产物和调试信息目录的校验和为树哈希）
+	ChecksumFiles    []string                   // 校验和清单（build/SHA256SUMS，启用SHA-512时还有 build/SHA512SUMS）
+	BuildID          string                     // 构建ID
+	Stages           []StageResult              // 各阶段的执行记录（包括跳过的阶段及原因）
+	Diagnoses        []Diagnosis                // 失败原因诊断（根据失败命令的输出识别，无法识别时为空）
+	OutputFiles      []string                   // 收集到输出目录的文件和目录（未设置 OutputDir 时为空）
+	PublishedURLs    []string                   // 已发布产物的地址（按发布后端和产物顺序，发布失败时为已发布的部分）
 }
 
 // Logger 日志接口
@@ -68,6 +166,11 @@ type FlutterBuilder interface {
 	// Build 执行构建
 	Build(config *BuildConfig) (*BuildResult, error)
 
+	// BuildContext 执行可取消的构建
+	// ctx 取消时终止正在运行的 flutter/gradle/xcodebuild 进程树和钩子脚本，
+	// 清理证书资源后返回包装了 ctx.Err() 的错误
+	BuildContext(ctx context.Context, config *BuildConfig) (*BuildResult, error)
+
 	// SetLogger 设置日志接口
 	SetLogger(logger Logger)
 
@@ -100,15 +203,75 @@ func (fb *flutterBuilderImpl) Validate(config *BuildConfig) error {
 		return fmt.Errorf("源代码路径不能为空")
 	}
 
-	if config.Platform != PlatformAPK && config.Platform != PlatformIOS {
+	switch config.Platform {
+	case PlatformAPK, PlatformAAB, PlatformIOS, PlatformWeb, PlatformLinux:
+	default:
 		return fmt.Errorf("不支持的平台: %s", config.Platform)
 	}
 
+	switch config.BuildMode {
+	case "", BuildModeDebug, BuildModeProfile, BuildModeRelease:
+	default:
+		return fmt.Errorf("不支持的构建模式: %s", config.BuildMode)
+	}
+
+	if config.ValidationConfig != nil && config.ValidationConfig.ExpectedManifest != nil && config.Platform != PlatformAPK {
+		return fmt.Errorf("清单预期值（ExpectedManifest）仅支持 APK 平台")
+	}
+	if err := stageSelection(config).Validate(); err != nil {
+		return err
+	}
+	if err := builder.ValidateRetryPolicies(config.RetryPolicies); err != nil {
+		return err
+	}
+	if err := timeouts(config).Validate(); err != nil {
+		return err
+	}
+	if config.OutputNameTemplate != "" && config.OutputDir == "" && len(config.Publishers) == 0 {
+		return fmt.Errorf("设置命名模板时必须同时设置输出目录或发布后端")
+	}
+	if err := outputConfig(config).Validate(); err != nil {
+		return err
+	}
+	for i, publisher := range config.Publishers {
+		if publisher == nil {
+			return fmt.Errorf("发布后端 %d 为空", i+1)
+		}
+		if validator, ok := publisher.(interface{ Validate() error }); ok {
+			if err := validator.Validate(); err != nil {
+				return fmt.Errorf("发布后端 %s 配置无效: %w", publisher.Name(), err)
+			}
+		}
+	}
 	return nil
 }
 
 // Build 执行构建
 func (fb *flutterBuilderImpl) Build(config *BuildConfig) (*BuildResult, error) {
+	return fb.BuildContext(context.Background(), config)
+}
+
+// BuildContext 执行可取消的构建
+func (fb *flutterBuilderImpl) BuildContext(ctx context.Context, config *BuildConfig) (*BuildResult, error) {
+	result, err := fb.build(ctx, config)
+	if config != nil && config.OnEvent != nil {
+		buildID := result.BuildID
+		if buildID == "" {
+			buildID = config.BuildID
+		}
+		events.NewEmitter(buildID, config.OnEvent).Emit(&events.BuildFinished{
+			Success:    result.Success,
+			Duration:   result.BuildTime,
+			OutputPath: result.OutputPath,
+			Artifacts:  result.Artifacts,
+			Error:      err,
+		})
+	}
+	return result, err
+}
+
+// build 执行构建，构建结束事件由 BuildContext 统一发送
+func (fb *flutterBuilderImpl) build(ctx context.Context, config *BuildConfig) (*BuildResult, error) {
 	startTime := time.Now()
 
 	// 验证配置
@@ -121,24 +284,32 @@ func (fb *flutterBuilderImpl) Build(config *BuildConfig) (*BuildResult, error) {
 		}, err
 	}
 
-	// 设置日志接口
+	// 每次构建使用独立的、带构建ID的日志实例，并发构建之间互不影响
+	buildID := config.BuildID
+	if buildID == "" {
+		buildID = newBuildID(config.Platform)
+	}
+	buildLogger := logger.NewWithBuildID(buildID)
+	if config.LogOutput != nil {
+		buildLogger.SetOutput(config.LogOutput, config.LogOutput)
+	}
 	if config.Logger != nil {
-		logger.SetExternalLogger(config.Logger)
+		buildLogger.SetExternalLogger(config.Logger)
 	} else if fb.customLogger != nil {
-		logger.SetExternalLogger(fb.customLogger)
+		buildLogger.SetExternalLogger(fb.customLogger)
 	}
 
 	// 设置日志级别
 	if config.Verbose {
-		logger.SetLevel(logger.DebugLevel)
+		buildLogger.SetLevel(logger.DebugLevel)
 	}
 
 	// 创建内部构建器
 	var internalBuilder builder.FlutterBuilder
 	if config.Platform == PlatformIOS {
-		internalBuilder = builder.NewFlutterBuilder("ios", config.IOSConfig, config.SourcePath)
+		internalBuilder = builder.NewFlutterBuilderWithLogger("ios", config.IOSConfig, config.SourcePath, buildLogger)
 	} else {
-		internalBuilder = builder.NewFlutterBuilder("apk", nil, config.SourcePath)
+		internalBuilder = builder.NewFlutterBuilderWithLogger(string(config.Platform), nil, config.SourcePath, buildLogger)
 	}
 
 	// 如果有自定义参数，需要传递给内部构建器
@@ -148,6 +319,24 @@ func (fb *flutterBuilderImpl) Build(config *BuildConfig) (*BuildResult, error) {
 		}
 	}
 
+	// 按ABI拆分APK
+	if config.SplitPerABI {
+		internalBuilder.SetCustomArgs(map[string]interface{}{"split_per_abi": true})
+	}
+
+	// 产品风味与入口文件
+	if config.Flavor != "" {
+		internalBuilder.SetCustomArgs(map[string]interface{}{"flavor": config.Flavor})
+	}
+	if config.Target != "" {
+		internalBuilder.SetCustomArgs(map[string]interface{}{"target": config.Target})
+	}
+
+	// 构建模式
+	if config.BuildMode != "" {
+		internalBuilder.SetCustomArgs(map[string]interface{}{"build_mode": string(config.BuildMode)})
+	}
+
 	// 如果有钩子配置，需要传递给内部构建器
 	if config.HooksConfig != nil {
 		if hooksBuilder, ok := internalBuilder.(interface {
@@ -159,6 +348,7 @@ func (fb *flutterBuilderImpl) Build(config *BuildConfig) (*BuildResult, error) {
 					Platform:  config.Platform,
 					BuildTime: time.Since(startTime),
 					Error:     fmt.Errorf("设置钩子配置失败: %w", err),
+					BuildID:   buildID,
 				}, fmt.Errorf("设置钩子配置失败: %w", err)
 			}
 		}
@@ -173,37 +363,106 @@ func (fb *flutterBuilderImpl) Build(config *BuildConfig) (*BuildResult, error) {
 		}
 	}
 
+	// 阶段选择（已在 Validate 中检查）
+	if err := internalBuilder.SetStageSelection(stageSelection(config)); err != nil {
+		return &BuildResult{
+			Success:   false,
+			Platform:  config.Platform,
+			BuildTime: time.Since(startTime),
+			Error:     err,
+			BuildID:   buildID,
+		}, err
+	}
+
+	internalBuilder.SetIncremental(config.Incremental)
+	if err := internalBuilder.SetTimeouts(timeouts(config)); err != nil {
+		return &BuildResult{
+			Success:   false,
+			Platform:  config.Platform,
+			BuildTime: time.Since(startTime),
+			Error:     err,
+			BuildID:   buildID,
+		}, err
+	}
+	if err := internalBuilder.SetRetryPolicies(config.RetryPolicies); err != nil {
+		return &BuildResult{
+			Success:   false,
+			Platform:  config.Platform,
+			BuildTime: time.Since(startTime),
+			Error:     err,
+			BuildID:   buildID,
+		}, err
+	}
+	if err := internalBuilder.SetOutput(outputConfig(config)); err != nil {
+		return &BuildResult{
+			Success:   false,
+			Platform:  config.Platform,
+			BuildTime: time.Since(startTime),
+			Error:     err,
+			BuildID:   buildID,
+		}, err
+	}
+	internalBuilder.SetPublishers(config.Publishers)
+	internalBuilder.SetEventHandler(config.OnEvent)
+
 	// 执行构建
-	err := internalBuilder.Run()
+	err := internalBuilder.RunContext(ctx)
 	buildTime := time.Since(startTime)
 
 	result := &BuildResult{
 		Success:   err == nil,
 		Platform:  config.Platform,
 		BuildTime: buildTime,
+		BuildID:   buildID,
+		Stages:    internalBuilder.GetStageResults(),
 	}
+	result.PublishedURLs = internalBuilder.GetPublishedURLs()
 
 	if err != nil {
 		result.Error = err
+		result.Diagnoses = diagnosis.FromError(err)
+		// 产物验证失败时保留验证详情
+		if validationResult := internalBuilder.GetValidationResult(); validationResult != nil {
+			result.ValidationResult = validationResult
+			result.ArtifactSize = validationResult.FileSize
+			result.Artifacts = validationResult.Artifacts
+			result.ChecksumFiles = validationResult.ChecksumFiles
+		}
 		return result, err
 	}
 
 	// 获取输出路径
-	result.OutputPath = getOutputPath(config.Platform, config.SourcePath, config.IOSConfig)
+	result.OutputPath = getOutputPath(config)
+	result.OutputFiles = internalBuilder.GetOutputFiles()
+	if config.SplitPerABI && config.Platform == PlatformAPK {
+		// 拆分构建有多个APK，输出路径为所在目录
+		result.OutputPath = filepath.Dir(result.OutputPath)
+	}
 
 	// 获取验证结果（如果可用）
 	if validationBuilder, ok := internalBuilder.(interface {
 		GetValidationConfig() *artifact.ArtifactValidationConfig
 	}); ok {
 		validationConfig := validationBuilder.GetValidationConfig()
-		if validationConfig != nil && validationConfig.EnableValidation {
+		if validationResult := internalBuilder.GetValidationResult(); validationResult != nil && validationConfig != nil && validationConfig.EnableValidation {
+			// 使用构建过程中的验证结果
+			result.ValidationResult = validationResult
+			result.Verified = validationResult.Success
+			result.ArtifactSize = validationResult.FileSize
+			result.Artifacts = validationResult.Artifacts
+			result.ChecksumFiles = validationResult.ChecksumFiles
+		} else if validationConfig != nil && validationConfig.EnableValidation {
 			// 创建验证器来获取验证结果
 			validator := artifact.NewArtifactValidator()
 			artifactConfig := &artifact.ArtifactConfig{
-				Platform:         convertPlatformForAPI(config.Platform),
-				SourcePath:       config.SourcePath,
+				Platform:          convertPlatformForAPI(config.Platform),
+				SourcePath:        config.SourcePath,
 				ValidateIntegrity: true,
-				ValidationConfig: validationConfig,
+				ValidationConfig:  validationConfig,
+				SplitPerABI:       config.SplitPerABI && config.Platform == PlatformAPK,
+				Flavor:            getFlavor(config),
+				BuildMode:         getBuildMode(config),
+				LinuxArch:         artifact.LinuxArch(getTargetPlatform(config)),
 			}
 			if config.Platform == PlatformIOS && config.IOSConfig != nil {
 				artifactConfig.IOSConfig = config.IOSConfig
@@ -213,6 +472,7 @@ func (fb *flutterBuilderImpl) Build(config *BuildConfig) (*BuildResult, error) {
 				result.ValidationResult = validationResult
 				result.Verified = validationResult.Success
 				result.ArtifactSize = validationResult.FileSize
+				result.Artifacts = validationResult.Artifacts
 			} else {
 				// 验证失败，但不影响构建结果
 				result.Verified = false
@@ -223,33 +483,79 @@ func (fb *flutterBuilderImpl) Build(config *BuildConfig) (*BuildResult, error) {
 	return result, nil
 }
 
+// stageSelection 返回配置中的阶段选择，未设置时返回nil（执行全部阶段）
+func stageSelection(config *BuildConfig) *builder.StageSelection {
+	if len(config.SkipStages) == 0 && len(config.OnlyStages) == 0 && config.ResumeFrom == "" {
+		return nil
+	}
+	return &builder.StageSelection{
+		Skip:       config.SkipStages,
+		Only:       config.OnlyStages,
+		ResumeFrom: config.ResumeFrom,
+	}
+}
+
+// timeouts 返回配置中的超时设置，未设置时返回nil
+func timeouts(config *BuildConfig) *builder.Timeouts {
+	if config.Timeout == 0 && len(config.StageTimeouts) == 0 {
+		return nil
+	}
+	return &builder.Timeouts{Build: config.Timeout, Stages: config.StageTimeouts}
+}
+
+// outputConfig 返回配置中的产物收集设置，未设置输出目录和命名模板时返回nil
+func outputConfig(config *BuildConfig) *builder.OutputConfig {
+	if config.OutputDir == "" && config.OutputNameTemplate == "" {
+		return nil
+	}
+	return &builder.OutputConfig{Dir: config.OutputDir, NameTemplate: config.OutputNameTemplate}
+}
+
+// newBuildID 生成构建ID，格式为 <平台>-<时间>-<随机后缀>，如 apk-20060102-150405-1a2b3c
+func newBuildID(platform Platform) string {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Sprintf("%s-%s", platform, time.Now().Format("20060102-150405.000"))
+	}
+	return fmt.Sprintf("%s-%s-%s", platform, time.Now().Format("20060102-150405"), hex.EncodeToString(suffix))
+}
+
 // getOutputPath 获取构建输出路径
-func getOutputPath(platform Platform, sourcePath string, iosConfig *IOSConfig) string {
-	switch platform {
+func getOutputPath(config *BuildConfig) string {
+	sourcePath := config.SourcePath
+	flavor := getFlavor(config)
+	mode := getBuildMode(config)
+
+	switch config.Platform {
 	case PlatformAPK:
-		return fmt.Sprintf("%s/build/app/outputs/flutter-apk/app-release.apk", sourcePath)
+		return fmt.Sprintf("%s/build/app/outputs/flutter-apk/%s", sourcePath, artifact.APKFileName(flavor, "", mode))
+	case PlatformAAB:
+		return fmt.Sprintf("%s/build/app/outputs/bundle/%s/%s", sourcePath, artifact.AndroidVariantName(flavor, mode), artifact.AABFileName(flavor, mode))
 	case PlatformIOS:
 		// 如果提供了证书配置，返回IPA文件路径；否则返回构建目录
-		if iosConfig != nil && iosConfig.TeamID != "" {
+		if config.IOSConfig != nil && config.IOSConfig.TeamID != "" {
 			// 构建IPA，尝试获取实际的IPA文件路径
-			return getActualIPAPath(sourcePath)
+			return getActualIPAPath(sourcePath, flavor)
 		} else {
 			// 仅构建iOS，返回.app文件路径
-			return fmt.Sprintf("%s/build/ios/iphoneos/Runner.app", sourcePath)
+			return getActualIOSAppPath(sourcePath, flavor)
 		}
+	case PlatformWeb:
+		return fmt.Sprintf("%s/build/web", sourcePath)
+	case PlatformLinux:
+		return artifact.LinuxBundlePath(sourcePath, artifact.LinuxArch(getTargetPlatform(config)), mode)
 	default:
 		return ""
 	}
 }
 
 // getActualIPAPath 获取实际生成的IPA文件路径
-func getActualIPAPath(sourcePath string) string {
+func getActualIPAPath(sourcePath, flavor string) string {
 	ipaDir := fmt.Sprintf("%s/build/ios/ipa", sourcePath)
 
-	// 尝试读取目录中的IPA文件
-	if files, err := filepath.Glob(fmt.Sprintf("%s/*.ipa", ipaDir)); err == nil && len(files) > 0 {
-		// 返回找到的第一个IPA文件路径
-		return files[0]
+	// 尝试读取目录中的IPA文件（优先匹配flavor）
+	if ipaPath, err := artifact.FindIPAFile(ipaDir, flavor); err == nil {
+		return ipaPath
 	}
 
 	// 如果找不到实际文件，返回默认的IPA路径格式
@@ -257,6 +563,68 @@ func getActualIPAPath(sourcePath string) string {
 	return fmt.Sprintf("%s/Runner.ipa", ipaDir)
 }
 
+// getActualIOSAppPath 获取实际生成的iOS App路径（带flavor时.app名称可能不是Runner.app）
+func getActualIOSAppPath(sourcePath, flavor string) string {
+	appDir := fmt.Sprintf("%s/build/ios/iphoneos", sourcePath)
+	if appPath, err := artifact.FindIOSApp(appDir, flavor); err == nil {
+		return appPath
+	}
+	return fmt.Sprintf("%s/Runner.app", appDir)
+}
+
+// getFlavor 获取生效的产品风味（Web/Linux 不支持flavor）
+func getFlavor(config *BuildConfig) string {
+	switch config.Platform {
+	case PlatformAPK, PlatformAAB, PlatformIOS:
+		if config.Flavor != "" {
+			return config.Flavor
+		}
+		if flavor, ok := config.CustomArgs["flavor"].(string); ok {
+			return flavor
+		}
+		// 兼容通过 flutter_build_args 传入的 --flavor
+		buildArgs := builder.CustomArgStringSlice(config.CustomArgs, "flutter_build_args")
+		for i, arg := range buildArgs {
+			if arg == "--flavor" && i+1 < len(buildArgs) {
+				return buildArgs[i+1]
+			}
+			if strings.HasPrefix(arg, "--flavor=") {
+				return strings.TrimPrefix(arg, "--flavor=")
+			}
+		}
+	}
+	return ""
+}
+
+// getBuildMode 获取生效的构建模式（优先 BuildConfig.BuildMode，其次 CustomArgs 中的 build_mode）
+func getBuildMode(config *BuildConfig) artifact.BuildMode {
+	if config.BuildMode != "" {
+		return artifact.BuildMode(config.BuildMode)
+	}
+	if mode, ok := config.CustomArgs["build_mode"].(string); ok && mode != "" {
+		return artifact.BuildMode(mode)
+	}
+	return artifact.BuildModeRelease
+}
+
+// getTargetPlatform 获取生效的 --target-platform（CustomArgs 中的 target_platform 优先于 flutter_build_args 中的参数）
+func getTargetPlatform(config *BuildConfig) string {
+	if targetPlatform, ok := config.CustomArgs["target_platform"].(string); ok && targetPlatform != "" {
+		return targetPlatform
+	}
+	targetPlatform := ""
+	buildArgs := builder.CustomArgStringSlice(config.CustomArgs, "flutter_build_args")
+	for i, arg := range buildArgs {
+		if arg == "--target-platform" && i+1 < len(buildArgs) {
+			targetPlatform = buildArgs[i+1]
+		}
+		if strings.HasPrefix(arg, "--target-platform=") {
+			targetPlatform = strings.TrimPrefix(arg, "--target-platform=")
+		}
+	}
+	return targetPlatform
+}
+
 // QuickBuild 快速构建函数（便捷方法）
 func QuickBuild(platform Platform, sourcePath string) (*BuildResult, error) {
 	builder := NewFlutterBuilder()
@@ -288,6 +656,21 @@ func QuickBuildAPKWithHooks(sourcePath string, hooksConfig *hooks.HooksConfig) (
 	return QuickBuildWithHooks(PlatformAPK, sourcePath, hooksConfig)
 }
 
+// QuickBuildAAB 快速构建Android App Bundle（便捷方法）
+func QuickBuildAAB(sourcePath string) (*BuildResult, error) {
+	return QuickBuild(PlatformAAB, sourcePath)
+}
+
+// QuickBuildWeb 快速构建Flutter Web（便捷方法）
+func QuickBuildWeb(sourcePath string) (*BuildResult, error) {
+	return QuickBuild(PlatformWeb, sourcePath)
+}
+
+// QuickBuildLinux 快速构建Linux桌面（便捷方法）
+func QuickBuildLinux(sourcePath string) (*BuildResult, error) {
+	return QuickBuild(PlatformLinux, sourcePath)
+}
+
 // QuickBuildIOS 快速构建iOS（便捷方法）
 func QuickBuildIOS(sourcePath string, iosConfig *IOSConfig) (*BuildResult, error) {
 	builder := NewFlutterBuilder()
@@ -351,8 +734,14 @@ func convertPlatformForAPI(platform Platform) artifact.Platform {
 	switch platform {
 	case PlatformAPK:
 		return artifact.PlatformAPK
+	case PlatformAAB:
+		return artifact.PlatformAAB
 	case PlatformIOS:
 		return artifact.PlatformIOS
+	case PlatformWeb:
+		return artifact.PlatformWeb
+	case PlatformLinux:
+		return artifact.PlatformLinux
 	default:
 		return artifact.Platform(platform)
 	}
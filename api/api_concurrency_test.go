@@ -0,0 +1,157 @@
+//go:build !windows
+
+package api
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeFlutterScript 模拟flutter命令：build子命令在当前工作目录生成APK
+const fakeFlutterScript = `#!/bin/sh
+if [ "$1" = "build" ]; then
+	mkdir -p build/app/outputs/flutter-apk
+	echo "$PWD" > build/app/outputs/flutter-apk/app-release.apk
+fi
+exit 0
+`
+
+// recordingLogger 记录日志内容的并发安全日志实现
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *recordingLogger) record(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingLogger) Debug(format string, args ...interface{})   { r.record(format, args...) }
+func (r *recordingLogger) Info(format string, args ...interface{})    { r.record(format, args...) }
+func (r *recordingLogger) Warning(format string, args ...interface{}) { r.record(format, args...) }
+func (r *recordingLogger) Error(format string, args ...interface{})   { r.record(format, args...) }
+func (r *recordingLogger) Success(format string, args ...interface{}) { r.record(format, args...) }
+func (r *recordingLogger) Header(title string)                        { r.record("%s", title) }
+func (r *recordingLogger) Println(args ...interface{}) {
+	r.record("%s", strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+func (r *recordingLogger) Printf(format string, args ...interface{}) { r.record(format, args...) }
+
+// allPrefixed 检查所有非空日志是否以指定前缀开头
+func (r *recordingLogger) allPrefixed(prefix string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range r.lines {
+		if line != "" && !strings.HasPrefix(line, prefix) {
+			return false
+		}
+	}
+	return len(r.lines) > 0
+}
+
+func (r *recordingLogger) contains(s string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range r.lines {
+		if strings.Contains(line, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// setupFakeFlutter 将模拟的 flutter/dart 命令加入 PATH
+func setupFakeFlutter(t *testing.T) {
+	t.Helper()
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "flutter"), []byte(fakeFlutterScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// build_runner 不可用时代码生成阶段会被跳过
+	if err := os.WriteFile(filepath.Join(binDir, "dart"), []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// TestConcurrentBuilds 并发构建多个项目，验证工作目录与日志互不干扰（配合 -race 运行）
+func TestConcurrentBuilds(t *testing.T) {
+	setupFakeFlutter(t)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const count = 4
+	projects := make([]string, count)
+	loggers := make([]*recordingLogger, count)
+	for i := range projects {
+		projects[i] = t.TempDir()
+		loggers[i] = &recordingLogger{}
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, count)
+	results := make([]*BuildResult, count)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			config := &BuildConfig{
+				Platform:         PlatformAPK,
+				SourcePath:       projects[i],
+				Logger:           loggers[i],
+				Verbose:          i%2 == 0,
+				ValidationConfig: DisableValidationConfig(),
+				BuildID:          fmt.Sprintf("build-%d", i),
+			}
+			results[i], errs[i] = NewFlutterBuilder().Build(config)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, project := range projects {
+		if errs[i] != nil {
+			t.Errorf("项目 %d 构建失败: %v", i, errs[i])
+			continue
+		}
+
+		apk := filepath.Join(project, "build", "app", "outputs", "flutter-apk", "app-release.apk")
+		content, err := os.ReadFile(apk)
+		if err != nil {
+			t.Errorf("项目 %d 未生成APK: %v", i, err)
+			continue
+		}
+		if got := strings.TrimSpace(string(content)); got != project {
+			t.Errorf("项目 %d 的构建命令在错误的目录执行: %s", i, got)
+		}
+
+		buildID := fmt.Sprintf("build-%d", i)
+		if results[i].BuildID != buildID {
+			t.Errorf("项目 %d 的构建ID错误: %s", i, results[i].BuildID)
+		}
+		if !loggers[i].allPrefixed("[" + buildID + "] ") {
+			t.Errorf("项目 %d 的日志未全部带构建ID前缀", i)
+		}
+
+		if !loggers[i].contains(project) {
+			t.Errorf("项目 %d 的日志缺少自身项目路径", i)
+		}
+		for j, other := range projects {
+			if j != i && loggers[i].contains(other) {
+				t.Errorf("项目 %d 的日志包含项目 %d 的内容", i, j)
+			}
+		}
+	}
+
+	if after, _ := os.Getwd(); after != wd {
+		t.Errorf("构建修改了进程工作目录: %s -> %s", wd, after)
+	}
+}
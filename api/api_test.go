@@ -1,6 +1,10 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -37,6 +41,23 @@ func TestBuildConfig(t *testing.T) {
 		t.Error("Expected validation error for invalid platform")
 	}
 
+	// 测试无效构建模式
+	invalidConfig.Platform = PlatformAPK
+	invalidConfig.BuildMode = "staging"
+	err = builder.Validate(invalidConfig)
+	if err == nil {
+		t.Error("Expected validation error for invalid build mode")
+	}
+
+	// 测试非APK平台设置清单预期值
+	invalidConfig.Platform = PlatformAAB
+	invalidConfig.BuildMode = ""
+	invalidConfig.ValidationConfig = &ArtifactValidationConfig{ExpectedManifest: &ManifestExpectation{Package: "com.example.app"}}
+	err = builder.Validate(invalidConfig)
+	if err == nil {
+		t.Error("Expected validation error for manifest expectation on non-APK platform")
+	}
+
 	// 测试有效配置但路径不存在
 	validConfig := &BuildConfig{
 		Platform:   PlatformAPK,
@@ -55,9 +76,21 @@ func TestPlatformConstants(t *testing.T) {
 		t.Errorf("Expected PlatformAPK to be 'apk', got '%s'", PlatformAPK)
 	}
 
+	if PlatformAAB != "aab" {
+		t.Errorf("Expected PlatformAAB to be 'aab', got '%s'", PlatformAAB)
+	}
+
 	if PlatformIOS != "ios" {
 		t.Errorf("Expected PlatformIOS to be 'ios', got '%s'", PlatformIOS)
 	}
+
+	if PlatformWeb != "web" {
+		t.Errorf("Expected PlatformWeb to be 'web', got '%s'", PlatformWeb)
+	}
+
+	if PlatformLinux != "linux" {
+		t.Errorf("Expected PlatformLinux to be 'linux', got '%s'", PlatformLinux)
+	}
 }
 
 // TestLoggerInterface 测试自定义日志接口
@@ -177,7 +210,7 @@ func TestIOSBuildLogic(t *testing.T) {
 
 	// 验证输出路径为 .app 文件
 	expectedPath1 := "/non/existent/path/build/ios/iphoneos/Runner.app"
-	actualPath1 := getOutputPath(config1.Platform, config1.SourcePath, config1.IOSConfig)
+	actualPath1 := getOutputPath(config1)
 	if actualPath1 != expectedPath1 {
 		t.Errorf("Expected iOS path without cert: %s, got: %s", expectedPath1, actualPath1)
 	}
@@ -193,7 +226,7 @@ func TestIOSBuildLogic(t *testing.T) {
 
 	// 验证输出路径为具体的IPA文件（由于文件不存在，会返回默认路径）
 	expectedPath2 := "/non/existent/path/build/ios/ipa/Runner.ipa"
-	actualPath2 := getOutputPath(config2.Platform, config2.SourcePath, config2.IOSConfig)
+	actualPath2 := getOutputPath(config2)
 	if actualPath2 != expectedPath2 {
 		t.Errorf("Expected iOS path with cert: %s, got: %s", expectedPath2, actualPath2)
 	}
@@ -205,8 +238,114 @@ func TestIOSBuildLogic(t *testing.T) {
 	}
 
 	expectedPath3 := "/non/existent/path/build/app/outputs/flutter-apk/app-release.apk"
-	actualPath3 := getOutputPath(config3.Platform, config3.SourcePath, nil)
+	actualPath3 := getOutputPath(config3)
 	if actualPath3 != expectedPath3 {
 		t.Errorf("Expected APK path: %s, got: %s", expectedPath3, actualPath3)
 	}
+
+	// 测试4: Android App Bundle构建路径
+	expectedPath4 := "/non/existent/path/build/app/outputs/bundle/release/app-release.aab"
+	actualPath4 := getOutputPath(&BuildConfig{Platform: PlatformAAB, SourcePath: "/non/existent/path"})
+	if actualPath4 != expectedPath4 {
+		t.Errorf("Expected AAB path: %s, got: %s", expectedPath4, actualPath4)
+	}
+
+	// 测试5: Web构建输出目录
+	expectedPath5 := "/non/existent/path/build/web"
+	actualPath5 := getOutputPath(&BuildConfig{Platform: PlatformWeb, SourcePath: "/non/existent/path"})
+	if actualPath5 != expectedPath5 {
+		t.Errorf("Expected web path: %s, got: %s", expectedPath5, actualPath5)
+	}
+}
+
+// TestFlavorOutputPath 测试带flavor的输出路径
+func TestFlavorOutputPath(t *testing.T) {
+	apkConfig := &BuildConfig{Platform: PlatformAPK, SourcePath: "/non/existent/path", Flavor: "prod"}
+	expectedAPK := "/non/existent/path/build/app/outputs/flutter-apk/app-prod-release.apk"
+	if actual := getOutputPath(apkConfig); actual != expectedAPK {
+		t.Errorf("Expected flavored APK path: %s, got: %s", expectedAPK, actual)
+	}
+
+	aabConfig := &BuildConfig{Platform: PlatformAAB, SourcePath: "/non/existent/path", Flavor: "staging"}
+	expectedAAB := "/non/existent/path/build/app/outputs/bundle/stagingRelease/app-staging-release.aab"
+	if actual := getOutputPath(aabConfig); actual != expectedAAB {
+		t.Errorf("Expected flavored AAB path: %s, got: %s", expectedAAB, actual)
+	}
+
+	// 通过 CustomArgs 传入的 flavor 同样生效
+	customConfig := &BuildConfig{
+		Platform:   PlatformAPK,
+		SourcePath: "/non/existent/path",
+		CustomArgs: map[string]interface{}{"flavor": "dev"},
+	}
+	expectedCustom := "/non/existent/path/build/app/outputs/flutter-apk/app-dev-release.apk"
+	if actual := getOutputPath(customConfig); actual != expectedCustom {
+		t.Errorf("Expected flavored APK path: %s, got: %s", expectedCustom, actual)
+	}
+
+	// 从JSON/YAML解析得到的 flutter_build_args 为 []interface{}，与构建器读取方式一致
+	interfaceArgsConfig := &BuildConfig{
+		Platform:   PlatformAPK,
+		SourcePath: "/non/existent/path",
+		CustomArgs: map[string]interface{}{"flutter_build_args": []interface{}{"--flavor", "qa"}},
+	}
+	expectedInterfaceArgs := "/non/existent/path/build/app/outputs/flutter-apk/app-qa-release.apk"
+	if actual := getOutputPath(interfaceArgsConfig); actual != expectedInterfaceArgs {
+		t.Errorf("Expected flavored APK path: %s, got: %s", expectedInterfaceArgs, actual)
+	}
+
+	// Web 不支持 flavor，路径不变
+	webConfig := &BuildConfig{Platform: PlatformWeb, SourcePath: "/non/existent/path", Flavor: "prod"}
+	if actual := getOutputPath(webConfig); actual != "/non/existent/path/build/web" {
+		t.Errorf("Expected web path without flavor, got: %s", actual)
+	}
+
+	// 构建模式影响产物文件名
+	debugConfig := &BuildConfig{Platform: PlatformAPK, SourcePath: "/non/existent/path", Flavor: "dev", BuildMode: BuildModeDebug}
+	expectedDebug := "/non/existent/path/build/app/outputs/flutter-apk/app-dev-debug.apk"
+	if actual := getOutputPath(debugConfig); actual != expectedDebug {
+		t.Errorf("Expected debug APK path: %s, got: %s", expectedDebug, actual)
+	}
+
+	// 已生成的flavor IPA优先于其他IPA
+	tempDir := t.TempDir()
+	ipaDir := filepath.Join(tempDir, "build", "ios", "ipa")
+	if err := os.MkdirAll(ipaDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"MyApp.ipa", "MyApp Prod.ipa"} {
+		if err := os.WriteFile(filepath.Join(ipaDir, name), []byte("ipa"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	iosConfig := &BuildConfig{
+		Platform:   PlatformIOS,
+		SourcePath: tempDir,
+		IOSConfig:  &IOSConfig{TeamID: "TEST123456"},
+		Flavor:     "prod",
+	}
+	if actual := getOutputPath(iosConfig); actual != filepath.Join(ipaDir, "MyApp Prod.ipa") {
+		t.Errorf("Expected flavored IPA, got: %s", actual)
+	}
+}
+
+// TestBuildContextCanceled 测试已取消的上下文直接返回取消错误
+func TestBuildContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	builder := NewFlutterBuilder()
+	result, err := builder.BuildContext(ctx, &BuildConfig{
+		Platform:   PlatformAPK,
+		SourcePath: t.TempDir(),
+	})
+	if err == nil {
+		t.Fatal("Expected cancellation error")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error to wrap context.Canceled, got: %v", err)
+	}
+	if result == nil || result.Success {
+		t.Error("Expected failed build result")
+	}
 }
@@ -0,0 +1,150 @@
+//go:build !windows
+
+package api
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mimicode/flutterbuilder/pkg/events"
+	"github.com/mimicode/flutterbuilder/pkg/hooks"
+)
+
+// TestBuildEvents 构建过程按顺序发送阶段、钩子、命令和构建结束事件
+func TestBuildEvents(t *testing.T) {
+	setupFakeFlutter(t)
+
+	// 在模拟的 flutter 之前放置一个会输出内容的版本
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+echo "flutter $1"
+printf 'partial' >&2
+if [ "$1" = "build" ]; then
+	mkdir -p build/app/outputs/flutter-apk
+	echo "$PWD" > build/app/outputs/flutter-apk/app-release.apk
+fi
+`
+	if err := os.WriteFile(filepath.Join(binDir, "flutter"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "hook.dart"), []byte("void main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var received []Event
+	config := &BuildConfig{
+		Platform:         PlatformAPK,
+		SourcePath:       project,
+		BuildID:          "events",
+		Logger:           &recordingLogger{},
+		LogOutput:        io.Discard,
+		ValidationConfig: DisableValidationConfig(),
+		HooksConfig: &hooks.HooksConfig{Hooks: map[hooks.HookType][]*hooks.HookConfig{
+			hooks.HookPreBuild: {{ScriptPath: "hook.dart", ContinueOnError: true}},
+		}},
+		SkipStages: []Stage{StageClean},
+		OnEvent: func(event Event) {
+			received = append(received, event)
+		},
+	}
+	if _, err := NewFlutterBuilder().Build(config); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	var stages []string
+	var hookFinished *events.HookFinished
+	var sawLine, sawPartial, sawExit bool
+	for _, event := range received {
+		if meta := event.EventMeta(); meta.BuildID != "events" || meta.Time.IsZero() {
+			t.Errorf("事件公共字段错误: %+v", meta)
+		}
+		switch e := event.(type) {
+		case *events.StageStarted:
+			if e.Total != 6 {
+				t.Errorf("阶段总数期望 6，实际 %d", e.Total)
+			}
+			stages = append(stages, "start:"+e.Stage)
+		case *events.StageFinished:
+			stages = append(stages, e.Status+":"+e.Stage)
+		case *events.HookStarted:
+			if e.HookType != hooks.HookPreBuild || e.Index != 1 || e.Total != 1 {
+				t.Errorf("钩子开始事件错误: %+v", e)
+			}
+		case *events.HookFinished:
+			hookFinished = e
+		case *events.CommandOutputLine:
+			if e.Stream == "stdout" && e.Line == "flutter build" {
+				sawLine = true
+			}
+			if e.Stream == "stderr" && e.Line == "partial" {
+				sawPartial = true
+			}
+		case *events.CommandExited:
+			if len(e.Command) > 1 && e.Command[1] == "build" && e.ExitCode == 0 && e.Error == nil {
+				sawExit = true
+			}
+		}
+	}
+
+	want := []string{
+		"skipped:clean",
+		"start:get_deps", "success:get_deps",
+		"start:code_gen", "success:code_gen",
+		"start:security_check", "success:security_check",
+		"start:build", "success:build",
+		"start:post_process", "success:post_process",
+	}
+	if len(stages) != len(want) {
+		t.Fatalf("阶段事件期望 %v，实际 %v", want, stages)
+	}
+	for i := range want {
+		if stages[i] != want[i] {
+			t.Errorf("第 %d 个阶段事件期望 %s，实际 %s", i, want[i], stages[i])
+		}
+	}
+	if hookFinished == nil || hookFinished.Result == nil || hookFinished.Result.Success {
+		t.Errorf("钩子结束事件应携带失败的执行结果: %+v", hookFinished)
+	}
+	if !sawLine || !sawPartial || !sawExit {
+		t.Errorf("缺少命令事件: 输出行=%v 末尾不完整行=%v 退出=%v", sawLine, sawPartial, sawExit)
+	}
+
+	last, ok := received[len(received)-1].(*events.BuildFinished)
+	if !ok || !last.Success || last.Error != nil {
+		t.Errorf("最后一个事件应为成功的构建结束事件: %+v", received[len(received)-1])
+	}
+}
+
+// TestBuildEventsValidation 产物验证的每个检查项发送事件，失败时构建结束事件携带错误
+func TestBuildEventsValidation(t *testing.T) {
+	setupFakeFlutter(t)
+
+	var checks int
+	var finished *events.BuildFinished
+	config := &BuildConfig{
+		Platform:   PlatformAPK,
+		SourcePath: t.TempDir(),
+		Logger:     &recordingLogger{},
+		LogOutput:  io.Discard,
+		OnEvent: func(event Event) {
+			switch e := event.(type) {
+			case *events.ValidationCheck:
+				checks++
+			case *events.BuildFinished:
+				finished = e
+			}
+		},
+	}
+	_, err := NewFlutterBuilder().Build(config)
+	if checks == 0 {
+		t.Error("未发送产物验证检查项事件")
+	}
+	if finished == nil || finished.Success != (err == nil) || (finished.Error == nil) != (err == nil) {
+		t.Errorf("构建结束事件与构建结果不一致: %+v, err=%v", finished, err)
+	}
+}
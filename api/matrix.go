@@ -0,0 +1,247 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/internal/fsutil"
+)
+
+// DefaultMatrixConcurrency 构建矩阵默认的最大并发构建数
+const DefaultMatrixConcurrency = 2
+
+// workspaceSkipDirs 复制隔离工作区时跳过的项目顶层目录（构建产物和缓存，flutter clean 会删除）
+var workspaceSkipDirs = map[string]bool{
+	"build":      true,
+	".dart_tool": true,
+}
+
+// MatrixOptions 构建矩阵选项
+type MatrixOptions struct {
+	Concurrency      int    // 最大并发构建数（<=0 时使用 DefaultMatrixConcurrency）
+	WorkspaceDir     string // 隔离工作区根目录（为空时在系统临时目录下创建），构建完成后保留以便获取产物
+	DisableIsolation bool   // 不复制工作区，直接在源代码目录构建（同一源代码目录的构建串行执行）
+}
+
+// MatrixEntryResult 构建矩阵中单项的结果
+type MatrixEntryResult struct {
+	Name      string       // 名称（BuildID，未设置时为 <平台>[-<flavor>][-<mode>]）
+	Workspace string       // 实际构建目录（隔离时为工作区副本，否则为源代码目录）
+	Result    *BuildResult // 构建结果（未开始构建时仅包含平台和错误）
+	Error     error        // 错误信息
+}
+
+// MatrixResult 构建矩阵汇总结果
+type MatrixResult struct {
+	Success  bool                 // 是否全部成功
+	Duration time.Duration        // 总耗时
+	Entries  []*MatrixEntryResult // 各项结果，顺序与输入一致
+}
+
+// Failed 返回失败的项数
+func (r *MatrixResult) Failed() int {
+	failed := 0
+	for _, entry := range r.Entries {
+		if entry.Error != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// BuildMatrix 在一次调用中构建多个配置（如 apk/aab/ios × 多个 flavor）
+//
+// 最多同时运行 options.Concurrency 个构建。并发构建时每项在独立的工作区副本中执行，
+// 避免 flutter clean 和构建产物相互覆盖；单项失败不影响其他项，ctx 取消时终止运行中的构建并跳过未开始的项。
+// 任意一项失败时返回汇总错误，MatrixResult 中仍包含全部结果。
+func BuildMatrix(ctx context.Context, configs []*BuildConfig, options *MatrixOptions) (*MatrixResult, error) {
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("构建矩阵不能为空")
+	}
+	if options == nil {
+		options = &MatrixOptions{}
+	}
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultMatrixConcurrency
+	}
+	if concurrency > len(configs) {
+		concurrency = len(configs)
+	}
+	isolate := !options.DisableIsolation && concurrency > 1
+
+	builder := NewFlutterBuilder()
+	for i, config := range configs {
+		if err := builder.Validate(config); err != nil {
+			return nil, fmt.Errorf("构建矩阵第 %d 项: %w", i+1, err)
+		}
+	}
+	names := matrixEntryNames(configs)
+	for i, name := range names {
+		if err := validateMatrixEntryName(name); err != nil {
+			return nil, fmt.Errorf("构建矩阵第 %d 项: %w", i+1, err)
+		}
+	}
+
+	workspaceDir := options.WorkspaceDir
+	if isolate {
+		if workspaceDir == "" {
+			dir, err := os.MkdirTemp("", "flutterbuilder-matrix-")
+			if err != nil {
+				return nil, fmt.Errorf("创建工作区目录失败: %w", err)
+			}
+			workspaceDir = dir
+		} else if err := os.MkdirAll(workspaceDir, 0755); err != nil {
+			return nil, fmt.Errorf("创建工作区目录失败: %w", err)
+		}
+	}
+
+	startTime := time.Now()
+	result := &MatrixResult{Entries: make([]*MatrixEntryResult, len(configs))}
+
+	// 未隔离时同一源代码目录的构建串行执行
+	var locksMu sync.Mutex
+	sourceLocks := make(map[string]*sync.Mutex)
+	lockSource := func(path string) *sync.Mutex {
+		locksMu.Lock()
+		defer locksMu.Unlock()
+		key := filepath.Clean(path)
+		if sourceLocks[key] == nil {
+			sourceLocks[key] = &sync.Mutex{}
+		}
+		return sourceLocks[key]
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+	for i, config := range configs {
+		entry := &MatrixEntryResult{Name: names[i], Workspace: config.SourcePath}
+		result.Entries[i] = entry
+
+		wg.Add(1)
+		go func(config *BuildConfig, entry *MatrixEntryResult) {
+			defer wg.Done()
+			defer func() {
+				// 未开始构建（取消或工作区创建失败）时同样提供结果
+				if entry.Result == nil {
+					entry.Result = &BuildResult{Platform: config.Platform, BuildID: entry.Name, Error: entry.Error}
+				}
+			}()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				entry.Error = fmt.Errorf("构建已取消: %w", ctx.Err())
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				entry.Error = fmt.Errorf("构建已取消: %w", err)
+				return
+			}
+
+			entryConfig := *config
+			entryConfig.BuildID = entry.Name
+
+			if isolate {
+				workspace, err := copyWorkspace(config.SourcePath, workspaceDir, entry.Name)
+				if err != nil {
+					entry.Error = fmt.Errorf("创建工作区失败: %w", err)
+					return
+				}
+				entry.Workspace = workspace
+				entryConfig.SourcePath = workspace
+			} else {
+				lock := lockSource(config.SourcePath)
+				lock.Lock()
+				defer lock.Unlock()
+			}
+
+			entry.Result, entry.Error = builder.BuildContext(ctx, &entryConfig)
+		}(config, entry)
+	}
+	wg.Wait()
+
+	result.Duration = time.Since(startTime)
+	result.Success = result.Failed() == 0
+	if !result.Success {
+		var failed []string
+		for _, entry := range result.Entries {
+			if entry.Error != nil {
+				failed = append(failed, entry.Name)
+			}
+		}
+		return result, fmt.Errorf("构建矩阵中 %d/%d 项失败: %s", len(failed), len(configs), strings.Join(failed, ", "))
+	}
+	return result, nil
+}
+
+// matrixEntryNames 生成各项名称：优先使用 BuildID，否则为 <平台>[-<flavor>][-<mode>]，重名时追加序号
+func matrixEntryNames(configs []*BuildConfig) []string {
+	names := make([]string, len(configs))
+	counts := make(map[string]int)
+	for i, config := range configs {
+		name := config.BuildID
+		if name == "" {
+			parts := []string{string(config.Platform)}
+			if flavor := getFlavor(config); flavor != "" {
+				parts = append(parts, flavor)
+			}
+			if config.BuildMode != "" || config.CustomArgs["build_mode"] != nil {
+				parts = append(parts, string(getBuildMode(config)))
+			}
+			name = strings.Join(parts, "-")
+		}
+		counts[name]++
+		if counts[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, counts[name])
+		}
+		names[i] = name
+	}
+	return names
+}
+
+// validateMatrixEntryName 检查名称可以作为工作区目录名：名称用于 <WorkspaceDir>/<名称>，
+// 创建工作区前会删除该目录，包含路径分隔符或为 .、.. 时可能删除工作区根目录之外的文件
+func validateMatrixEntryName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("名称 %q 无效（BuildID 或 matrix 的 name 不能为空、.、.. 或包含路径分隔符）", name)
+	}
+	return nil
+}
+
+// copyWorkspace 将项目复制到隔离工作区 <root>/<name>（跳过顶层 build、.dart_tool 目录，保留文件权限和符号链接），返回工作区路径
+func copyWorkspace(src, root, name string) (string, error) {
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return "", err
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	dst := filepath.Join(root, name)
+	// 删除旧工作区前确认其位于工作区根目录下
+	if filepath.Dir(dst) != root {
+		return "", fmt.Errorf("工作区 %s 不在 %s 下", dst, root)
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return "", err
+	}
+
+	return dst, fsutil.CopyTree(src, dst, func(path, rel string, info os.FileInfo) error {
+		// 工作区根目录位于项目目录内时不复制（其中有本项和其他项正在构建的工作区）
+		if info.IsDir() && (workspaceSkipDirs[rel] || path == root) {
+			if rel == "." {
+				return fmt.Errorf("工作区根目录不能是项目目录: %s", root)
+			}
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
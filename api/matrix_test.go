@@ -0,0 +1,156 @@
+//go:build !windows
+
+package api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestBuildMatrixIsolation 同一项目的多项并发构建在各自的工作区副本中执行
+func TestBuildMatrixIsolation(t *testing.T) {
+	setupFakeFlutter(t)
+
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "pubspec.yaml"), []byte("name: app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// 旧的构建产物不应复制到工作区
+	if err := os.MkdirAll(filepath.Join(project, "build", "old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var configs []*BuildConfig
+	for _, flavor := range []string{"dev", "prod", "staging"} {
+		configs = append(configs, &BuildConfig{
+			Platform:         PlatformAPK,
+			SourcePath:       project,
+			Flavor:           flavor,
+			Logger:           &recordingLogger{},
+			ValidationConfig: DisableValidationConfig(),
+		})
+	}
+
+	workspaceDir := t.TempDir()
+	result, err := BuildMatrix(context.Background(), configs, &MatrixOptions{Concurrency: 3, WorkspaceDir: workspaceDir})
+	if err != nil {
+		t.Fatalf("构建矩阵失败: %v", err)
+	}
+	if !result.Success || len(result.Entries) != 3 {
+		t.Fatalf("汇总结果错误: %+v", result)
+	}
+
+	for i, flavor := range []string{"dev", "prod", "staging"} {
+		entry := result.Entries[i]
+		if entry.Name != "apk-"+flavor || entry.Result.BuildID != entry.Name {
+			t.Errorf("第 %d 项名称错误: %s / %s", i, entry.Name, entry.Result.BuildID)
+		}
+		if entry.Workspace != filepath.Join(workspaceDir, entry.Name) {
+			t.Errorf("第 %d 项工作区错误: %s", i, entry.Workspace)
+		}
+		if _, err := os.Stat(filepath.Join(entry.Workspace, "pubspec.yaml")); err != nil {
+			t.Errorf("工作区缺少项目文件: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(entry.Workspace, "build", "old")); !os.IsNotExist(err) {
+			t.Error("工作区不应包含旧的构建产物")
+		}
+
+		content, err := os.ReadFile(filepath.Join(entry.Workspace, "build", "app", "outputs", "flutter-apk", "app-release.apk"))
+		if err != nil {
+			t.Errorf("第 %d 项未生成APK: %v", i, err)
+			continue
+		}
+		if got := strings.TrimSpace(string(content)); got != entry.Workspace {
+			t.Errorf("第 %d 项在错误的目录构建: %s", i, got)
+		}
+		if !strings.HasPrefix(entry.Result.OutputPath, entry.Workspace) {
+			t.Errorf("第 %d 项输出路径应位于工作区: %s", i, entry.Result.OutputPath)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(project, "build", "app")); !os.IsNotExist(err) {
+		t.Error("隔离构建不应修改源代码目录")
+	}
+
+	// 工作区根目录位于项目目录内时，各项都不复制工作区根目录（包括其他项的工作区）
+	innerDir := filepath.Join(project, ".workspaces")
+	result, err = BuildMatrix(context.Background(), configs[:2], &MatrixOptions{Concurrency: 2, WorkspaceDir: innerDir})
+	if err != nil {
+		t.Fatalf("构建矩阵失败: %v", err)
+	}
+	for _, entry := range result.Entries {
+		if _, err := os.Stat(filepath.Join(entry.Workspace, ".workspaces")); !os.IsNotExist(err) {
+			t.Errorf("%s 的工作区不应包含工作区根目录", entry.Name)
+		}
+	}
+}
+
+// TestBuildMatrixFailures 单项失败不影响其他项，并返回汇总错误
+func TestBuildMatrixFailures(t *testing.T) {
+	setupFakeFlutter(t)
+
+	project := t.TempDir()
+	configs := []*BuildConfig{
+		{Platform: PlatformAPK, SourcePath: project, BuildID: "ok", Logger: &recordingLogger{}, ValidationConfig: DisableValidationConfig()},
+		{Platform: PlatformAPK, SourcePath: filepath.Join(project, "missing"), BuildID: "missing", Logger: &recordingLogger{}},
+	}
+
+	result, err := BuildMatrix(context.Background(), configs, &MatrixOptions{DisableIsolation: true})
+	if err == nil || !strings.Contains(err.Error(), "1/2") || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("期望汇总错误，实际 %v", err)
+	}
+	if result.Success || result.Failed() != 1 {
+		t.Errorf("汇总结果错误: success=%v failed=%d", result.Success, result.Failed())
+	}
+	if result.Entries[0].Error != nil || result.Entries[0].Workspace != project {
+		t.Errorf("未隔离时应在源代码目录构建成功: %+v", result.Entries[0])
+	}
+
+	report := NewMatrixReport(result, err)
+	if report.Success || len(report.Entries) != 2 || report.Entries[1].Build.Error == "" {
+		t.Errorf("汇总报告错误: %+v", report)
+	}
+
+	if _, err := BuildMatrix(context.Background(), nil, nil); err == nil {
+		t.Error("空矩阵应返回错误")
+	}
+}
+
+// TestBuildMatrixEntryNames 名称用作工作区目录名，不能指向工作区根目录之外
+func TestBuildMatrixEntryNames(t *testing.T) {
+	setupFakeFlutter(t)
+
+	root := t.TempDir()
+	victim := filepath.Join(root, "victim", "important.txt")
+	if err := os.MkdirAll(filepath.Dir(victim), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(victim, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	project := t.TempDir()
+	for _, name := range []string{"../victim", "..", "a/b", `a\b`} {
+		configs := []*BuildConfig{
+			{Platform: PlatformAPK, SourcePath: project, BuildID: name, Logger: &recordingLogger{}, ValidationConfig: DisableValidationConfig()},
+			{Platform: PlatformAAB, SourcePath: project, Logger: &recordingLogger{}, ValidationConfig: DisableValidationConfig()},
+		}
+		_, err := BuildMatrix(context.Background(), configs, &MatrixOptions{Concurrency: 2, WorkspaceDir: filepath.Join(root, "ws")})
+		if err == nil || !strings.Contains(err.Error(), "名称") {
+			t.Errorf("名称 %q 应被拒绝，实际 %v", name, err)
+		}
+	}
+	if content, err := os.ReadFile(victim); err != nil || string(content) != "keep" {
+		t.Fatalf("工作区根目录之外的文件被删除: %v", err)
+	}
+
+	if _, err := copyWorkspace(project, filepath.Join(root, "ws"), "../victim"); err == nil {
+		t.Error("工作区不在根目录下时应返回错误")
+	}
+	if _, err := os.Stat(victim); err != nil {
+		t.Errorf("工作区根目录之外的文件被删除: %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/mimicode/flutterbuilder/pkg/diagnosis"
+)
+
+// ReportSchemaVersion 构建报告的 JSON 结构版本，字段发生不兼容变化时递增
+const ReportSchemaVersion = 1
+
+// BuildReport 机器可读的构建报告（CLI --output json 的输出）
+// 所有字段始终输出，错误以字符串表示
+type BuildReport struct {
+	SchemaVersion int               `json:"schema_version"`
+	BuildID       string            `json:"build_id"`
+	Success       bool              `json:"success"`
+	Platform      string            `json:"platform"`
+	OutputPath    string            `json:"output_path"`
+	ArtifactSize  int64             `json:"artifact_size"`
+	BuildTimeMs   int64             `json:"build_time_ms"`
+	BuildTime     string            `json:"build_time"`
+	Verified      bool              `json:"verified"`
+	Error         string            `json:"error"`
+	Artifacts     []ReportArtifact  `json:"artifacts"`
+	Validation    *ReportValidation `json:"validation"`
+	Stages        []ReportStage     `json:"stages"`
+	Diagnoses     []ReportDiagnosis `json:"diagnoses"`
+	ChecksumFiles []string          `json:"checksum_files"`
+	OutputFiles   []string          `json:"output_files"`
+	PublishedURLs []string          `json:"published_urls"`
+}
+
+// ReportDiagnosis 失败原因诊断
+type ReportDiagnosis struct {
+	Code       string `json:"code"`
+	Summary    string `json:"summary"`
+	Suggestion string `json:"suggestion"`
+	Excerpt    string `json:"excerpt"`
+}
+
+// ReportStage 阶段执行记录
+type ReportStage struct {
+	Stage      string          `json:"stage"`
+	Status     string          `json:"status"`
+	Reason     string          `json:"reason"`
+	DurationMs int64           `json:"duration_ms"`
+	LogFile    string          `json:"log_file"`
+	OutputTail []string        `json:"output_tail"`
+	Attempts   []ReportAttempt `json:"attempts"`
+}
+
+// ReportAttempt 设置了重试策略的阶段中一次命令执行的记录
+type ReportAttempt struct {
+	Attempt    int      `json:"attempt"`
+	Command    []string `json:"command"`
+	Success    bool     `json:"success"`
+	Error      string   `json:"error"`
+	DurationMs int64    `json:"duration_ms"`
+}
+
+// ReportArtifact 产物文件信息（目录产物和调试信息目录的校验和为树哈希）
+type ReportArtifact struct {
+	ABI       string           `json:"abi"`
+	Path      string           `json:"path"`
+	Size      int64            `json:"size"`
+	SHA256    string           `json:"sha256"`
+	SHA512    string           `json:"sha512"`
+	Dir       bool             `json:"dir"`
+	DebugInfo bool             `json:"debug_info"`
+	Manifest  *AndroidManifest `json:"manifest,omitempty"` // APK清单信息
+}
+
+// ReportValidation 产物验证结果
+type ReportValidation struct {
+	Success      bool             `json:"success"`
+	ArtifactPath string           `json:"artifact_path"`
+	FileSize     int64            `json:"file_size"`
+	Error        string           `json:"error"`
+	Details      []ReportDetail   `json:"details"`
+	Manifest     *AndroidManifest `json:"manifest"` // APK清单信息（非APK或无法解析时为null）
+}
+
+// ReportDetail 单项验证详情
+type ReportDetail struct {
+	Check    string `json:"check"`
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	Critical bool   `json:"critical"`
+}
+
+// NewBuildReport 由构建结果生成构建报告，result 为nil时生成仅包含错误的失败报告
+func NewBuildReport(result *BuildResult, err error) *BuildReport {
+	report := &BuildReport{
+		SchemaVersion: ReportSchemaVersion,
+		Artifacts:     []ReportArtifact{},
+		Stages:        []ReportStage{},
+		Diagnoses:     []ReportDiagnosis{},
+		ChecksumFiles: []string{},
+		OutputFiles:   []string{},
+		PublishedURLs: []string{},
+	}
+	if result != nil {
+		report.BuildID = result.BuildID
+		report.Success = result.Success
+		report.Platform = string(result.Platform)
+		report.OutputPath = result.OutputPath
+		report.ArtifactSize = result.ArtifactSize
+		report.BuildTimeMs = result.BuildTime.Milliseconds()
+		report.BuildTime = result.BuildTime.String()
+		report.Verified = result.Verified
+		if err == nil {
+			err = result.Error
+		}
+
+		for _, file := range result.Artifacts {
+			report.Artifacts = append(report.Artifacts, ReportArtifact{
+				ABI:       file.ABI,
+				Path:      file.Path,
+				Size:      file.Size,
+				SHA256:    file.Checksum,
+				SHA512:    file.SHA512,
+				Dir:       file.Dir,
+				DebugInfo: file.DebugInfo,
+				Manifest:  file.Manifest,
+			})
+		}
+		report.ChecksumFiles = append(report.ChecksumFiles, result.ChecksumFiles...)
+		report.OutputFiles = append(report.OutputFiles, result.OutputFiles...)
+		report.PublishedURLs = append(report.PublishedURLs, result.PublishedURLs...)
+
+		for _, stage := range result.Stages {
+			reportStage := ReportStage{
+				Stage:      string(stage.Stage),
+				Status:     string(stage.Status),
+				Reason:     stage.Reason,
+				DurationMs: stage.Duration.Milliseconds(),
+				LogFile:    stage.LogFile,
+				OutputTail: append([]string{}, stage.OutputTail...),
+				Attempts:   []ReportAttempt{},
+			}
+			for _, attempt := range stage.Attempts {
+				reportStage.Attempts = append(reportStage.Attempts, ReportAttempt{
+					Attempt:    attempt.Attempt,
+					Command:    attempt.Command,
+					Success:    attempt.Success,
+					Error:      errorString(attempt.Error),
+					DurationMs: attempt.Duration.Milliseconds(),
+				})
+			}
+			report.Stages = append(report.Stages, reportStage)
+		}
+
+		if validation := result.ValidationResult; validation != nil {
+			report.Validation = &ReportValidation{
+				Success:      validation.Success,
+				ArtifactPath: validation.ArtifactPath,
+				FileSize:     validation.FileSize,
+				Error:        errorString(validation.Error),
+				Details:      []ReportDetail{},
+				Manifest:     validation.Manifest,
+			}
+			for _, detail := range validation.ValidationDetails {
+				report.Validation.Details = append(report.Validation.Details, ReportDetail{
+					Check:    detail.Check,
+					Status:   detail.Status,
+					Message:  detail.Message,
+					Critical: detail.Critical,
+				})
+			}
+		}
+	}
+
+	if err != nil {
+		report.Success = false
+		report.Error = err.Error()
+	}
+
+	diagnoses := diagnosis.FromError(err)
+	if result != nil && len(result.Diagnoses) > 0 {
+		diagnoses = result.Diagnoses
+	}
+	for _, d := range diagnoses {
+		report.Diagnoses = append(report.Diagnoses, ReportDiagnosis{
+			Code:       d.Code,
+			Summary:    d.Summary,
+			Suggestion: d.Suggestion,
+			Excerpt:    d.Excerpt,
+		})
+	}
+	return report
+}
+
+// Write 以缩进的 JSON 文档写出构建报告
+func (r *BuildReport) Write(w io.Writer) error {
+	return writeJSON(w, r)
+}
+
+// MatrixReport 构建矩阵的汇总报告（matrix 命令 --output json 的输出）
+type MatrixReport struct {
+	SchemaVersion int                  `json:"schema_version"`
+	Success       bool                 `json:"success"`
+	DurationMs    int64                `json:"duration_ms"`
+	Duration      string               `json:"duration"`
+	Error         string               `json:"error"`
+	Entries       []*MatrixReportEntry `json:"entries"`
+}
+
+// MatrixReportEntry 构建矩阵中单项的报告
+type MatrixReportEntry struct {
+	Name      string       `json:"name"`
+	Workspace string       `json:"workspace"`
+	Build     *BuildReport `json:"build"`
+}
+
+// NewMatrixReport 由构建矩阵结果生成汇总报告，result 为nil时生成仅包含错误的失败报告
+func NewMatrixReport(result *MatrixResult, err error) *MatrixReport {
+	report := &MatrixReport{
+		SchemaVersion: ReportSchemaVersion,
+		Entries:       []*MatrixReportEntry{},
+	}
+	if result != nil {
+		report.Success = result.Success
+		report.DurationMs = result.Duration.Milliseconds()
+		report.Duration = result.Duration.String()
+		for _, entry := range result.Entries {
+			report.Entries = append(report.Entries, &MatrixReportEntry{
+				Name:      entry.Name,
+				Workspace: entry.Workspace,
+				Build:     NewBuildReport(entry.Result, entry.Error),
+			})
+		}
+	}
+
+	if err != nil {
+		report.Success = false
+		report.Error = err.Error()
+	}
+	return report
+}
+
+// Write 以缩进的 JSON 文档写出汇总报告
+func (r *MatrixReport) Write(w io.Writer) error {
+	return writeJSON(w, r)
+}
+
+// writeJSON 以缩进格式写出 JSON 文档
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// errorString 将错误转换为字符串，nil 转换为空字符串
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+	"github.com/mimicode/flutterbuilder/pkg/diagnosis"
+)
+
+// TestBuildReport 测试构建报告的 JSON 结构
+func TestBuildReport(t *testing.T) {
+	result := &BuildResult{
+		Success:      false,
+		Platform:     PlatformAPK,
+		BuildTime:    1500 * time.Millisecond,
+		OutputPath:   "/app/build/app/outputs/flutter-apk/app-release.apk",
+		Error:        errors.New("产物验证失败: 文件过小"),
+		ArtifactSize: 1024,
+		Artifacts:    []Artifact{{Path: "/app/app-release.apk", Size: 1024, Checksum: "abc"}},
+		BuildID:      "apk-test",
+		ValidationResult: &artifact.ValidationResult{
+			Success:      false,
+			ArtifactPath: "/app/app-release.apk",
+			FileSize:     1024,
+			Error:        errors.New("文件过小"),
+			ValidationDetails: []artifact.ValidationDetail{
+				{Check: "文件大小", Status: "failed", Message: "小于最小限制", Critical: true},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewBuildReport(result, nil).Write(&buf); err != nil {
+		t.Fatalf("写出报告失败: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("报告不是合法的JSON: %v", err)
+	}
+	if decoded["schema_version"] != float64(ReportSchemaVersion) || decoded["build_id"] != "apk-test" {
+		t.Errorf("报告头部字段错误: %v", decoded)
+	}
+	if decoded["success"] != false || decoded["error"] != "产物验证失败: 文件过小" || decoded["build_time_ms"] != float64(1500) {
+		t.Errorf("结果字段错误: %v", decoded)
+	}
+	if artifacts := decoded["artifacts"].([]interface{}); len(artifacts) != 1 || artifacts[0].(map[string]interface{})["sha256"] != "abc" {
+		t.Errorf("产物字段错误: %v", decoded["artifacts"])
+	}
+	validation := decoded["validation"].(map[string]interface{})
+	if validation["error"] != "文件过小" {
+		t.Errorf("验证错误应序列化为字符串: %v", validation["error"])
+	}
+	details := validation["details"].([]interface{})
+	if detail := details[0].(map[string]interface{}); detail["check"] != "文件大小" || detail["critical"] != true {
+		t.Errorf("验证详情错误: %v", detail)
+	}
+}
+
+// TestBuildReportWithoutResult 测试构建开始前失败时的报告
+func TestBuildReportWithoutResult(t *testing.T) {
+	report := NewBuildReport(nil, errors.New("源代码路径不存在"))
+	if report.Success || report.Error != "源代码路径不存在" || report.SchemaVersion != ReportSchemaVersion {
+		t.Errorf("失败报告错误: %+v", report)
+	}
+	if report.Artifacts == nil {
+		t.Error("artifacts 应输出为空数组而不是 null")
+	}
+}
+
+// TestBuildReportDiagnoses 测试报告中的失败诊断
+func TestBuildReportDiagnoses(t *testing.T) {
+	err := diagnosis.WrapError(errors.New("命令执行失败"), diagnosis.Diagnose([]string{"version solving failed"}))
+	report := NewBuildReport(&BuildResult{Error: err, Diagnoses: diagnosis.FromError(err)}, err)
+	if len(report.Diagnoses) != 1 || report.Diagnoses[0].Code != diagnosis.CodePubVersionSolvingFailure {
+		t.Errorf("报告应包含诊断: %+v", report.Diagnoses)
+	}
+
+	if report := NewBuildReport(nil, err); len(report.Diagnoses) != 1 {
+		t.Errorf("没有构建结果时应从错误中取出诊断: %+v", report.Diagnoses)
+	}
+	if report := NewBuildReport(nil, errors.New("x")); report.Diagnoses == nil {
+		t.Error("diagnoses 应输出为空数组而不是 null")
+	}
+}
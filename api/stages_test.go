@@ -0,0 +1,76 @@
+//go:build !windows
+
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mimicode/flutterbuilder/pkg/hooks"
+)
+
+// TestBuildResumeFrom 从 build 阶段继续时，之前阶段及其钩子均被跳过并记录在结果中
+func TestBuildResumeFrom(t *testing.T) {
+	setupFakeFlutter(t)
+
+	project := t.TempDir()
+	// 模拟的 dart 命令总是失败，执行到该钩子即构建失败
+	if err := os.WriteFile(filepath.Join(project, "fail.dart"), []byte("void main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hooksConfig := &hooks.HooksConfig{Hooks: map[hooks.HookType][]*hooks.HookConfig{
+		hooks.HookPreClean: {{ScriptPath: "fail.dart"}},
+	}}
+
+	config := &BuildConfig{
+		Platform:         PlatformAPK,
+		SourcePath:       project,
+		Logger:           &recordingLogger{},
+		HooksConfig:      hooksConfig,
+		ValidationConfig: DisableValidationConfig(),
+		ResumeFrom:       StageBuild,
+		SkipStages:       []Stage{StagePostProcess},
+	}
+	result, err := NewFlutterBuilder().Build(config)
+	if err != nil {
+		t.Fatalf("跳过的阶段的钩子不应执行: %v", err)
+	}
+
+	want := map[Stage]string{
+		StageClean:         "skipped",
+		StageGetDeps:       "skipped",
+		StageCodeGen:       "skipped",
+		StageSecurityCheck: "skipped",
+		StageBuild:         "success",
+		StagePostProcess:   "skipped",
+	}
+	if len(result.Stages) != len(want) {
+		t.Fatalf("阶段记录数量错误: %+v", result.Stages)
+	}
+	for _, stage := range result.Stages {
+		if string(stage.Status) != want[stage.Stage] {
+			t.Errorf("阶段 %s 状态期望 %s，实际 %s", stage.Stage, want[stage.Stage], stage.Status)
+		}
+		if stage.Status == "skipped" && stage.Reason == "" {
+			t.Errorf("阶段 %s 缺少跳过原因", stage.Stage)
+		}
+	}
+
+	// 执行 clean 阶段时钩子失败
+	config.ResumeFrom = ""
+	config.SkipStages = nil
+	config.OnlyStages = []Stage{StageClean}
+	result, err = NewFlutterBuilder().Build(config)
+	if err == nil {
+		t.Fatal("执行 clean 阶段时应执行失败的钩子")
+	}
+	if len(result.Stages) != 1 || result.Stages[0].Status != "failed" {
+		t.Errorf("失败阶段记录错误: %+v", result.Stages)
+	}
+
+	config.OnlyStages = []Stage{"sign"}
+	if _, err := NewFlutterBuilder().Build(config); err == nil {
+		t.Error("未知阶段应返回错误")
+	}
+}
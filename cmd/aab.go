@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+
+	"github.com/spf13/cobra"
+)
+
+var aabCmd = &cobra.Command{
+	Use:   "aab",
+	Short: "构建Android App Bundle发布版本",
+	Long: `构建Android App Bundle (AAB) 发布版本
+
+用于上传到Google Play，包含以下功能:
+- 代码混淆和优化
+- 调试信息分离
+- Tree Shaking优化
+- 仅ARM64架构支持
+- 安全配置检查
+- Bundle结构验证`,
+	RunE: runAABBuild,
+}
+
+func NewAABCommand() *cobra.Command {
+	return aabCmd
+}
+
+func runAABBuild(cmd *cobra.Command, args []string) error {
+	logger.Header("FFXApp Android App Bundle Build")
+
+	// 根据构建配置文件和命令行参数创建构建配置
+	buildConfig, _, err := newBuildConfig(cmd, "aab")
+	if err != nil {
+		return err
+	}
+
+	// 执行构建流程
+	return runBuild(cmd, buildConfig, "AAB构建失败")
+}
@@ -1,14 +1,16 @@
 package cmd
 
 import (
-	"fmt"
-
-	"github.com/mimicode/flutterbuilder/pkg/builder"
 	"github.com/mimicode/flutterbuilder/pkg/logger"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	// APK构建相关参数
+	splitPerABI bool
+)
+
 var apkCmd = &cobra.Command{
 	Use:   "apk",
 	Short: "构建Android APK发布版本",
@@ -18,28 +20,31 @@ var apkCmd = &cobra.Command{
 - 代码混淆和优化
 - 调试信息分离
 - Tree Shaking优化
-- 仅ARM64架构支持
+- 仅ARM64架构支持（可通过 --split-per-abi 按ABI拆分构建）
 - 安全配置检查`,
 	RunE: runAPKBuild,
 }
 
 func NewAPKCommand() *cobra.Command {
+	// 添加APK相关标志
+	apkCmd.Flags().BoolVar(&splitPerABI, "split-per-abi", false, "按ABI拆分构建多个APK (app-<abi>-release.apk)")
+
 	return apkCmd
 }
 
 func runAPKBuild(cmd *cobra.Command, args []string) error {
 	logger.Header("FFXApp Android APK Build")
 
-	// 获取源代码路径
-	sourcePath, _ := cmd.Flags().GetString("source-path")
-
-	// 创建构建器
-	builder := builder.NewFlutterBuilder("apk", nil, sourcePath)
+	// 根据构建配置文件和命令行参数创建构建配置
+	buildConfig, _, err := newBuildConfig(cmd, "apk")
+	if err != nil {
+		return err
+	}
 
-	// 执行构建流程
-	if err := builder.Run(); err != nil {
-		return fmt.Errorf("APK构建失败: %w", err)
+	if splitPerABI || buildConfig.CustomArgs["split_per_abi"] == true {
+		buildConfig.SplitPerABI = true
 	}
 
-	return nil
+	// 执行构建流程
+	return runBuild(cmd, buildConfig, "APK构建失败")
 }
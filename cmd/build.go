@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mimicode/flutterbuilder/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+var buildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "按构建配置文件中的平台构建",
+	Long: `按构建配置文件（--config）中 platform 指定的平台构建
+
+配置文件支持 YAML/JSON，可描述平台、自定义参数、dart defines、钩子、
+产物验证和iOS签名，并支持环境变量展开和按平台覆盖，例如:
+  flutter-builder build --config flutterbuilder.yaml`,
+	RunE: runFileBuild,
+}
+
+func NewBuildCommand() *cobra.Command {
+	return buildCmd
+}
+
+func runFileBuild(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath == "" {
+		return fmt.Errorf("build 命令需要指定构建配置文件: --config")
+	}
+
+	file, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+
+	switch file.Platform {
+	case "apk":
+		return runAPKBuild(cmd, args)
+	case "aab":
+		return runAABBuild(cmd, args)
+	case "ios":
+		return runIOSBuild(cmd, args)
+	case "web":
+		return runWebBuild(cmd, args)
+	case "linux":
+		return runLinuxBuild(cmd, args)
+	case "":
+		return fmt.Errorf("配置文件 %s 未设置 platform", configPath)
+	default:
+		return fmt.Errorf("不支持的平台: %s", file.Platform)
+	}
+}
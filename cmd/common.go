@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/api"
+	"github.com/mimicode/flutterbuilder/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+// loadBuildFile 加载 --config 指定的构建配置文件，返回指定平台合并后的设置和项目路径
+// 未指定 --config 时设置为nil；--source-path 优先于配置文件中的 source_path
+func loadBuildFile(cmd *cobra.Command, platform string) (*config.Settings, string, error) {
+	file, sourcePath, err := loadConfigFile(cmd)
+	if err != nil || file == nil {
+		return nil, sourcePath, err
+	}
+
+	settings, err := file.Resolve(platform)
+	if err != nil {
+		return nil, "", err
+	}
+	return settings, sourcePath, nil
+}
+
+// loadConfigFile 加载 --config 指定的构建配置文件并确定项目路径，未指定 --config 时配置文件为nil
+func loadConfigFile(cmd *cobra.Command) (*config.File, string, error) {
+	sourcePath, _ := cmd.Flags().GetString("source-path")
+
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath == "" {
+		return nil, sourcePath, nil
+	}
+
+	file, err := config.Load(configPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if sourcePath == "" {
+		sourcePath = file.ResolvedSourcePath()
+		if sourcePath == "" {
+			return nil, "", fmt.Errorf("必须提供源代码路径参数: --source-path（或在配置文件中设置 source_path）")
+		}
+		if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+			return nil, "", fmt.Errorf("源代码路径不存在: %s", sourcePath)
+		}
+	}
+
+	return file, sourcePath, nil
+}
+
+// newBuildConfig 根据构建配置文件和全局命令行参数创建指定平台的构建配置
+// 平台专用参数（如 --split-per-abi、iOS证书）由各子命令继续设置
+func newBuildConfig(cmd *cobra.Command, platform string) (*api.BuildConfig, *config.Settings, error) {
+	settings, sourcePath, err := loadBuildFile(cmd, platform)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	buildConfig, err := buildConfigFromSettings(cmd, platform, sourcePath, settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	return buildConfig, settings, nil
+}
+
+// buildConfigFromSettings 根据已解析的配置文件设置（可为nil）和全局命令行参数创建构建配置
+func buildConfigFromSettings(cmd *cobra.Command, platform, sourcePath string, settings *config.Settings) (*api.BuildConfig, error) {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	buildConfig := &api.BuildConfig{
+		Platform:   api.Platform(platform),
+		SourcePath: sourcePath,
+		CustomArgs: make(map[string]interface{}),
+		Verbose:    verbose,
+	}
+	applyBuildFile(buildConfig, settings)
+
+	// 阶段选择
+	skipStages, _ := cmd.Flags().GetStringSlice("skip-stage")
+	onlyStages, _ := cmd.Flags().GetStringSlice("only-stage")
+	resumeFrom, _ := cmd.Flags().GetString("resume-from")
+	for _, stage := range skipStages {
+		buildConfig.SkipStages = append(buildConfig.SkipStages, api.Stage(stage))
+	}
+	for _, stage := range onlyStages {
+		buildConfig.OnlyStages = append(buildConfig.OnlyStages, api.Stage(stage))
+	}
+	buildConfig.ResumeFrom = api.Stage(resumeFrom)
+	buildConfig.Incremental, _ = cmd.Flags().GetBool("incremental")
+	if err := applyRetryFlags(cmd, buildConfig); err != nil {
+		return nil, err
+	}
+	if err := applyTimeoutFlags(cmd, buildConfig); err != nil {
+		return nil, err
+	}
+	if err := applyOutputFlags(cmd, buildConfig); err != nil {
+		return nil, err
+	}
+	if err := applyPublishFlags(cmd, buildConfig); err != nil {
+		return nil, err
+	}
+	if err := api.NewFlutterBuilder().Validate(buildConfig); err != nil {
+		return nil, err
+	}
+
+	// 产品风味、入口文件、构建模式与自定义构建参数
+	if err := applyCommonArgs(cmd, buildConfig, settings, platform); err != nil {
+		return nil, err
+	}
+	return buildConfig, nil
+}
+
+// applyBuildFile 将配置文件中的自定义参数、钩子和验证设置写入构建配置
+func applyBuildFile(buildConfig *api.BuildConfig, settings *config.Settings) {
+	if settings == nil {
+		return
+	}
+
+	for key, value := range settings.BuildCustomArgs() {
+		buildConfig.CustomArgs[key] = value
+	}
+	buildConfig.HooksConfig = settings.HooksConfig()
+	buildConfig.ValidationConfig = settings.ValidationConfig()
+
+	buildConfig.RetryPolicies = settings.RetryPolicies()
+
+	if timeouts := settings.Timeouts(); timeouts != nil {
+		buildConfig.Timeout = timeouts.Build
+		buildConfig.StageTimeouts = timeouts.Stages
+	}
+
+	buildConfig.OutputDir = settings.OutputDir()
+	buildConfig.OutputNameTemplate = settings.OutputName()
+	buildConfig.Publishers = settings.Publishers()
+}
+
+// applyRetryFlags 应用 --retry 阶段=次数，覆盖配置文件中该阶段的重试次数（保留等待时间和重试条件）
+func applyRetryFlags(cmd *cobra.Command, buildConfig *api.BuildConfig) error {
+	retries, _ := cmd.Flags().GetStringSlice("retry")
+	for _, retry := range retries {
+		stage, value, ok := strings.Cut(retry, "=")
+		attempts, err := strconv.Atoi(value)
+		if !ok || err != nil || attempts < 1 {
+			return fmt.Errorf("--retry 格式应为 阶段=次数（次数为正整数），实际: %s", retry)
+		}
+
+		if buildConfig.RetryPolicies == nil {
+			buildConfig.RetryPolicies = make(map[api.Stage]*api.RetryPolicy)
+		}
+		policy := &api.RetryPolicy{}
+		if existing := buildConfig.RetryPolicies[api.Stage(stage)]; existing != nil {
+			*policy = *existing
+		}
+		policy.Attempts = attempts
+		buildConfig.RetryPolicies[api.Stage(stage)] = policy
+	}
+	return nil
+}
+
+// applyTimeoutFlags 应用 --timeout 和 --stage-timeout 阶段=时长，覆盖配置文件中的超时设置
+func applyTimeoutFlags(cmd *cobra.Command, buildConfig *api.BuildConfig) error {
+	if cmd.Flags().Changed("timeout") {
+		buildConfig.Timeout, _ = cmd.Flags().GetDuration("timeout")
+	}
+
+	stageTimeouts, _ := cmd.Flags().GetStringSlice("stage-timeout")
+	for _, stageTimeout := range stageTimeouts {
+		stage, value, ok := strings.Cut(stageTimeout, "=")
+		timeout, err := time.ParseDuration(value)
+		if !ok || err != nil || timeout <= 0 {
+			return fmt.Errorf("--stage-timeout 格式应为 阶段=时长（如 build=30m），实际: %s", stageTimeout)
+		}
+
+		if buildConfig.StageTimeouts == nil {
+			buildConfig.StageTimeouts = make(map[api.Stage]time.Duration)
+		}
+		buildConfig.StageTimeouts[api.Stage(stage)] = timeout
+	}
+	return nil
+}
+
+// applyOutputFlags 应用 --output-dir 和 --output-name，覆盖配置文件中的产物收集设置
+func applyOutputFlags(cmd *cobra.Command, buildConfig *api.BuildConfig) error {
+	if outputDir, _ := cmd.Flags().GetString("output-dir"); outputDir != "" {
+		// 命令行中的相对路径基于当前目录
+		absDir, err := filepath.Abs(outputDir)
+		if err != nil {
+			return fmt.Errorf("无法解析输出目录: %w", err)
+		}
+		buildConfig.OutputDir = absDir
+	}
+	if outputName, _ := cmd.Flags().GetString("output-name"); outputName != "" {
+		buildConfig.OutputNameTemplate = outputName
+	}
+	return nil
+}
+
+// applyPublishFlags 应用 --publish-dir 和 --publish-s3，替换配置文件中同类的发布后端。
+// --publish-s3 沿用配置文件中对象存储的服务地址和访问凭证，只替换存储桶和路径前缀
+func applyPublishFlags(cmd *cobra.Command, buildConfig *api.BuildConfig) error {
+	if publishDir, _ := cmd.Flags().GetString("publish-dir"); publishDir != "" {
+		absDir, err := filepath.Abs(publishDir)
+		if err != nil {
+			return fmt.Errorf("无法解析发布目录: %w", err)
+		}
+		buildConfig.Publishers = replacePublisher(buildConfig.Publishers, &api.FilesystemPublisher{Dir: absDir})
+	}
+
+	target, _ := cmd.Flags().GetString("publish-s3")
+	endpoint, _ := cmd.Flags().GetString("s3-endpoint")
+	region, _ := cmd.Flags().GetString("s3-region")
+	if target == "" {
+		if endpoint != "" || region != "" {
+			return fmt.Errorf("--s3-endpoint、--s3-region 需要与 --publish-s3 一起使用")
+		}
+		return nil
+	}
+	location, ok := strings.CutPrefix(target, "s3://")
+	bucket, prefix, _ := strings.Cut(location, "/")
+	if !ok || bucket == "" {
+		return fmt.Errorf("--publish-s3 格式应为 s3://存储桶[/路径前缀]，实际: %s", target)
+	}
+
+	publisher := &api.S3Publisher{}
+	for _, existing := range buildConfig.Publishers {
+		if s3, ok := existing.(*api.S3Publisher); ok {
+			*publisher = *s3
+		}
+	}
+	publisher.Bucket, publisher.Prefix = bucket, prefix
+	if endpoint != "" {
+		publisher.Endpoint = endpoint
+	}
+	if region != "" {
+		publisher.Region = region
+	}
+	buildConfig.Publishers = replacePublisher(buildConfig.Publishers, publisher)
+	return nil
+}
+
+// replacePublisher 用 publisher 替换同类型的发布后端，没有同类型的后端时追加
+func replacePublisher(publishers []api.Publisher, publisher api.Publisher) []api.Publisher {
+	for i, existing := range publishers {
+		if reflect.TypeOf(existing) == reflect.TypeOf(publisher) {
+			replaced := append([]api.Publisher{}, publishers...)
+			replaced[i] = publisher
+			return replaced
+		}
+	}
+	return append(publishers, publisher)
+}
+
+// runBuild 执行构建；--output json 时日志写入标准错误，结束后向标准输出写出构建报告
+func runBuild(cmd *cobra.Command, buildConfig *api.BuildConfig, failure string) error {
+	jsonMode := isJSONOutput(cmd)
+	if jsonMode {
+		buildConfig.LogOutput = os.Stderr
+	}
+
+	result, err := api.NewFlutterBuilder().BuildContext(cmd.Context(), buildConfig)
+	if jsonMode {
+		if writeErr := api.NewBuildReport(result, err).Write(cmd.OutOrStdout()); writeErr != nil {
+			return fmt.Errorf("写出构建报告失败: %w", writeErr)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("%s: %w", failure, err)
+		if jsonMode {
+			return &reportedError{err: err}
+		}
+		return err
+	}
+	return nil
+}
+
+// overrideFlag 命令行参数非空时覆盖配置值
+func overrideFlag(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+// applyCommonArgs 将全局构建参数写入构建配置
+// 命令行参数优先于配置文件中的同名设置；列表参数（--dart-define、--build-arg、--remove-default-arg）与配置文件合并
+func applyCommonArgs(cmd *cobra.Command, buildConfig *api.BuildConfig, settings *config.Settings, platform string) error {
+	customArgs := buildConfig.CustomArgs
+
+	if flavor, _ := cmd.Flags().GetString("flavor"); flavor != "" {
+		customArgs["flavor"] = flavor
+	}
+	if target, _ := cmd.Flags().GetString("target"); target != "" {
+		customArgs["target"] = target
+	}
+	if mode, _ := cmd.Flags().GetString("mode"); mode != "" && cmd.Flags().Changed("mode") {
+		customArgs["build_mode"] = mode
+	}
+
+	return collectBuildFlags(cmd, settings, platform, customArgs)
+}
+
+// collectBuildFlags 解析 --dart-define、--build-arg、--remove-default-arg、--no-default-args、
+// --target-platform、--build-name、--build-number，检查冲突后写入 customArgs
+func collectBuildFlags(cmd *cobra.Command, settings *config.Settings, platform string, customArgs map[string]interface{}) error {
+	flags := cmd.Flags()
+	dartDefineFlags, _ := flags.GetStringArray("dart-define")
+	buildArgFlags, _ := flags.GetStringArray("build-arg")
+	removeArgFlags, _ := flags.GetStringArray("remove-default-arg")
+	noDefaultArgs, _ := flags.GetBool("no-default-args")
+	targetPlatform, _ := flags.GetString("target-platform")
+	buildName, _ := flags.GetString("build-name")
+	buildNumber, _ := flags.GetString("build-number")
+
+	// 配置文件中的值作为基础
+	var base map[string]interface{}
+	if settings != nil {
+		base = settings.BuildCustomArgs()
+	}
+	baseSlice := func(key string) []string {
+		values, _ := base[key].([]string)
+		return append([]string(nil), values...)
+	}
+
+	if noDefaultArgs && len(removeArgFlags) > 0 {
+		return fmt.Errorf("--no-default-args 已禁用全部默认参数，不能同时使用 --remove-default-arg")
+	}
+	if targetPlatform != "" && platform != "apk" && platform != "aab" && platform != "linux" {
+		return fmt.Errorf("--target-platform 仅支持 apk、aab、linux 平台")
+	}
+
+	// --dart-define：同名 KEY 覆盖配置文件中的值
+	if len(dartDefineFlags) > 0 {
+		defines := config.DartDefines(baseSlice("dart_defines"))
+		for _, define := range dartDefineFlags {
+			if parts := strings.SplitN(define, "=", 2); len(parts) != 2 || parts[0] == "" {
+				return fmt.Errorf("无效的 --dart-define: %s（格式应为 KEY=VALUE）", define)
+			}
+			defines = defines.Set(define)
+		}
+		customArgs["dart_defines"] = []string(defines)
+	}
+
+	// --build-arg、--build-name、--build-number：追加到 flutter_build_args
+	if len(buildArgFlags) > 0 || buildName != "" || buildNumber != "" {
+		buildArgs := append(baseSlice("flutter_build_args"), buildArgFlags...)
+		for _, arg := range buildArgs {
+			name := strings.SplitN(arg, "=", 2)[0]
+			if (name == "--build-name" && buildName != "") || (name == "--build-number" && buildNumber != "") {
+				return fmt.Errorf("%s 与 --build-arg %s 冲突，请只使用其中一个", name, arg)
+			}
+		}
+		if buildName != "" {
+			buildArgs = append(buildArgs, "--build-name="+buildName)
+		}
+		if buildNumber != "" {
+			if n, err := strconv.Atoi(buildNumber); err != nil || n < 0 {
+				return fmt.Errorf("无效的 --build-number: %s（必须是非负整数）", buildNumber)
+			}
+			buildArgs = append(buildArgs, "--build-number="+buildNumber)
+		}
+		customArgs["flutter_build_args"] = buildArgs
+	}
+
+	if len(removeArgFlags) > 0 {
+		customArgs["remove_default_args"] = append(baseSlice("remove_default_args"), removeArgFlags...)
+	}
+	if noDefaultArgs {
+		customArgs["disable_default_args"] = true
+	}
+	if targetPlatform != "" {
+		customArgs["target_platform"] = targetPlatform
+	}
+
+	return nil
+}
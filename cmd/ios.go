@@ -1,9 +1,7 @@
 package cmd
 
 import (
-	"fmt"
-
-	"github.com/mimicode/flutterbuilder/pkg/builder"
+	"github.com/mimicode/flutterbuilder/api"
 	"github.com/mimicode/flutterbuilder/pkg/logger"
 
 	"github.com/spf13/cobra"
@@ -53,25 +51,26 @@ func NewIOSCommand() *cobra.Command {
 func runIOSBuild(cmd *cobra.Command, args []string) error {
 	logger.Header("FFXApp iOS Build")
 
-	// 获取源代码路径
-	sourcePath, _ := cmd.Flags().GetString("source-path")
-
-	// 创建iOS配置
-	iosConfig := &builder.IOSConfig{
-		P12Cert:             p12Cert,
-		CertPassword:        certPassword,
-		ProvisioningProfile: provisioningProfile,
-		TeamID:              teamID,
-		BundleID:            bundleID,
+	// 根据构建配置文件和命令行参数创建构建配置
+	buildConfig, settings, err := newBuildConfig(cmd, "ios")
+	if err != nil {
+		return err
 	}
 
-	// 创建构建器
-	builder := builder.NewFlutterBuilder("ios", iosConfig, sourcePath)
-
-	// 执行构建流程
-	if err := builder.Run(); err != nil {
-		return fmt.Errorf("iOS构建失败: %w", err)
+	// 创建iOS配置（命令行参数优先于配置文件）
+	iosConfig := &api.IOSConfig{}
+	if settings != nil {
+		if fileConfig := settings.IOSConfig(); fileConfig != nil {
+			iosConfig = fileConfig
+		}
 	}
+	overrideFlag(&iosConfig.P12Cert, p12Cert)
+	overrideFlag(&iosConfig.CertPassword, certPassword)
+	overrideFlag(&iosConfig.ProvisioningProfile, provisioningProfile)
+	overrideFlag(&iosConfig.TeamID, teamID)
+	overrideFlag(&iosConfig.BundleID, bundleID)
+	buildConfig.IOSConfig = iosConfig
 
-	return nil
+	// 执行构建流程
+	return runBuild(cmd, buildConfig, "iOS构建失败")
 }
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+
+	"github.com/spf13/cobra"
+)
+
+var linuxCmd = &cobra.Command{
+	Use:   "linux",
+	Short: "构建Linux桌面发布版本",
+	Long: `构建Linux桌面发布版本
+
+包含以下功能:
+- 代码混淆和优化
+- 调试信息分离
+- Tree Shaking优化
+- bundle产物验证 (ELF可执行文件、libapp.so、libflutter_linux_gtk.so、flutter_assets)
+
+产物位于 build/linux/<arch>/release/bundle，需要在Linux环境下构建。`,
+	RunE: runLinuxBuild,
+}
+
+func NewLinuxCommand() *cobra.Command {
+	return linuxCmd
+}
+
+func runLinuxBuild(cmd *cobra.Command, args []string) error {
+	logger.Header("FFXApp Linux Build")
+
+	// 根据构建配置文件和命令行参数创建构建配置
+	buildConfig, _, err := newBuildConfig(cmd, "linux")
+	if err != nil {
+		return err
+	}
+
+	// 执行构建流程
+	return runBuild(cmd, buildConfig, "Linux构建失败")
+}
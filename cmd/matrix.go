@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mimicode/flutterbuilder/api"
+	"github.com/mimicode/flutterbuilder/pkg/config"
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// 构建矩阵相关参数
+	matrixPlatforms   []string
+	matrixFlavors     []string
+	matrixConcurrency int
+	matrixWorkspace   string
+	matrixNoIsolation bool
+)
+
+var matrixCmd = &cobra.Command{
+	Use:   "matrix",
+	Short: "一次构建多个平台/flavor",
+	Long: `一次构建多个平台/flavor，并输出汇总结果
+
+构建矩阵来源:
+- 配置文件（--config）中的 matrix 列表，每项在对应平台设置之上叠加
+- --platforms 与 --flavors 的组合（如 apk,aab × dev,prod 共 4 项）
+
+并发构建时每项在独立的工作区副本中执行，避免 flutter clean 相互干扰，
+产物保留在工作区中（见汇总结果）。例如:
+  flutter-builder matrix --config flutterbuilder.yaml -j 3
+  flutter-builder matrix --source-path . --platforms apk,aab --flavors dev,prod`,
+	RunE: runMatrixBuild,
+}
+
+func NewMatrixCommand() *cobra.Command {
+	// 添加构建矩阵相关标志
+	matrixCmd.Flags().StringSliceVar(&matrixPlatforms, "platforms", nil, "构建平台列表 (如 apk,aab,ios)，指定时忽略配置文件中的 matrix")
+	matrixCmd.Flags().StringSliceVar(&matrixFlavors, "flavors", nil, "产品风味列表 (如 dev,prod)，与 --platforms 组合")
+	matrixCmd.Flags().IntVarP(&matrixConcurrency, "concurrency", "j", api.DefaultMatrixConcurrency, "最大并发构建数")
+	matrixCmd.Flags().StringVar(&matrixWorkspace, "workspace-dir", "", "隔离工作区根目录 (默认在系统临时目录下创建)")
+	matrixCmd.Flags().BoolVar(&matrixNoIsolation, "no-isolation", false, "不复制工作区，直接在源代码目录构建 (同一项目的构建串行执行)")
+
+	return matrixCmd
+}
+
+func runMatrixBuild(cmd *cobra.Command, args []string) error {
+	logger.Header("FFXApp Build Matrix")
+
+	configs, err := newMatrixConfigs(cmd)
+	if err != nil {
+		return err
+	}
+
+	jsonMode := isJSONOutput(cmd)
+	if jsonMode {
+		for _, buildConfig := range configs {
+			buildConfig.LogOutput = os.Stderr
+		}
+	}
+
+	result, err := api.BuildMatrix(cmd.Context(), configs, &api.MatrixOptions{
+		Concurrency:      matrixConcurrency,
+		WorkspaceDir:     matrixWorkspace,
+		DisableIsolation: matrixNoIsolation,
+	})
+
+	if jsonMode {
+		if writeErr := api.NewMatrixReport(result, err).Write(cmd.OutOrStdout()); writeErr != nil {
+			return fmt.Errorf("写出构建报告失败: %w", writeErr)
+		}
+		if err != nil {
+			return &reportedError{err: err}
+		}
+		return nil
+	}
+
+	if result != nil {
+		printMatrixSummary(result)
+	}
+	return err
+}
+
+// newMatrixConfigs 根据 --platforms/--flavors 或配置文件中的 matrix 生成各项构建配置
+func newMatrixConfigs(cmd *cobra.Command) ([]*api.BuildConfig, error) {
+	file, sourcePath, err := loadConfigFile(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if flavor, _ := cmd.Flags().GetString("flavor"); flavor != "" && len(matrixFlavors) > 0 {
+		return nil, fmt.Errorf("--flavor 与 --flavors 不能同时使用")
+	}
+
+	var configs []*api.BuildConfig
+	add := func(platform, name string, settings *config.Settings) error {
+		buildConfig, err := buildConfigFromSettings(cmd, platform, sourcePath, settings)
+		if err != nil {
+			return fmt.Errorf("构建矩阵 %s: %w", platform, err)
+		}
+		buildConfig.BuildID = name
+		if platform == "apk" && buildConfig.CustomArgs["split_per_abi"] == true {
+			buildConfig.SplitPerABI = true
+		}
+		if platform == "ios" && settings != nil {
+			buildConfig.IOSConfig = settings.IOSConfig()
+		}
+		configs = append(configs, buildConfig)
+		return nil
+	}
+
+	switch {
+	case len(matrixPlatforms) > 0:
+		flavors := matrixFlavors
+		if len(flavors) == 0 {
+			flavors = []string{""}
+		}
+		for _, platform := range matrixPlatforms {
+			for _, flavor := range flavors {
+				settings := &config.Settings{}
+				if file != nil {
+					if settings, err = file.Resolve(platform); err != nil {
+						return nil, err
+					}
+				}
+				if flavor != "" {
+					settings.Flavor = flavor
+				}
+				if err := add(platform, "", settings); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case file != nil && len(file.Matrix) > 0:
+		for _, entry := range file.Matrix {
+			settings, err := file.ResolveEntry(entry)
+			if err != nil {
+				return nil, err
+			}
+			if err := add(entry.Platform, entry.Name, settings); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("未指定构建矩阵: 请使用 --platforms，或在配置文件中设置 matrix")
+	}
+
+	return configs, nil
+}
+
+// printMatrixSummary 输出构建矩阵汇总结果
+func printMatrixSummary(result *api.MatrixResult) {
+	logger.Header("构建矩阵结果")
+	for _, entry := range result.Entries {
+		if entry.Error != nil {
+			logger.Error("%s: %v", entry.Name, entry.Error)
+			continue
+		}
+		logger.Success("%s (%.2f秒，工作区: %s)", entry.Name, entry.Result.BuildTime.Seconds(), entry.Workspace)
+		if len(entry.Result.Artifacts) > 0 {
+			for _, file := range entry.Result.Artifacts {
+				logger.Printf("  %s (%.2f MB)\n", file.Path, float64(file.Size)/(1024*1024))
+			}
+		} else if entry.Result.OutputPath != "" {
+			logger.Printf("  %s\n", entry.Result.OutputPath)
+		}
+	}
+	if result.Success {
+		logger.Success("构建矩阵完成: %d 项，耗时 %.2f秒", len(result.Entries), result.Duration.Seconds())
+	} else {
+		logger.Warning("构建矩阵完成: %d 项，%d 项失败，耗时 %.2f秒", len(result.Entries), result.Failed(), result.Duration.Seconds())
+	}
+}
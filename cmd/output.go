@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mimicode/flutterbuilder/api"
+
+	"github.com/spf13/cobra"
+)
+
+// 输出格式（--output）
+const (
+	OutputText = "text" // 默认，彩色日志输出到标准输出
+	OutputJSON = "json" // 日志输出到标准错误，标准输出只包含一个 JSON 构建报告
+)
+
+// ValidateOutputFormat 检查 --output 取值
+func ValidateOutputFormat(format string) error {
+	switch format {
+	case OutputText, OutputJSON:
+		return nil
+	default:
+		return fmt.Errorf("无效的输出格式: %s（可选 text、json）", format)
+	}
+}
+
+// isJSONOutput 是否使用 JSON 输出模式
+func isJSONOutput(cmd *cobra.Command) bool {
+	format, _ := cmd.Flags().GetString("output")
+	return format == OutputJSON
+}
+
+// reportedError 已写出构建报告的构建错误
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string { return e.err.Error() }
+func (e *reportedError) Unwrap() error { return e.err }
+
+// WriteErrorReport JSON 输出模式下，为尚未写出构建报告的错误（如参数或配置文件错误）写出失败报告
+func WriteErrorReport(w io.Writer, err error) error {
+	var reported *reportedError
+	if errors.As(err, &reported) {
+		return nil
+	}
+	return api.NewBuildReport(nil, err).Write(w)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Web构建相关参数
+	webRenderer string
+	baseHref    string
+)
+
+var webCmd = &cobra.Command{
+	Use:   "web",
+	Short: "构建Flutter Web发布版本",
+	Long: `构建Flutter Web发布版本
+
+包含以下功能:
+- Tree Shaking优化
+- 可选Web渲染器 (canvaskit/html/skwasm)
+- 可选部署子路径 (base href)
+- build/web 产物验证 (index.html、main.dart.js、service worker、资源清单、体积预算)`,
+	RunE: runWebBuild,
+}
+
+func NewWebCommand() *cobra.Command {
+	// 添加Web相关标志
+	webCmd.Flags().StringVar(&webRenderer, "web-renderer", "", "Web渲染器 (canvaskit, html, skwasm)")
+	webCmd.Flags().StringVar(&baseHref, "base-href", "", "部署子路径，需以 / 开头和结尾 (如 /admin/)")
+
+	return webCmd
+}
+
+func runWebBuild(cmd *cobra.Command, args []string) error {
+	logger.Header("FFXApp Web Build")
+
+	// 根据构建配置文件和命令行参数创建构建配置
+	buildConfig, _, err := newBuildConfig(cmd, "web")
+	if err != nil {
+		return err
+	}
+
+	// 传递Web相关参数
+	if webRenderer != "" {
+		buildConfig.CustomArgs["web_renderer"] = webRenderer
+	}
+	if baseHref != "" {
+		buildConfig.CustomArgs["base_href"] = baseHref
+	}
+
+	// 执行构建流程
+	return runBuild(cmd, buildConfig, "Web构建失败")
+}
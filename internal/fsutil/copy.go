@@ -0,0 +1,93 @@
+// Package fsutil 提供构建输出、矩阵工作区和文件系统发布共用的文件复制工具
+package fsutil
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// CopyFile 复制单个文件到 dst（自动创建父目录），先写入同目录的临时文件再重命名，
+// 避免其他进程读到未写完的文件
+func CopyFile(src, dst string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, in); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// CopyTree 将目录 src 复制到 dst，保留文件权限和符号链接，跳过套接字、设备等特殊文件。
+// filter 不为 nil 时在复制每一项前调用（rel 为相对 src 的路径），返回 filepath.SkipDir 跳过该目录，返回其他错误则中止复制
+func CopyTree(src, dst string, filter func(path, rel string, info os.FileInfo) error) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if filter != nil {
+			if err := filter(path, rel, info); err != nil {
+				return err
+			}
+		}
+		return copyEntry(path, filepath.Join(dst, rel), info)
+	})
+}
+
+// CopyPath 复制文件或目录到 dst（已存在时先删除），保留文件权限和符号链接
+func CopyPath(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return CopyTree(src, dst, nil)
+	}
+	return copyEntry(src, dst, info)
+}
+
+// copyEntry 按类型复制目录、符号链接或普通文件
+func copyEntry(src, dst string, info os.FileInfo) error {
+	switch {
+	case info.IsDir():
+		return os.MkdirAll(dst, info.Mode().Perm()|0700)
+	case info.Mode()&os.ModeSymlink != 0:
+		link, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(link, dst)
+	case info.Mode().IsRegular():
+		return CopyFile(src, dst, info.Mode().Perm())
+	default:
+		return nil
+	}
+}
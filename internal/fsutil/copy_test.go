@@ -0,0 +1,60 @@
+//go:build !windows
+
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyPath(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "lib", "skip"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "lib", "run.sh"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "lib", "skip", "cache"), []byte("cache"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("lib/run.sh", filepath.Join(src, "run")); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "out")
+	if err := os.MkdirAll(filepath.Join(dst, "stale"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := CopyPath(src, dst); err != nil {
+		t.Fatalf("复制失败: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "lib", "run.sh")); err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("应保留文件权限: %v %v", info, err)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "run")); err != nil || link != "lib/run.sh" {
+		t.Errorf("应保留符号链接: %q %v", link, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "stale")); !os.IsNotExist(err) {
+		t.Error("复制前应删除已存在的目标")
+	}
+
+	// filter 返回 filepath.SkipDir 时跳过目录
+	tree := filepath.Join(t.TempDir(), "tree")
+	err := CopyTree(src, tree, func(path, rel string, info os.FileInfo) error {
+		if info.IsDir() && rel == filepath.Join("lib", "skip") {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("复制目录失败: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tree, "lib", "skip")); !os.IsNotExist(err) {
+		t.Error("应跳过 filter 排除的目录")
+	}
+	if data, err := os.ReadFile(filepath.Join(tree, "lib", "run.sh")); err != nil || string(data) != "#!/bin/sh\n" {
+		t.Errorf("文件内容错误: %q %v", data, err)
+	}
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/mimicode/flutterbuilder/cmd"
 	"github.com/mimicode/flutterbuilder/pkg/logger"
@@ -13,6 +16,11 @@ import (
 var (
 	verbose    bool
 	sourcePath string
+	flavor     string
+	target     string
+	buildMode  string
+	configPath string
+	output     string
 )
 
 func main() {
@@ -27,12 +35,31 @@ func main() {
 
 支持平台:
   - apk: Android APK构建
+  - aab: Android App Bundle构建（Google Play）
   - ios: iOS应用构建
+  - web: Flutter Web构建
+  - linux: Linux桌面构建
 
 使用示例:
   flutter-builder apk --source-path /path/to/flutter/project
+  flutter-builder aab --source-path /path/to/flutter/project
   flutter-builder ios --source-path /path/to/flutter/project
+  flutter-builder web --source-path /path/to/flutter/project --base-href /admin/
+  flutter-builder linux --source-path /path/to/flutter/project
   flutter-builder apk --source-path . --verbose
+  flutter-builder apk --source-path . --flavor prod --target lib/main_prod.dart
+  flutter-builder apk --source-path . --mode profile
+  flutter-builder apk --source-path . --dart-define API_URL=https://api.example.com --build-number 42
+  flutter-builder apk --config flutterbuilder.yaml
+  flutter-builder build --config flutterbuilder.yaml
+  flutter-builder matrix --source-path . --platforms apk,aab --flavors dev,prod
+  flutter-builder apk --source-path . --output json > build-report.json
+  flutter-builder ios --source-path . --resume-from build
+  flutter-builder apk --source-path . --incremental
+  flutter-builder apk --source-path . --retry get_deps=3,build=2
+  flutter-builder ios --source-path . --timeout 1h --stage-timeout build=40m
+  flutter-builder apk --source-path . --flavor prod --output-dir dist
+  flutter-builder apk --source-path . --publish-s3 s3://app-builds/shop --s3-endpoint https://minio.example.com:9000
   
   # iOS动态证书构建示例:
   flutter-builder ios --source-path /path/to/flutter/project \\
@@ -42,15 +69,26 @@ func main() {
     --team-id "TEAM123456" \\
     --bundle-id "com.company.app"`,
 		Version: "2.0.0",
-		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		PersistentPreRunE: func(c *cobra.Command, args []string) error {
+			// JSON 输出模式：标准输出只保留构建报告，日志写入标准错误
+			if err := cmd.ValidateOutputFormat(output); err != nil {
+				return err
+			}
+			if output == cmd.OutputJSON {
+				logger.SetOutput(os.Stderr, os.Stderr)
+			}
+
 			// 设置日志级别
 			if verbose {
 				logger.SetLevel(logger.DebugLevel)
 			}
 
-			// 验证必需的源代码路径参数
+			// 验证必需的源代码路径参数（使用配置文件时可由 source_path 提供）
 			if sourcePath == "" {
-				return fmt.Errorf("必须提供源代码路径参数: --source-path")
+				if configPath == "" {
+					return fmt.Errorf("必须提供源代码路径参数: --source-path")
+				}
+				return nil
 			}
 
 			// 验证路径是否存在
@@ -64,18 +102,62 @@ func main() {
 
 	// 添加全局标志
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "显示详细日志")
-	rootCmd.PersistentFlags().StringVarP(&sourcePath, "source-path", "s", "", "Flutter项目源代码路径 (必需)")
+	rootCmd.PersistentFlags().StringVarP(&sourcePath, "source-path", "s", "", "Flutter项目源代码路径 (必需，使用 --config 时可由配置文件提供)")
+	rootCmd.PersistentFlags().StringVar(&flavor, "flavor", "", "产品风味，对应 flutter build --flavor（apk/aab/ios）")
+	rootCmd.PersistentFlags().StringVarP(&target, "target", "t", "", "入口文件，如 lib/main_prod.dart")
+	rootCmd.PersistentFlags().StringVarP(&buildMode, "mode", "m", "release", "构建模式: debug、profile、release")
+	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "构建配置文件 (YAML/JSON，如 flutterbuilder.yaml)")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "text", "输出格式: text、json（json 时标准输出只包含构建报告）")
 
-	// 将源代码路径设为必需参数
-	rootCmd.MarkPersistentFlagRequired("source-path")
+	// 自定义构建参数（对应 API 的 CustomArgs）
+	rootCmd.PersistentFlags().StringArray("dart-define", nil, "添加 --dart-define=KEY=VALUE，可重复指定")
+	rootCmd.PersistentFlags().StringArray("build-arg", nil, "追加 flutter build 参数（如 --build-arg=--no-pub），可重复指定")
+	rootCmd.PersistentFlags().StringArray("remove-default-arg", nil, "移除指定默认参数（如 --obfuscate），可重复指定")
+	rootCmd.PersistentFlags().Bool("no-default-args", false, "禁用全部默认参数（混淆、调试信息分离等）")
+	rootCmd.PersistentFlags().String("target-platform", "", "目标架构（apk/aab 如 android-arm,android-arm64；linux 如 linux-arm64）")
+	rootCmd.PersistentFlags().String("build-name", "", "版本名称，对应 --build-name（如 1.2.0）")
+	rootCmd.PersistentFlags().String("build-number", "", "构建号，对应 --build-number（非负整数）")
+
+	// 阶段选择：clean、get_deps、code_gen、security_check、build、post_process
+	rootCmd.PersistentFlags().StringSlice("skip-stage", nil, "跳过的构建阶段（连同其钩子），可重复或逗号分隔，如 clean,get_deps")
+	rootCmd.PersistentFlags().StringSlice("only-stage", nil, "只执行的构建阶段，可重复或逗号分隔")
+	rootCmd.PersistentFlags().String("resume-from", "", "从指定阶段继续，跳过之前的阶段（如 build）")
+	rootCmd.PersistentFlags().Bool("incremental", false, "增量构建：依赖指纹未变化时跳过 clean、get_deps、code_gen")
+	rootCmd.PersistentFlags().StringSlice("retry", nil, "阶段命令失败时的最大执行次数，格式 阶段=次数（get_deps、code_gen、build），如 get_deps=3")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "整个构建的超时时间（如 1h），超时后终止进程并清理证书资源")
+	rootCmd.PersistentFlags().StringSlice("stage-timeout", nil, "阶段超时时间，格式 阶段=时长，如 build=40m")
+	rootCmd.PersistentFlags().String("output-dir", "", "构建成功后将产物、调试信息和构建信息复制到该目录（可使用 {version} 等占位符）")
+	rootCmd.PersistentFlags().String("output-name", "", "收集和发布产物的命名模板，默认 {app}-{version}+{build}-{flavor}-{platform}.{ext}")
+	rootCmd.PersistentFlags().String("publish-dir", "", "构建成功后将产物发布到该目录（如 NFS 挂载目录）")
+	rootCmd.PersistentFlags().String("publish-s3", "", "构建成功后将产物上传到 S3 兼容的对象存储，格式 s3://存储桶/路径前缀（凭证取自 AWS_ACCESS_KEY_ID、AWS_SECRET_ACCESS_KEY）")
+	rootCmd.PersistentFlags().String("s3-endpoint", "", "对象存储服务地址（如 https://minio.example.com:9000），默认 AWS S3")
+	rootCmd.PersistentFlags().String("s3-region", "", "对象存储区域，默认 us-east-1")
 
 	// 添加子命令
 	rootCmd.AddCommand(cmd.NewAPKCommand())
+	rootCmd.AddCommand(cmd.NewAABCommand())
 	rootCmd.AddCommand(cmd.NewIOSCommand())
+	rootCmd.AddCommand(cmd.NewWebCommand())
+	rootCmd.AddCommand(cmd.NewLinuxCommand())
+	rootCmd.AddCommand(cmd.NewBuildCommand())
+	rootCmd.AddCommand(cmd.NewMatrixCommand())
+
+	// 收到 Ctrl+C / SIGTERM 时取消构建：终止子进程树并清理证书资源
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		// 恢复默认信号处理，再次 Ctrl+C 可强制退出
+		stop()
+	}()
 
 	// 执行命令
-	if err := rootCmd.Execute(); err != nil {
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		stop()
+		if output == cmd.OutputJSON {
+			cmd.WriteErrorReport(os.Stdout, err)
+		}
 		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
 		os.Exit(1)
 	}
+	stop()
 }
@@ -0,0 +1,181 @@
+package artifact
+
+import (
+	"archive/zip"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ValidateAAB 验证Android App Bundle文件
+func (v *ArtifactValidatorImpl) ValidateAAB(aabPath string, config *ArtifactConfig) (*ValidationResult, error) {
+	var details []ValidationDetail
+	var success = true
+
+	// 1. 检查AAB文件是否存在
+	exists, fileInfo, err := v.checkFileExists(aabPath)
+	if err != nil {
+		details = append(details, ValidationDetail{
+			Check:    "AAB文件存在性检查",
+			Status:   "failed",
+			Message:  fmt.Sprintf("检查文件状态失败: %v", err),
+			Critical: true,
+		})
+		return v.createValidationResult(false, aabPath, 0, details, err), err
+	}
+
+	if !exists {
+		details = append(details, ValidationDetail{
+			Check:    "AAB文件存在性检查",
+			Status:   "failed",
+			Message:  fmt.Sprintf("AAB文件不存在: %s", aabPath),
+			Critical: true,
+		})
+		return v.createValidationResult(false, aabPath, 0, details, fmt.Errorf("AAB文件不存在")), fmt.Errorf("AAB文件不存在")
+	}
+
+	details = append(details, ValidationDetail{
+		Check:    "AAB文件存在性检查",
+		Status:   "success",
+		Message:  "AAB文件存在",
+		Critical: true,
+	})
+
+	fileSize := fileInfo.Size()
+
+	// 2. 检查文件大小
+	sizeOK, sizeMsg := v.checkFileSize(fileSize, config.MinFileSize, config.MaxFileSize)
+	if !sizeOK {
+		details = append(details, ValidationDetail{
+			Check:    "AAB文件大小检查",
+			Status:   "failed",
+			Message:  sizeMsg,
+			Critical: true,
+		})
+		success = false
+	} else {
+		details = append(details, ValidationDetail{
+			Check:    "AAB文件大小检查",
+			Status:   "success",
+			Message:  sizeMsg,
+			Critical: false,
+		})
+	}
+
+	// 3. 检查调试信息目录（非关键，仅release模式生成）
+	debugInfoPath := filepath.Join(config.SourcePath, "build", "debug-info")
+	if normalizeBuildMode(config.BuildMode) == BuildModeRelease {
+		if debugExists, _, _ := v.checkFileExists(debugInfoPath); debugExists {
+			details = append(details, ValidationDetail{
+				Check:    "调试信息目录检查",
+				Status:   "success",
+				Message:  "调试信息目录存在",
+				Critical: false,
+			})
+		} else {
+			details = append(details, ValidationDetail{
+				Check:    "调试信息目录检查",
+				Status:   "warning",
+				Message:  "调试信息目录不存在（可能未启用代码混淆）",
+				Critical: false,
+			})
+		}
+	}
+
+	// 4. AAB结构检查（如果启用）
+	if config.ValidateIntegrity || (config.ValidationConfig != nil && config.ValidationConfig.EnableIntegrityCheck) {
+		integrityOK, integrityMsg := v.validateAABIntegrity(aabPath)
+		if !integrityOK {
+			details = append(details, ValidationDetail{
+				Check:    "AAB完整性检查",
+				Status:   "failed",
+				Message:  integrityMsg,
+				Critical: true,
+			})
+			success = false
+		} else {
+			details = append(details, ValidationDetail{
+				Check:    "AAB完整性检查",
+				Status:   "success",
+				Message:  integrityMsg,
+				Critical: false,
+			})
+		}
+	}
+
+	var resultErr error
+	if !success {
+		resultErr = fmt.Errorf("AAB验证失败")
+	}
+
+	return v.createValidationResult(success, aabPath, fileSize, details, resultErr), resultErr
+}
+
+// validateAABIntegrity 验证AAB文件结构
+func (v *ArtifactValidatorImpl) validateAABIntegrity(aabPath string) (bool, string) {
+	// AAB本质上也是ZIP文件
+	zipReader, err := zip.OpenReader(aabPath)
+	if err != nil {
+		return false, fmt.Sprintf("无法打开AAB文件: %v", err)
+	}
+	defer zipReader.Close()
+
+	var hasBundleConfig, hasManifest, hasDex bool
+	abiSet := make(map[string]bool)
+
+	// 检查base模块内的必要文件
+	for _, file := range zipReader.File {
+		fileName := file.Name
+
+		switch {
+		case fileName == "BundleConfig.pb":
+			hasBundleConfig = true
+		case fileName == "base/manifest/AndroidManifest.xml":
+			hasManifest = true
+		case strings.HasPrefix(fileName, "base/dex/") && strings.HasSuffix(fileName, ".dex"):
+			hasDex = true
+		case strings.HasPrefix(fileName, "base/lib/"):
+			// base/lib/<abi>/libxxx.so
+			parts := strings.Split(strings.TrimPrefix(fileName, "base/lib/"), "/")
+			if len(parts) >= 2 && parts[0] != "" && strings.HasSuffix(parts[len(parts)-1], ".so") {
+				abiSet[parts[0]] = true
+			}
+		}
+	}
+
+	// 验证必要结构
+	var missingComponents []string
+	if !hasBundleConfig {
+		missingComponents = append(missingComponents, "BundleConfig.pb")
+	}
+	if !hasManifest {
+		missingComponents = append(missingComponents, "base/manifest/AndroidManifest.xml")
+	}
+	if !hasDex {
+		missingComponents = append(missingComponents, "base/dex")
+	}
+	if len(abiSet) == 0 {
+		missingComponents = append(missingComponents, "base/lib/<abi>")
+	}
+
+	if len(missingComponents) > 0 {
+		return false, fmt.Sprintf("缺少必要组件: %s", strings.Join(missingComponents, ", "))
+	}
+
+	abis := make([]string, 0, len(abiSet))
+	for abi := range abiSet {
+		abis = append(abis, abi)
+	}
+	sort.Strings(abis)
+
+	details := []string{
+		"ZIP结构正常",
+		"BundleConfig.pb 存在",
+		"AndroidManifest.xml 存在",
+		"dex 存在",
+		fmt.Sprintf("ABI: %s", strings.Join(abis, ", ")),
+	}
+
+	return true, fmt.Sprintf("AAB完整性正常 (%s)", strings.Join(details, ", "))
+}
@@ -3,12 +3,19 @@ package artifact
 import (
 	"archive/zip"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
 // ValidateAPK 验证Android APK文件
 func (v *ArtifactValidatorImpl) ValidateAPK(apkPath string, config *ArtifactConfig) (*ValidationResult, error) {
+	return v.validateAPK(apkPath, "", config)
+}
+
+// validateAPK 验证APK文件，abi 为拆分APK的架构（未拆分时为空）
+func (v *ArtifactValidatorImpl) validateAPK(apkPath, abi string, config *ArtifactConfig) (*ValidationResult, error) {
 	var details []ValidationDetail
 	var success = true
 
@@ -62,22 +69,24 @@ func (v *ArtifactValidatorImpl) ValidateAPK(apkPath string, config *ArtifactConf
 		})
 	}
 
-	// 3. 检查调试信息目录（非关键）
+	// 3. 检查调试信息目录（非关键，仅release模式生成）
 	debugInfoPath := filepath.Join(filepath.Dir(filepath.Dir(filepath.Dir(filepath.Dir(apkPath)))), "debug-info")
-	if debugExists, _, _ := v.checkFileExists(debugInfoPath); debugExists {
-		details = append(details, ValidationDetail{
-			Check:    "调试信息目录检查",
-			Status:   "success",
-			Message:  "调试信息目录存在",
-			Critical: false,
-		})
-	} else {
-		details = append(details, ValidationDetail{
-			Check:    "调试信息目录检查",
-			Status:   "warning",
-			Message:  "调试信息目录不存在（可能未启用代码混淆）",
-			Critical: false,
-		})
+	if normalizeBuildMode(config.BuildMode) == BuildModeRelease {
+		if debugExists, _, _ := v.checkFileExists(debugInfoPath); debugExists {
+			details = append(details, ValidationDetail{
+				Check:    "调试信息目录检查",
+				Status:   "success",
+				Message:  "调试信息目录存在",
+				Critical: false,
+			})
+		} else {
+			details = append(details, ValidationDetail{
+				Check:    "调试信息目录检查",
+				Status:   "warning",
+				Message:  "调试信息目录不存在（可能未启用代码混淆）",
+				Critical: false,
+			})
+		}
 	}
 
 	// 4. APK完整性检查（如果启用）
@@ -101,12 +110,65 @@ func (v *ArtifactValidatorImpl) ValidateAPK(apkPath string, config *ArtifactConf
 		}
 	}
 
+	// 5. 解析 AndroidManifest.xml 并检查预期值（如果启用完整性检查或设置了预期值）
+	var manifest *AndroidManifest
+	var expected *ManifestExpectation
+	if config.ValidationConfig != nil {
+		expected = config.ValidationConfig.ExpectedManifest
+	}
+	if expected != nil || config.ValidateIntegrity || (config.ValidationConfig != nil && config.ValidationConfig.EnableIntegrityCheck) {
+		var detail ValidationDetail
+		manifest, detail = v.checkAPKManifest(apkPath, abi, expected)
+		details = append(details, detail)
+		if detail.Status == "failed" {
+			success = false
+		}
+		if manifest != nil && manifest.Debuggable && normalizeBuildMode(config.BuildMode) == BuildModeRelease && (expected == nil || expected.Debuggable == nil) {
+			details = append(details, ValidationDetail{
+				Check:    "APK清单检查",
+				Status:   "warning",
+				Message:  "release 构建的APK可调试（android:debuggable=true）",
+				Critical: false,
+			})
+		}
+	}
+
 	var resultErr error
 	if !success {
 		resultErr = fmt.Errorf("APK验证失败")
 	}
 
-	return v.createValidationResult(success, apkPath, fileSize, details, resultErr), resultErr
+	result := v.createValidationResult(success, apkPath, fileSize, details, resultErr)
+	result.Manifest = manifest
+	return result, resultErr
+}
+
+// checkAPKManifest 解析APK清单并与预期值比较。未设置预期值时解析失败只记为警告
+func (v *ArtifactValidatorImpl) checkAPKManifest(apkPath, abi string, expected *ManifestExpectation) (*AndroidManifest, ValidationDetail) {
+	manifest, err := ReadAPKManifest(apkPath)
+	if err != nil {
+		detail := ValidationDetail{Check: "APK清单检查", Status: "warning", Message: err.Error()}
+		if expected != nil {
+			detail.Status, detail.Critical = "failed", true
+		}
+		return nil, detail
+	}
+
+	if problems := expected.Check(manifest, abi); len(problems) > 0 {
+		return manifest, ValidationDetail{
+			Check:    "APK清单检查",
+			Status:   "failed",
+			Message:  "清单与预期不符: " + strings.Join(problems, "; "),
+			Critical: true,
+		}
+	}
+	return manifest, ValidationDetail{
+		Check:  "APK清单检查",
+		Status: "success",
+		Message: fmt.Sprintf("%s versionCode=%d versionName=%s minSdk=%d targetSdk=%d，%d 项权限",
+			manifest.Package, manifest.VersionCode, manifest.VersionName, manifest.MinSdkVersion, manifest.TargetSdkVersion, len(manifest.Permissions)),
+		Critical: expected != nil,
+	}
 }
 
 // validateAPKIntegrity 验证APK文件完整性
@@ -165,4 +227,97 @@ func (v *ArtifactValidatorImpl) validateAPKIntegrity(apkPath string) (bool, stri
 	}
 
 	return true, fmt.Sprintf("APK完整性正常 (%s)", strings.Join(details, ", "))
-}
\ No newline at end of file
+}
+
+// AndroidABIs Flutter 按ABI拆分构建时可能生成的架构
+var AndroidABIs = []string{"armeabi-v7a", "arm64-v8a", "x86", "x86_64"}
+
+// FindSplitAPKs 查找按ABI拆分构建生成的APK (app-<abi>[-<flavor>]-<mode>.apk)，返回 ABI -> 路径。
+// 只匹配已知架构，其他 flavor 或构建模式遗留的APK不会被当作拆分APK
+func FindSplitAPKs(apkDir, flavor string, mode BuildMode) (map[string]string, error) {
+	if _, err := os.Stat(apkDir); err != nil {
+		return nil, fmt.Errorf("读取APK目录失败: %w", err)
+	}
+
+	apks := make(map[string]string)
+	for _, abi := range AndroidABIs {
+		apkPath := filepath.Join(apkDir, APKFileName(flavor, abi, mode))
+		if info, err := os.Stat(apkPath); err == nil && !info.IsDir() {
+			apks[abi] = apkPath
+		}
+	}
+
+	return apks, nil
+}
+
+// validateSplitAPKs 逐个验证按ABI拆分的APK
+func (v *ArtifactValidatorImpl) validateSplitAPKs(apkDir string, config *ArtifactConfig) (*ValidationResult, error) {
+	apks, err := FindSplitAPKs(apkDir, config.Flavor, config.BuildMode)
+	if err == nil && len(apks) == 0 {
+		err = fmt.Errorf("在目录 %s 中未找到按ABI拆分的APK", apkDir)
+	}
+	if err != nil {
+		details := []ValidationDetail{
+			{Check: "拆分APK查找", Status: "failed", Message: err.Error(), Critical: true},
+		}
+		return v.createValidationResult(false, apkDir, 0, details, err), err
+	}
+
+	abis := make([]string, 0, len(apks))
+	for abi := range apks {
+		abis = append(abis, abi)
+	}
+	sort.Strings(abis)
+
+	var details []ValidationDetail
+	var artifacts []ArtifactFile
+	var totalSize int64
+	success := true
+
+	details = append(details, ValidationDetail{
+		Check:    "拆分APK查找",
+		Status:   "success",
+		Message:  fmt.Sprintf("找到 %d 个APK: %s", len(abis), strings.Join(abis, ", ")),
+		Critical: true,
+	})
+
+	for _, abi := range abis {
+		apkPath := apks[abi]
+		result, _ := v.validateAPK(apkPath, abi, config)
+		for _, detail := range result.ValidationDetails {
+			detail.Check = fmt.Sprintf("[%s] %s", abi, detail.Check)
+			details = append(details, detail)
+		}
+		if !result.Success {
+			success = false
+			continue
+		}
+
+		artifactFile, err := NewArtifactFile(apkPath, abi)
+		if err != nil {
+			details = append(details, ValidationDetail{
+				Check:    fmt.Sprintf("[%s] 校验和计算", abi),
+				Status:   "failed",
+				Message:  fmt.Sprintf("计算校验和失败: %v", err),
+				Critical: true,
+			})
+			success = false
+			continue
+		}
+		artifactFile.Manifest = result.Manifest
+		artifacts = append(artifacts, *artifactFile)
+		totalSize += artifactFile.Size
+	}
+
+	var resultErr error
+	if !success {
+		resultErr = fmt.Errorf("拆分APK验证失败")
+	}
+
+	result := v.createValidationResult(success, apkDir, totalSize, details, resultErr)
+	result.Artifacts = artifacts
+	if len(artifacts) > 0 {
+		result.Manifest = artifacts[0].Manifest
+	}
+	return result, resultErr
+}
@@ -0,0 +1,397 @@
+package artifact
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf16"
+)
+
+// AndroidManifest 从APK的二进制 AndroidManifest.xml 中解析出的信息
+type AndroidManifest struct {
+	Package          string   `json:"package"`            // 包名（applicationId）
+	VersionCode      int64    `json:"version_code"`       // 版本号（包括 versionCodeMajor）
+	VersionName      string   `json:"version_name"`       // 版本名称（引用字符串资源时为 @0x7f...）
+	MinSdkVersion    int      `json:"min_sdk_version"`    // 最低 SDK 版本（未声明时为0）
+	TargetSdkVersion int      `json:"target_sdk_version"` // 目标 SDK 版本（未声明时为0）
+	Permissions      []string `json:"permissions"`        // 声明的权限（uses-permission）
+	Debuggable       bool     `json:"debuggable"`         // application 是否可调试
+}
+
+// ManifestExpectation APK清单的预期值，零值字段不检查
+type ManifestExpectation struct {
+	Package          string // 包名
+	VersionCode      int64  // 版本号（拆分APK同时接受 Flutter 按ABI加上的偏移，如 arm64-v8a 为 2000+版本号）
+	VersionName      string // 版本名称
+	MinSdkVersion    int    // 最低 SDK 版本
+	TargetSdkVersion int    // 目标 SDK 版本
+	Debuggable       *bool  // 是否可调试
+}
+
+// abiVersionCodes Flutter 按ABI拆分构建时 versionCode 的偏移（偏移*1000 + versionCode）
+var abiVersionCodes = map[string]int64{"armeabi-v7a": 1, "arm64-v8a": 2, "x86": 3, "x86_64": 4}
+
+// Check 返回清单与预期值不符的项（abi 为拆分APK的架构，未拆分时为空）
+func (e *ManifestExpectation) Check(m *AndroidManifest, abi string) []string {
+	if e == nil {
+		return nil
+	}
+
+	var problems []string
+	mismatch := func(field string, got, want interface{}) {
+		problems = append(problems, fmt.Sprintf("%s 为 %v，预期 %v", field, got, want))
+	}
+	if e.Package != "" && m.Package != e.Package {
+		mismatch("package", m.Package, e.Package)
+	}
+	if e.VersionCode != 0 && m.VersionCode != e.VersionCode &&
+		(abiVersionCodes[abi] == 0 || m.VersionCode != abiVersionCodes[abi]*1000+e.VersionCode) {
+		mismatch("versionCode", m.VersionCode, e.VersionCode)
+	}
+	if e.VersionName != "" && m.VersionName != e.VersionName {
+		mismatch("versionName", m.VersionName, e.VersionName)
+	}
+	if e.MinSdkVersion != 0 && m.MinSdkVersion != e.MinSdkVersion {
+		mismatch("minSdkVersion", m.MinSdkVersion, e.MinSdkVersion)
+	}
+	if e.TargetSdkVersion != 0 && m.TargetSdkVersion != e.TargetSdkVersion {
+		mismatch("targetSdkVersion", m.TargetSdkVersion, e.TargetSdkVersion)
+	}
+	if e.Debuggable != nil && m.Debuggable != *e.Debuggable {
+		mismatch("debuggable", m.Debuggable, *e.Debuggable)
+	}
+	return problems
+}
+
+// ReadAPKManifest 读取并解析APK中的 AndroidManifest.xml
+func ReadAPKManifest(apkPath string) (*AndroidManifest, error) {
+	zipReader, err := zip.OpenReader(apkPath)
+	if err != nil {
+		return nil, fmt.Errorf("无法打开APK文件: %w", err)
+	}
+	defer zipReader.Close()
+
+	for _, file := range zipReader.File {
+		if file.Name != "AndroidManifest.xml" {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+
+		data, err := io.ReadAll(io.LimitReader(reader, maxManifestSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxManifestSize {
+			return nil, fmt.Errorf("AndroidManifest.xml 过大")
+		}
+		return ParseAndroidManifest(data)
+	}
+	return nil, fmt.Errorf("APK中缺少 AndroidManifest.xml")
+}
+
+// ParseAndroidManifest 解析二进制 XML（AXML）格式的 AndroidManifest.xml
+func ParseAndroidManifest(data []byte) (*AndroidManifest, error) {
+	manifest := &AndroidManifest{Permissions: []string{}}
+	var hasManifest bool
+	var versionCodeMajor int64
+
+	err := decodeAXML(data, func(element string, attrs []axmlAttr) {
+		for _, attr := range attrs {
+			switch element + "." + attr.name {
+			case "manifest.package":
+				manifest.Package = attr.stringValue()
+			case "manifest.versionCode":
+				manifest.VersionCode = attr.intValue()
+			case "manifest.versionCodeMajor":
+				versionCodeMajor = attr.intValue()
+			case "manifest.versionName":
+				manifest.VersionName = attr.stringValue()
+			case "uses-sdk.minSdkVersion":
+				manifest.MinSdkVersion = int(attr.intValue())
+			case "uses-sdk.targetSdkVersion":
+				manifest.TargetSdkVersion = int(attr.intValue())
+			case "uses-permission.name", "uses-permission-sdk-23.name":
+				manifest.Permissions = append(manifest.Permissions, attr.stringValue())
+			case "application.debuggable":
+				manifest.Debuggable = attr.boolValue()
+			}
+		}
+		if element == "manifest" {
+			hasManifest = true
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("解析 AndroidManifest.xml 失败: %w", err)
+	}
+	if !hasManifest {
+		return nil, fmt.Errorf("解析 AndroidManifest.xml 失败: 缺少 manifest 元素")
+	}
+	manifest.VersionCode |= versionCodeMajor << 32
+	return manifest, nil
+}
+
+// maxManifestSize AndroidManifest.xml 的最大读取大小
+const maxManifestSize = 16 * 1024 * 1024
+
+// 二进制 XML 的数据块类型（frameworks/base/libs/androidfw/include/androidfw/ResourceTypes.h）
+const (
+	axmlStringPoolType   = 0x0001
+	axmlXMLType          = 0x0003
+	axmlStartElementType = 0x0102
+	axmlResourceMapType  = 0x0180
+
+	axmlUTF8Flag = 1 << 8 // 字符串池使用 UTF-8 编码
+	axmlNoIndex  = 0xFFFFFFFF
+)
+
+// 属性值（Res_value）的数据类型
+const (
+	axmlTypeReference = 0x01
+	axmlTypeString    = 0x03
+	axmlTypeIntDec    = 0x10
+	axmlTypeIntHex    = 0x11
+	axmlTypeBoolean   = 0x12
+)
+
+// androidAttrIDs Android 框架属性的资源ID。资源混淆工具可能去掉属性名，因此优先按资源ID识别
+var androidAttrIDs = map[uint32]string{
+	0x01010003: "name",
+	0x0101000f: "debuggable",
+	0x0101020c: "minSdkVersion",
+	0x0101021b: "versionCode",
+	0x0101021c: "versionName",
+	0x01010270: "targetSdkVersion",
+	0x01010576: "versionCodeMajor",
+}
+
+// axmlAttr 元素的属性
+type axmlAttr struct {
+	name     string // 属性名（Android 框架属性按资源ID识别）
+	raw      string // 原始字符串值（没有时为空）
+	dataType uint8  // 类型化值的数据类型
+	data     uint32 // 类型化值的数据
+	str      string // 字符串类型的值
+}
+
+// stringValue 返回属性的字符串形式
+func (a axmlAttr) stringValue() string {
+	switch {
+	case a.dataType == axmlTypeString:
+		return a.str
+	case a.raw != "":
+		return a.raw
+	case a.dataType == axmlTypeReference:
+		return fmt.Sprintf("@0x%08x", a.data)
+	case a.dataType == axmlTypeBoolean:
+		return strconv.FormatBool(a.data != 0)
+	default:
+		return strconv.FormatInt(int64(int32(a.data)), 10)
+	}
+}
+
+// intValue 返回属性的整数值（字符串形式的数字同样解析，无法解析时为0）
+func (a axmlAttr) intValue() int64 {
+	switch a.dataType {
+	case axmlTypeIntDec, axmlTypeIntHex:
+		return int64(a.data)
+	default:
+		value, _ := strconv.ParseInt(a.stringValue(), 10, 64)
+		return value
+	}
+}
+
+// boolValue 返回属性的布尔值
+func (a axmlAttr) boolValue() bool {
+	if a.dataType == axmlTypeBoolean {
+		return a.data != 0
+	}
+	return a.stringValue() == "true"
+}
+
+// decodeAXML 依次解析二进制 XML 的数据块，对每个开始元素调用 onElement
+func decodeAXML(data []byte, onElement func(element string, attrs []axmlAttr)) error {
+	typ, headerSize, size, err := chunkHeader(data, 0)
+	if err != nil {
+		return err
+	}
+	if typ != axmlXMLType {
+		return fmt.Errorf("不是二进制XML（文件头 0x%04x）", typ)
+	}
+
+	var pool []string
+	var resourceIDs []uint32
+	for offset := headerSize; offset < size; {
+		typ, chunkHeaderSize, chunkSize, err := chunkHeader(data[:size], offset)
+		if err != nil {
+			return err
+		}
+		chunk := data[offset : offset+chunkSize]
+
+		switch typ {
+		case axmlStringPoolType:
+			if pool, err = parseStringPool(chunk, chunkHeaderSize); err != nil {
+				return err
+			}
+		case axmlResourceMapType:
+			resourceIDs = resourceIDs[:0]
+			for i := chunkHeaderSize; i+4 <= chunkSize; i += 4 {
+				resourceIDs = append(resourceIDs, binary.LittleEndian.Uint32(chunk[i:]))
+			}
+		case axmlStartElementType:
+			element, attrs, err := parseStartElement(chunk, chunkHeaderSize, pool, resourceIDs)
+			if err != nil {
+				return err
+			}
+			onElement(element, attrs)
+		}
+		offset += chunkSize
+	}
+	return nil
+}
+
+// chunkHeader 读取 offset 处数据块的类型、头部大小和总大小
+func chunkHeader(data []byte, offset int) (typ uint16, headerSize, size int, err error) {
+	if offset+8 > len(data) {
+		return 0, 0, 0, fmt.Errorf("数据块头部被截断（偏移 %d）", offset)
+	}
+	typ = binary.LittleEndian.Uint16(data[offset:])
+	headerSize = int(binary.LittleEndian.Uint16(data[offset+2:]))
+	size = int(binary.LittleEndian.Uint32(data[offset+4:]))
+	if headerSize < 8 || size < headerSize || offset+size > len(data) {
+		return 0, 0, 0, fmt.Errorf("数据块大小无效（偏移 %d）", offset)
+	}
+	return typ, headerSize, size, nil
+}
+
+// parseStringPool 解析字符串池
+func parseStringPool(chunk []byte, headerSize int) ([]string, error) {
+	if headerSize < 28 {
+		return nil, fmt.Errorf("字符串池头部无效")
+	}
+	count := int(binary.LittleEndian.Uint32(chunk[8:]))
+	flags := binary.LittleEndian.Uint32(chunk[16:])
+	stringsStart := int(binary.LittleEndian.Uint32(chunk[20:]))
+	if count > (len(chunk)-headerSize)/4 || stringsStart > len(chunk) {
+		return nil, fmt.Errorf("字符串池大小无效")
+	}
+
+	pool := make([]string, count)
+	for i := range pool {
+		offset := stringsStart + int(binary.LittleEndian.Uint32(chunk[headerSize+4*i:]))
+		var err error
+		if flags&axmlUTF8Flag != 0 {
+			pool[i], err = decodeUTF8String(chunk, offset)
+		} else {
+			pool[i], err = decodeUTF16String(chunk, offset)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("字符串池第 %d 项无效: %w", i, err)
+		}
+	}
+	return pool, nil
+}
+
+// decodeUTF8String 解析 UTF-8 字符串：UTF-16 长度、UTF-8 字节数（各1或2字节）后为字符串内容
+func decodeUTF8String(data []byte, offset int) (string, error) {
+	readLength := func() (int, error) {
+		if offset >= len(data) {
+			return 0, io.ErrUnexpectedEOF
+		}
+		length := int(data[offset])
+		offset++
+		if length&0x80 != 0 {
+			if offset >= len(data) {
+				return 0, io.ErrUnexpectedEOF
+			}
+			length = (length&0x7F)<<8 | int(data[offset])
+			offset++
+		}
+		return length, nil
+	}
+	if _, err := readLength(); err != nil {
+		return "", err
+	}
+	length, err := readLength()
+	if err != nil {
+		return "", err
+	}
+	if offset+length > len(data) {
+		return "", io.ErrUnexpectedEOF
+	}
+	return string(data[offset : offset+length]), nil
+}
+
+// decodeUTF16String 解析 UTF-16 字符串：长度（1或2个 uint16）后为字符串内容
+func decodeUTF16String(data []byte, offset int) (string, error) {
+	if offset+2 > len(data) {
+		return "", io.ErrUnexpectedEOF
+	}
+	length := int(binary.LittleEndian.Uint16(data[offset:]))
+	offset += 2
+	if length&0x8000 != 0 {
+		if offset+2 > len(data) {
+			return "", io.ErrUnexpectedEOF
+		}
+		length = (length&0x7FFF)<<16 | int(binary.LittleEndian.Uint16(data[offset:]))
+		offset += 2
+	}
+	if length > (len(data)-offset)/2 {
+		return "", io.ErrUnexpectedEOF
+	}
+
+	units := make([]uint16, length)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[offset+2*i:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// parseStartElement 解析开始元素：元素名和属性
+func parseStartElement(chunk []byte, headerSize int, pool []string, resourceIDs []uint32) (string, []axmlAttr, error) {
+	if headerSize+20 > len(chunk) {
+		return "", nil, fmt.Errorf("元素数据块被截断")
+	}
+	ext := chunk[headerSize:]
+	lookup := func(index uint32) string {
+		if index == axmlNoIndex || int(index) >= len(pool) {
+			return ""
+		}
+		return pool[index]
+	}
+
+	element := lookup(binary.LittleEndian.Uint32(ext[4:]))
+	attrStart := int(binary.LittleEndian.Uint16(ext[8:]))
+	attrSize := int(binary.LittleEndian.Uint16(ext[10:]))
+	attrCount := int(binary.LittleEndian.Uint16(ext[12:]))
+	if attrCount > 0 && (attrSize < 20 || attrStart+attrCount*attrSize > len(ext)) {
+		return "", nil, fmt.Errorf("元素 %s 的属性被截断", element)
+	}
+
+	attrs := make([]axmlAttr, 0, attrCount)
+	for i := 0; i < attrCount; i++ {
+		raw := ext[attrStart+i*attrSize:]
+		nameIndex := binary.LittleEndian.Uint32(raw[4:])
+		attr := axmlAttr{
+			name:     lookup(nameIndex),
+			raw:      lookup(binary.LittleEndian.Uint32(raw[8:])),
+			dataType: raw[15],
+			data:     binary.LittleEndian.Uint32(raw[16:]),
+		}
+		if int(nameIndex) < len(resourceIDs) {
+			if name, ok := androidAttrIDs[resourceIDs[nameIndex]]; ok {
+				attr.name = name
+			}
+		}
+		if attr.dataType == axmlTypeString {
+			attr.str = lookup(attr.data)
+		}
+		attrs = append(attrs, attr)
+	}
+	return element, attrs, nil
+}
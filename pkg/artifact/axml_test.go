@@ -0,0 +1,304 @@
+package artifact
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// testAttr 测试用的属性，resID 不为0时写入资源ID映射
+type testAttr struct {
+	name     string
+	resID    uint32
+	dataType uint8
+	data     uint32
+	str      string // dataType 为 axmlTypeString 时的值
+}
+
+// testElement 测试用的元素
+type testElement struct {
+	name  string
+	attrs []testAttr
+}
+
+// encodeTestAXML 按 aapt2 的格式生成二进制 XML：带资源ID的属性名排在字符串池前部并与资源ID映射一一对应
+func encodeTestAXML(useUTF8 bool, elements []testElement) []byte {
+	var pool []string
+	var resourceIDs []uint32
+	indexes := map[string]uint32{}
+	intern := func(s string) uint32 {
+		if index, ok := indexes[s]; ok {
+			return index
+		}
+		indexes[s] = uint32(len(pool))
+		pool = append(pool, s)
+		return indexes[s]
+	}
+	// 带资源ID的属性名可能重复（混淆后都为空），不去重
+	attrNames := map[*testAttr]uint32{}
+	for i := range elements {
+		for j := range elements[i].attrs {
+			if attr := &elements[i].attrs[j]; attr.resID != 0 {
+				attrNames[attr] = uint32(len(pool))
+				pool = append(pool, attr.name)
+				resourceIDs = append(resourceIDs, attr.resID)
+			}
+		}
+	}
+
+	var body []byte
+	u16 := func(b []byte, v uint16) []byte { return binary.LittleEndian.AppendUint16(b, v) }
+	u32 := func(b []byte, v uint32) []byte { return binary.LittleEndian.AppendUint32(b, v) }
+	for i := range elements {
+		element := &elements[i]
+		chunk := u32(u32(nil, 1), axmlNoIndex) // lineNumber、comment
+		chunk = u32(chunk, axmlNoIndex)        // ns
+		chunk = u32(chunk, intern(element.name))
+		chunk = u16(u16(u16(chunk, 20), 20), uint16(len(element.attrs)))
+		chunk = u16(u16(u16(chunk, 0), 0), 0)
+		for j := range element.attrs {
+			attr := &element.attrs[j]
+			name, ok := attrNames[attr]
+			if !ok {
+				name = intern(attr.name)
+			}
+			raw, data := uint32(axmlNoIndex), attr.data
+			if attr.dataType == axmlTypeString {
+				raw = intern(attr.str)
+				data = raw
+			}
+			chunk = u32(u32(u32(chunk, axmlNoIndex), name), raw)
+			chunk = append(u16(chunk, 8), 0, attr.dataType)
+			chunk = u32(chunk, data)
+		}
+		body = append(u32(u16(u16(body, axmlStartElementType), 16), uint32(8+len(chunk))), chunk...)
+
+		// 结束元素，解析时应跳过
+		end := u32(u32(u32(u32(nil, 1), axmlNoIndex), axmlNoIndex), intern(element.name))
+		body = append(u32(u16(u16(body, 0x0103), 16), uint32(8+len(end))), end...)
+	}
+
+	var offsets, stringData []byte
+	for _, s := range pool {
+		offsets = u32(offsets, uint32(len(stringData)))
+		if useUTF8 {
+			stringData = append(stringData, byte(len(utf16.Encode([]rune(s)))), byte(len(s)))
+			stringData = append(append(stringData, s...), 0)
+		} else {
+			units := utf16.Encode([]rune(s))
+			stringData = u16(stringData, uint16(len(units)))
+			for _, unit := range units {
+				stringData = u16(stringData, unit)
+			}
+			stringData = u16(stringData, 0)
+		}
+	}
+	for len(stringData)%4 != 0 {
+		stringData = append(stringData, 0)
+	}
+	var flags uint32
+	if useUTF8 {
+		flags = axmlUTF8Flag
+	}
+	stringPool := u32(u32(u16(u16(nil, axmlStringPoolType), 28), uint32(28+len(offsets)+len(stringData))), uint32(len(pool)))
+	stringPool = u32(u32(u32(u32(stringPool, 0), flags), uint32(28+len(offsets))), 0)
+	stringPool = append(append(stringPool, offsets...), stringData...)
+
+	resourceMap := u32(u16(u16(nil, axmlResourceMapType), 8), uint32(8+4*len(resourceIDs)))
+	for _, id := range resourceIDs {
+		resourceMap = u32(resourceMap, id)
+	}
+
+	size := 8 + len(stringPool) + len(resourceMap) + len(body)
+	data := u32(u16(u16(nil, axmlXMLType), 8), uint32(size))
+	return append(append(append(data, stringPool...), resourceMap...), body...)
+}
+
+// testManifestElements 返回一个 release 构建的清单，其中一个权限的属性名被资源混淆去掉
+func testManifestElements(packageName string, versionCode uint32) []testElement {
+	return []testElement{
+		{name: "manifest", attrs: []testAttr{
+			{name: "versionCode", resID: 0x0101021b, dataType: axmlTypeIntDec, data: versionCode},
+			{name: "versionName", resID: 0x0101021c, dataType: axmlTypeString, str: "1.2.0-测试"},
+			{name: "package", dataType: axmlTypeString, str: packageName},
+		}},
+		{name: "uses-sdk", attrs: []testAttr{
+			{name: "minSdkVersion", resID: 0x0101020c, dataType: axmlTypeIntDec, data: 21},
+			{name: "targetSdkVersion", resID: 0x01010270, dataType: axmlTypeIntDec, data: 34},
+		}},
+		{name: "uses-permission", attrs: []testAttr{
+			{name: "name", resID: 0x01010003, dataType: axmlTypeString, str: "android.permission.INTERNET"},
+		}},
+		{name: "uses-permission", attrs: []testAttr{
+			{name: "", resID: 0x01010003, dataType: axmlTypeString, str: "android.permission.CAMERA"},
+		}},
+		{name: "application", attrs: []testAttr{
+			{name: "label", resID: 0x01010001, dataType: axmlTypeReference, data: 0x7f0e0001},
+			{name: "debuggable", resID: 0x0101000f, dataType: axmlTypeBoolean, data: 0},
+		}},
+	}
+}
+
+func TestParseAndroidManifest(t *testing.T) {
+	want := &AndroidManifest{
+		Package:          "com.example.shop",
+		VersionCode:      43,
+		VersionName:      "1.2.0-测试",
+		MinSdkVersion:    21,
+		TargetSdkVersion: 34,
+		Permissions:      []string{"android.permission.INTERNET", "android.permission.CAMERA"},
+	}
+	for _, useUTF8 := range []bool{true, false} {
+		manifest, err := ParseAndroidManifest(encodeTestAXML(useUTF8, testManifestElements("com.example.shop", 43)))
+		if err != nil {
+			t.Fatalf("UTF-8=%v: %v", useUTF8, err)
+		}
+		if !reflect.DeepEqual(manifest, want) {
+			t.Errorf("UTF-8=%v: 解析结果错误:\n%+v\n应为:\n%+v", useUTF8, manifest, want)
+		}
+	}
+
+	// versionCodeMajor 作为高32位，debuggable 为 true（-1）
+	elements := testManifestElements("com.example.shop", 43)
+	elements[0].attrs = append(elements[0].attrs, testAttr{name: "versionCodeMajor", resID: 0x01010576, dataType: axmlTypeIntDec, data: 1})
+	elements[4].attrs[1].data = 0xFFFFFFFF
+	manifest, err := ParseAndroidManifest(encodeTestAXML(true, elements))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.VersionCode != 1<<32|43 || !manifest.Debuggable {
+		t.Errorf("versionCodeMajor 或 debuggable 解析错误: %+v", manifest)
+	}
+}
+
+func TestParseAndroidManifestInvalid(t *testing.T) {
+	if _, err := ParseAndroidManifest([]byte(`<?xml version="1.0" encoding="utf-8"?><manifest/>`)); err == nil {
+		t.Error("文本 XML 应返回错误")
+	}
+	if _, err := ParseAndroidManifest(encodeTestAXML(true, []testElement{{name: "application"}})); err == nil {
+		t.Error("缺少 manifest 元素时应返回错误")
+	}
+
+	// 截断的数据返回错误而不是越界
+	data := encodeTestAXML(false, testManifestElements("com.example.shop", 43))
+	for size := 0; size < len(data); size++ {
+		truncated := append([]byte(nil), data[:size]...)
+		if size >= 8 {
+			binary.LittleEndian.PutUint32(truncated[4:], uint32(size))
+		}
+		ParseAndroidManifest(truncated)
+	}
+}
+
+func TestManifestExpectationCheck(t *testing.T) {
+	manifest := &AndroidManifest{Package: "com.example.shop", VersionCode: 2043, VersionName: "1.2.0", TargetSdkVersion: 34}
+	debuggable := true
+	tests := []struct {
+		expected *ManifestExpectation
+		abi      string
+		problems int
+	}{
+		{nil, "", 0},
+		{&ManifestExpectation{Package: "com.example.shop", VersionCode: 2043, TargetSdkVersion: 34}, "", 0},
+		// 拆分APK的 versionCode 带有 Flutter 按ABI加上的偏移
+		{&ManifestExpectation{VersionCode: 43}, "arm64-v8a", 0},
+		{&ManifestExpectation{VersionCode: 43}, "x86_64", 1},
+		{&ManifestExpectation{VersionCode: 43}, "", 1},
+		{&ManifestExpectation{Package: "com.example.shop.dev", VersionName: "1.1.0", Debuggable: &debuggable}, "", 3},
+	}
+	for _, tt := range tests {
+		if problems := tt.expected.Check(manifest, tt.abi); len(problems) != tt.problems {
+			t.Errorf("Check(%+v, %q) = %v，应有 %d 项不符", tt.expected, tt.abi, problems, tt.problems)
+		}
+	}
+}
+
+func TestArtifactValidator_APKManifest(t *testing.T) {
+	validator := NewArtifactValidator()
+	apkPath := filepath.Join(t.TempDir(), "app-release.apk")
+	if err := createTestAPKWithManifest(apkPath, encodeTestAXML(true, testManifestElements("com.example.shop", 43))); err != nil {
+		t.Fatalf("创建测试APK失败: %v", err)
+	}
+
+	newConfig := func(expected *ManifestExpectation) *ArtifactConfig {
+		return &ArtifactConfig{
+			Platform:    PlatformAPK,
+			MinFileSize: 100,
+			MaxFileSize: DefaultAndroidMaxSize,
+			BuildMode:   BuildModeRelease,
+			ValidationConfig: &ArtifactValidationConfig{
+				EnableValidation: true,
+				ExpectedManifest: expected,
+			},
+		}
+	}
+
+	result, err := validator.ValidateAPK(apkPath, newConfig(&ManifestExpectation{Package: "com.example.shop", VersionCode: 43}))
+	if err != nil {
+		t.Fatalf("验证APK失败: %v", err)
+	}
+	if result.Manifest == nil || result.Manifest.Package != "com.example.shop" || result.Manifest.TargetSdkVersion != 34 {
+		t.Errorf("验证结果中的清单信息错误: %+v", result.Manifest)
+	}
+
+	// 包名与预期不符时验证失败
+	result, err = validator.ValidateAPK(apkPath, newConfig(&ManifestExpectation{Package: "com.example.other"}))
+	if err == nil || result.Success {
+		t.Fatal("包名不符时验证应失败")
+	}
+	found := false
+	for _, detail := range result.ValidationDetails {
+		if detail.Check == "APK清单检查" && detail.Status == "failed" && strings.Contains(detail.Message, "com.example.other") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("缺少清单不符的验证详情: %+v", result.ValidationDetails)
+	}
+
+	// 设置预期值时无法解析清单（文本 XML）验证失败
+	textAPK := filepath.Join(t.TempDir(), "text.apk")
+	if err := createTestAPK(textAPK); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := validator.ValidateAPK(textAPK, newConfig(&ManifestExpectation{Package: "com.example.test"})); result.Success {
+		t.Error("无法解析清单时验证应失败")
+	}
+}
+
+// createTestAPKWithManifest 创建包含指定二进制清单的测试APK
+func createTestAPKWithManifest(apkPath string, manifest []byte) error {
+	file, err := os.Create(apkPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zipWriter := zip.NewWriter(file)
+	defer zipWriter.Close()
+
+	files := map[string][]byte{
+		"AndroidManifest.xml": manifest,
+		"classes.dex":         []byte("dex\n"),
+		"resources.arsc":      []byte("resources"),
+	}
+	for i := 0; i < 15; i++ {
+		files[fmt.Sprintf("assets/file%d.txt", i)] = []byte("test file content")
+	}
+	for name, content := range files {
+		writer, err := zipWriter.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package artifact
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 校验和清单文件名（sha256sum/sha512sum 格式）
+const (
+	SHA256SumsFile = "SHA256SUMS"
+	SHA512SumsFile = "SHA512SUMS"
+)
+
+// FileSHA256 计算文件的SHA-256校验和
+func FileSHA256(path string) (string, error) {
+	return fileHash(path, sha256.New)
+}
+
+// FileSHA512 计算文件的SHA-512校验和
+func FileSHA512(path string) (string, error) {
+	return fileHash(path, sha512.New)
+}
+
+// TreeSHA256 计算目录的树哈希：目录中所有文件按相对路径排序后，
+// 以 sha256sum 格式（"<SHA-256>  <相对路径>\n"）列出，再对该列表计算SHA-256。
+// 结果只取决于文件内容和相对路径，与修改时间、权限和遍历顺序无关
+func TreeSHA256(dir string) (string, error) {
+	return treeHash(dir, sha256.New)
+}
+
+// TreeSHA512 以 SHA-512 计算目录的树哈希（计算方式同 TreeSHA256）
+func TreeSHA512(dir string) (string, error) {
+	return treeHash(dir, sha512.New)
+}
+
+// NewArtifactFile 根据文件路径创建产物文件信息（包含大小和SHA-256）
+func NewArtifactFile(path string, abi string) (*ArtifactFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum, err := FileSHA256(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArtifactFile{
+		ABI:      abi,
+		Path:     path,
+		Size:     info.Size(),
+		Checksum: checksum,
+	}, nil
+}
+
+// NewArtifactDir 根据目录路径创建产物信息（大小为目录中文件的总大小，校验和为树哈希）
+func NewArtifactDir(path string) (*ArtifactFile, error) {
+	entries, err := listFiles(path, sha256.New)
+	if err != nil {
+		return nil, err
+	}
+
+	var size int64
+	for _, entry := range entries {
+		size += entry.size
+	}
+	return &ArtifactFile{
+		Path:     path,
+		Size:     size,
+		Checksum: listingHash(entries, sha256.New),
+		Dir:      true,
+	}, nil
+}
+
+// fillSHA512 计算产物的SHA-512（目录为树哈希）
+func (f *ArtifactFile) fillSHA512() error {
+	var err error
+	if f.Dir {
+		f.SHA512, err = TreeSHA512(f.Path)
+	} else {
+		f.SHA512, err = FileSHA512(f.Path)
+	}
+	return err
+}
+
+// WriteChecksumFiles 在 dir 中写出产物的 SHA256SUMS（产物带有 SHA-512 时同时写出 SHA512SUMS），返回写出的文件路径。
+// 清单中的路径相对 dir，目录产物展开为其中的每个文件，可在 dir 中直接使用 sha256sum -c 校验
+func WriteChecksumFiles(dir string, files []ArtifactFile) ([]string, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	algorithms := []checksumAlgorithm{{SHA256SumsFile, sha256.New}}
+	if files[0].SHA512 != "" {
+		algorithms = append(algorithms, checksumAlgorithm{SHA512SumsFile, sha512.New})
+	}
+
+	var written []string
+	for _, algorithm := range algorithms {
+		var content strings.Builder
+		for _, file := range files {
+			lines, err := checksumLines(dir, file, algorithm.newHash)
+			if err != nil {
+				return written, err
+			}
+			for _, line := range lines {
+				content.WriteString(line)
+			}
+		}
+
+		path := filepath.Join(dir, algorithm.name)
+		if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// checksumAlgorithm 校验和清单文件及其哈希算法
+type checksumAlgorithm struct {
+	name    string
+	newHash func() hash.Hash
+}
+
+// checksumLines 返回产物在校验和清单中的行（路径相对 dir）
+func checksumLines(dir string, file ArtifactFile, newHash func() hash.Hash) ([]string, error) {
+	rel, err := filepath.Rel(dir, file.Path)
+	if err != nil {
+		return nil, err
+	}
+	rel = filepath.ToSlash(rel)
+
+	if !file.Dir {
+		sum, err := fileHash(file.Path, newHash)
+		if err != nil {
+			return nil, err
+		}
+		return []string{sumLine(sum, rel)}, nil
+	}
+
+	entries, err := listFiles(file.Path, newHash)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, sumLine(entry.sum, rel+"/"+entry.path))
+	}
+	return lines, nil
+}
+
+// fileEntry 目录中的单个文件
+type fileEntry struct {
+	path string // 相对目录的路径（使用 / 分隔）
+	sum  string
+	size int64
+}
+
+// listFiles 列出目录中的所有文件及其哈希，按相对路径排序。
+// 指向文件的符号链接按目标文件内容计算，指向目录的符号链接不展开
+func listFiles(dir string, newHash func() hash.Hash) ([]fileEntry, error) {
+	var entries []fileEntry
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		sum, err := fileHash(path, newHash)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, fileEntry{path: filepath.ToSlash(rel), sum: sum, size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("计算目录校验和失败: %w", err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].path < entries[j].path
+	})
+	return entries, nil
+}
+
+// treeHash 计算目录的树哈希
+func treeHash(dir string, newHash func() hash.Hash) (string, error) {
+	entries, err := listFiles(dir, newHash)
+	if err != nil {
+		return "", err
+	}
+	return listingHash(entries, newHash), nil
+}
+
+// listingHash 对 sha256sum 格式的文件列表计算哈希
+func listingHash(entries []fileEntry, newHash func() hash.Hash) string {
+	h := newHash()
+	for _, entry := range entries {
+		io.WriteString(h, sumLine(entry.sum, entry.path))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// sumLine 返回 sha256sum 格式的一行
+func sumLine(sum, path string) string {
+	return sum + "  " + path + "\n"
+}
+
+// fileHash 使用指定算法计算文件的校验和
+func fileHash(path string, newHash func() hash.Hash) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := newHash()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
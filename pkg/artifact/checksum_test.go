@@ -0,0 +1,112 @@
+package artifact
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTreeSHA256(t *testing.T) {
+	// writeTree 按给定顺序创建文件
+	writeTree := func(files [][2]string) string {
+		t.Helper()
+		dir := t.TempDir()
+		for _, file := range files {
+			path := filepath.Join(dir, filepath.FromSlash(file[0]))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(file[1]), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return dir
+	}
+
+	base := writeTree([][2]string{{"index.html", "<html>"}, {"assets/a.js", "a"}, {"assets/b.js", "b"}})
+	sum, err := TreeSHA256(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 创建顺序和修改时间不影响树哈希
+	reordered := writeTree([][2]string{{"assets/b.js", "b"}, {"index.html", "<html>"}, {"assets/a.js", "a"}})
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(filepath.Join(reordered, "index.html"), old, old)
+	if got, _ := TreeSHA256(reordered); got != sum {
+		t.Errorf("相同内容的目录树哈希应一致: %s != %s", got, sum)
+	}
+
+	for name, files := range map[string][][2]string{
+		"内容变化":  {{"index.html", "<html>"}, {"assets/a.js", "a2"}, {"assets/b.js", "b"}},
+		"文件改名":  {{"index.html", "<html>"}, {"assets/a.js", "a"}, {"assets/c.js", "b"}},
+		"新增空文件": {{"index.html", "<html>"}, {"assets/a.js", "a"}, {"assets/b.js", "b"}, {"empty", ""}},
+	} {
+		if got, _ := TreeSHA256(writeTree(files)); got == sum {
+			t.Errorf("%s后树哈希应变化", name)
+		}
+	}
+}
+
+func TestValidateArtifactChecksums(t *testing.T) {
+	project := t.TempDir()
+	buildDir := filepath.Join(project, "build")
+	if err := createTestWebBuild(filepath.Join(buildDir, "web"), true); err != nil {
+		t.Fatalf("创建测试Web目录失败: %v", err)
+	}
+	debugInfo := filepath.Join(buildDir, "debug-info")
+	if err := os.MkdirAll(debugInfo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(debugInfo, "app.symbols"), []byte("symbols"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewArtifactValidator().ValidateArtifact(&ArtifactConfig{
+		Platform:         PlatformWeb,
+		SourcePath:       project,
+		ValidationConfig: &ArtifactValidationConfig{EnableValidation: true, CustomMinSize: 1, EnableSHA512: true},
+		DebugInfoDir:     "build/debug-info",
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("验证失败: %v", err)
+	}
+
+	if len(result.Artifacts) != 2 {
+		t.Fatalf("应记录Web目录和调试信息目录: %+v", result.Artifacts)
+	}
+	web, symbols := result.Artifacts[0], result.Artifacts[1]
+	if !web.Dir || web.DebugInfo || !symbols.Dir || !symbols.DebugInfo {
+		t.Errorf("产物类型错误: %+v", result.Artifacts)
+	}
+	if sum, _ := TreeSHA256(web.Path); web.Checksum != sum {
+		t.Errorf("目录产物的校验和应为树哈希: %s", web.Checksum)
+	}
+	if sum, _ := TreeSHA512(symbols.Path); symbols.SHA512 != sum || len(sum) != 128 {
+		t.Errorf("SHA-512 错误: %s", symbols.SHA512)
+	}
+
+	written, err := WriteChecksumFiles(buildDir, result.Artifacts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 2 || filepath.Base(written[0]) != SHA256SumsFile || filepath.Base(written[1]) != SHA512SumsFile {
+		t.Fatalf("校验和清单错误: %v", written)
+	}
+	data, _ := os.ReadFile(written[0])
+	if !strings.Contains(string(data), "  web/index.html\n") || !strings.Contains(string(data), "  debug-info/app.symbols\n") {
+		t.Errorf("SHA256SUMS 内容错误:\n%s", data)
+	}
+
+	// 清单可直接由 sha256sum -c 校验
+	if _, err := exec.LookPath("sha256sum"); err == nil {
+		cmd := exec.Command("sha256sum", "--check", "--quiet", SHA256SumsFile)
+		cmd.Dir = buildDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("sha256sum 校验失败: %v\n%s", err, output)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package artifact
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// elfMagic ELF文件头魔数
+var elfMagic = []byte{0x7f, 'E', 'L', 'F'}
+
+// hostLinuxArch 返回当前主机架构对应的Linux bundle架构目录
+func hostLinuxArch() string {
+	if runtime.GOARCH == "arm64" {
+		return "arm64"
+	}
+	return "x64"
+}
+
+// LinuxArch 返回 --target-platform（linux-x64、linux-arm64）对应的bundle架构目录，未指定时为当前主机架构
+func LinuxArch(targetPlatform string) string {
+	if arch, ok := strings.CutPrefix(targetPlatform, "linux-"); ok && arch != "" {
+		return arch
+	}
+	return hostLinuxArch()
+}
+
+// LinuxBundlePath 获取指定架构的Linux bundle路径 (build/linux/<arch>/<mode>/bundle)，arch 为空时为当前主机架构
+func LinuxBundlePath(sourcePath, arch string, mode BuildMode) string {
+	if arch == "" {
+		arch = hostLinuxArch()
+	}
+	return filepath.Join(sourcePath, "build", "linux", arch, string(normalizeBuildMode(mode)), "bundle")
+}
+
+// validateLinuxArtifacts 验证Linux产物：只验证目标架构的bundle，不使用其他架构遗留的bundle
+func (v *ArtifactValidatorImpl) validateLinuxArtifacts(expectedPaths []string, config *ArtifactConfig) (*ValidationResult, error) {
+	return v.ValidateLinuxBundle(expectedPaths[0], config)
+}
+
+// ValidateLinuxBundle 验证Linux桌面bundle目录
+func (v *ArtifactValidatorImpl) ValidateLinuxBundle(bundlePath string, config *ArtifactConfig) (*ValidationResult, error) {
+	var details []ValidationDetail
+	var success = true
+
+	// 1. 检查bundle目录是否存在
+	exists, fileInfo, err := v.checkFileExists(bundlePath)
+	if err != nil {
+		details = append(details, ValidationDetail{
+			Check:    "Linux bundle目录存在性检查",
+			Status:   "failed",
+			Message:  fmt.Sprintf("检查目录状态失败: %v", err),
+			Critical: true,
+		})
+		return v.createValidationResult(false, bundlePath, 0, details, err), err
+	}
+
+	if !exists || !fileInfo.IsDir() {
+		details = append(details, ValidationDetail{
+			Check:    "Linux bundle目录存在性检查",
+			Status:   "failed",
+			Message:  fmt.Sprintf("Linux bundle目录不存在: %s", bundlePath),
+			Critical: true,
+		})
+		return v.createValidationResult(false, bundlePath, 0, details, fmt.Errorf("Linux bundle目录不存在")), fmt.Errorf("Linux bundle目录不存在")
+	}
+
+	details = append(details, ValidationDetail{
+		Check:    "Linux bundle目录存在性检查",
+		Status:   "success",
+		Message:  "Linux bundle目录存在",
+		Critical: true,
+	})
+
+	// 2. 检查目录大小
+	dirSize, err := v.calculateDirectorySize(bundlePath)
+	if err != nil {
+		details = append(details, ValidationDetail{
+			Check:    "Linux bundle目录大小计算",
+			Status:   "warning",
+			Message:  fmt.Sprintf("无法计算目录大小: %v", err),
+			Critical: false,
+		})
+	} else {
+		sizeOK, sizeMsg := v.checkFileSize(dirSize, config.MinFileSize, config.MaxFileSize)
+		if !sizeOK {
+			details = append(details, ValidationDetail{
+				Check:    "Linux bundle目录大小检查",
+				Status:   "failed",
+				Message:  sizeMsg,
+				Critical: true,
+			})
+			success = false
+		} else {
+			details = append(details, ValidationDetail{
+				Check:    "Linux bundle目录大小检查",
+				Status:   "success",
+				Message:  sizeMsg,
+				Critical: false,
+			})
+		}
+	}
+
+	// 3. 检查ELF可执行文件
+	executable, execErr := v.findLinuxExecutable(bundlePath)
+	if execErr != nil {
+		details = append(details, ValidationDetail{
+			Check:    "ELF可执行文件检查",
+			Status:   "failed",
+			Message:  execErr.Error(),
+			Critical: true,
+		})
+		success = false
+	} else {
+		details = append(details, ValidationDetail{
+			Check:    "ELF可执行文件检查",
+			Status:   "success",
+			Message:  fmt.Sprintf("%s 可执行", filepath.Base(executable)),
+			Critical: true,
+		})
+	}
+
+	// 4. 检查必要文件（debug模式使用JIT，Dart代码以kernel_blob.bin形式存在）
+	appCode := filepath.Join("lib", "libapp.so")
+	if normalizeBuildMode(config.BuildMode) == BuildModeDebug {
+		appCode = filepath.Join("data", "flutter_assets", "kernel_blob.bin")
+	}
+	requiredPaths := []string{
+		appCode,
+		filepath.Join("lib", "libflutter_linux_gtk.so"),
+		filepath.Join("data", "flutter_assets"),
+	}
+	for _, requiredPath := range requiredPaths {
+		displayName := filepath.ToSlash(requiredPath)
+		if pathExists, _, _ := v.checkFileExists(filepath.Join(bundlePath, requiredPath)); pathExists {
+			details = append(details, ValidationDetail{
+				Check:    fmt.Sprintf("必要文件检查 (%s)", displayName),
+				Status:   "success",
+				Message:  fmt.Sprintf("%s 存在", displayName),
+				Critical: true,
+			})
+		} else {
+			details = append(details, ValidationDetail{
+				Check:    fmt.Sprintf("必要文件检查 (%s)", displayName),
+				Status:   "failed",
+				Message:  fmt.Sprintf("%s 不存在", displayName),
+				Critical: true,
+			})
+			success = false
+		}
+	}
+
+	var resultErr error
+	if !success {
+		resultErr = fmt.Errorf("Linux bundle验证失败")
+	}
+
+	return v.createValidationResult(success, bundlePath, dirSize, details, resultErr), resultErr
+}
+
+// findLinuxExecutable 在bundle根目录查找具有执行权限的ELF文件
+func (v *ArtifactValidatorImpl) findLinuxExecutable(bundlePath string) (string, error) {
+	entries, err := os.ReadDir(bundlePath)
+	if err != nil {
+		return "", fmt.Errorf("读取bundle目录失败: %w", err)
+	}
+
+	var nonExecutable string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(bundlePath, entry.Name())
+		if !isELFFile(path) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.Mode()&0111 != 0 {
+			return path, nil
+		}
+		nonExecutable = entry.Name()
+	}
+
+	if nonExecutable != "" {
+		return "", fmt.Errorf("%s 没有执行权限", nonExecutable)
+	}
+	return "", fmt.Errorf("bundle根目录中未找到ELF可执行文件")
+}
+
+// isELFFile 检查文件是否为ELF格式
+func isELFFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	header := make([]byte, len(elfMagic))
+	if _, err := io.ReadFull(file, header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, elfMagic)
+}
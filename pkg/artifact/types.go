@@ -8,16 +8,30 @@ import (
 type Platform string
 
 const (
-	PlatformAPK Platform = "apk"
-	PlatformIOS Platform = "ios"
+	PlatformAPK   Platform = "apk"
+	PlatformAAB   Platform = "aab"
+	PlatformIOS   Platform = "ios"
+	PlatformWeb   Platform = "web"
+	PlatformLinux Platform = "linux"
+)
+
+// BuildMode 构建模式
+type BuildMode string
+
+const (
+	BuildModeDebug   BuildMode = "debug"
+	BuildModeProfile BuildMode = "profile"
+	BuildModeRelease BuildMode = "release"
 )
 
 // ArtifactValidationConfig 产物验证配置
 type ArtifactValidationConfig struct {
-	EnableValidation     bool  // 是否启用产物验证（默认: true）
-	EnableIntegrityCheck bool  // 是否启用完整性检查（默认: true）
-	CustomMinSize        int64 // 自定义最小文件大小（0表示使用默认值）
-	CustomMaxSize        int64 // 自定义最大文件大小（0表示使用默认值）
+	EnableValidation     bool                 // 是否启用产物验证（默认: true）
+	EnableIntegrityCheck bool                 // 是否启用完整性检查（默认: true）
+	CustomMinSize        int64                // 自定义最小文件大小（0表示使用默认值）
+	CustomMaxSize        int64                // 自定义最大文件大小（0表示使用默认值）
+	EnableSHA512         bool                 // 是否同时计算SHA-512（默认: false）
+	ExpectedManifest     *ManifestExpectation // APK清单的预期值（可选，包名、versionCode 等不符时验证失败）
 }
 
 // ArtifactConfig 产物验证配置
@@ -30,15 +44,35 @@ type ArtifactConfig struct {
 	MaxFileSize       int64                     // 最大文件大小
 	ValidateIntegrity bool                      // 是否验证完整性
 	ValidationConfig  *ArtifactValidationConfig // 验证配置（可选）
+	SplitPerABI       bool                      // 是否为按ABI拆分的APK构建
+	Flavor            string                    // 产品风味（可选，影响产物文件名）
+	BuildMode         BuildMode                 // 构建模式（为空时视为release）
+	DebugInfoDir      string                    // 调试信息目录（--split-debug-info，可选），存在时一并计算校验和
+	LinuxArch         string                    // Linux 目标架构（x64、arm64，为空时为当前主机架构）
 }
 
 // ValidationResult 验证结果
 type ValidationResult struct {
-	Success           bool                // 验证是否成功
-	ArtifactPath      string              // 产物文件路径
-	FileSize          int64               // 文件大小
-	ValidationDetails []ValidationDetail  // 验证详情
-	Error             error               // 错误信息
+	Success           bool               // 验证是否成功
+	ArtifactPath      string             // 产物文件路径
+	FileSize          int64              // 文件大小
+	ValidationDetails []ValidationDetail // 验证详情
+	Error             error              // 错误信息
+	Artifacts         []ArtifactFile     // 产物文件列表（拆分构建时包含多个，调试信息目录也在其中）
+	ChecksumFiles     []string           // 构建器写出的校验和清单（SHA256SUMS、SHA512SUMS）路径
+	Manifest          *AndroidManifest   // APK清单信息（仅APK，拆分构建时为第一个APK的清单）
+}
+
+// ArtifactFile 单个产物文件信息
+type ArtifactFile struct {
+	ABI       string           // 目标架构（如 arm64-v8a，未拆分时为空）
+	Path      string           // 文件路径
+	Size      int64            // 文件大小（目录为其中文件的总大小）
+	Checksum  string           // SHA-256 校验和（目录为树哈希，见 TreeSHA256）
+	SHA512    string           // SHA-512 校验和（启用 EnableSHA512 时）
+	Dir       bool             // 是否为目录产物（Web、Linux bundle、iOS App）
+	DebugInfo bool             // 是否为调试信息目录
+	Manifest  *AndroidManifest // APK清单信息（仅APK）
 }
 
 // ValidationDetail 验证详情
@@ -61,6 +95,17 @@ const (
 	// iOS IPA 默认大小限制
 	DefaultIOSIPAMinSize = 10 * 1024 * 1024      // 10MB
 	DefaultIOSIPAMaxSize = 4 * 1024 * 1024 * 1024 // 4GB
+
+	// Web 默认大小限制（build/web 目录总大小，最大值即体积预算）
+	DefaultWebMinSize = 1 * 1024 * 1024   // 1MB
+	DefaultWebMaxSize = 100 * 1024 * 1024 // 100MB
+
+	// Linux bundle 默认大小限制
+	DefaultLinuxMinSize = 10 * 1024 * 1024   // 10MB
+	DefaultLinuxMaxSize = 1024 * 1024 * 1024 // 1GB
+
+	// debug 模式默认大小上限放宽倍数（未优化的JIT产物）
+	DebugSizeLimitFactor = 2
 )
 
 // GetDefaultValidationConfig 获取默认验证配置
@@ -81,12 +126,24 @@ type ArtifactValidator interface {
 	// GetExpectedPaths 获取预期的产物路径
 	GetExpectedPaths(platform Platform, sourcePath string, iosConfig *types.IOSConfig) ([]string, error)
 
+	// GetExpectedPathsForConfig 按验证配置获取预期的产物路径（考虑 flavor、构建模式和目标架构）
+	GetExpectedPathsForConfig(config *ArtifactConfig) ([]string, error)
+
 	// ValidateAPK 验证Android APK文件
 	ValidateAPK(apkPath string, config *ArtifactConfig) (*ValidationResult, error)
 
+	// ValidateAAB 验证Android App Bundle文件
+	ValidateAAB(aabPath string, config *ArtifactConfig) (*ValidationResult, error)
+
 	// ValidateIPA 验证iOS IPA文件
 	ValidateIPA(ipaPath string, config *ArtifactConfig) (*ValidationResult, error)
 
 	// ValidateIOSApp 验证iOS App目录
 	ValidateIOSApp(appPath string, config *ArtifactConfig) (*ValidationResult, error)
+
+	// ValidateWeb 验证Flutter Web构建目录
+	ValidateWeb(webDir string, config *ArtifactConfig) (*ValidationResult, error)
+
+	// ValidateLinuxBundle 验证Linux桌面bundle目录
+	ValidateLinuxBundle(bundlePath string, config *ArtifactConfig) (*ValidationResult, error)
 }
\ No newline at end of file
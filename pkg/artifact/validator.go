@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/mimicode/flutterbuilder/pkg/types"
 )
@@ -34,7 +33,7 @@ func (v *ArtifactValidatorImpl) ValidateArtifact(config *ArtifactConfig) (*Valid
 	}
 
 	// 获取预期的产物路径
-	expectedPaths, err := v.GetExpectedPaths(config.Platform, config.SourcePath, config.IOSConfig)
+	expectedPaths, err := v.GetExpectedPathsForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("获取预期产物路径失败: %w", err)
 	}
@@ -43,36 +42,176 @@ func (v *ArtifactValidatorImpl) ValidateArtifact(config *ArtifactConfig) (*Valid
 	v.setDefaultSizeLimits(config)
 
 	// 根据平台执行相应的验证
+	var result *ValidationResult
 	switch config.Platform {
 	case PlatformAPK:
-		return v.validateAndroidArtifacts(expectedPaths, config)
+		if config.SplitPerABI {
+			result, err = v.validateSplitAPKs(filepath.Dir(expectedPaths[0]), config)
+		} else {
+			result, err = v.validateAndroidArtifacts(expectedPaths, config)
+		}
+	case PlatformAAB:
+		result, err = v.ValidateAAB(expectedPaths[0], config)
 	case PlatformIOS:
-		return v.validateIOSArtifacts(expectedPaths, config)
+		result, err = v.validateIOSArtifacts(expectedPaths, config)
+	case PlatformWeb:
+		result, err = v.ValidateWeb(expectedPaths[0], config)
+	case PlatformLinux:
+		result, err = v.validateLinuxArtifacts(expectedPaths, config)
 	default:
 		return nil, fmt.Errorf("不支持的平台: %s", config.Platform)
 	}
+
+	if result != nil && result.Success {
+		v.collectArtifacts(result, config)
+	}
+
+	return result, err
+}
+
+// collectArtifacts 将单文件产物（APK/AAB/IPA）、目录产物（Web、Linux bundle、iOS App）和调试信息目录
+// 记录到产物列表并计算校验和，校验和计算失败时记录为非关键检查项
+func (v *ArtifactValidatorImpl) collectArtifacts(result *ValidationResult, config *ArtifactConfig) {
+	warn := func(check string, err error) {
+		result.ValidationDetails = append(result.ValidationDetails, ValidationDetail{
+			Check:   check,
+			Status:  "warning",
+			Message: fmt.Sprintf("计算校验和失败: %v", err),
+		})
+	}
+
+	if len(result.Artifacts) == 0 && result.ArtifactPath != "" {
+		if info, err := os.Stat(result.ArtifactPath); err == nil {
+			var artifactFile *ArtifactFile
+			if info.IsDir() {
+				artifactFile, err = NewArtifactDir(result.ArtifactPath)
+			} else {
+				artifactFile, err = NewArtifactFile(result.ArtifactPath, "")
+			}
+			if err != nil {
+				warn("产物校验和", err)
+			} else {
+				artifactFile.Manifest = result.Manifest
+				result.Artifacts = append(result.Artifacts, *artifactFile)
+			}
+		}
+	}
+
+	if dir := config.DebugInfoDir; dir != "" {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(config.SourcePath, dir)
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			if debugInfo, err := NewArtifactDir(dir); err != nil {
+				warn("调试信息校验和", err)
+			} else {
+				debugInfo.DebugInfo = true
+				result.Artifacts = append(result.Artifacts, *debugInfo)
+			}
+		}
+	}
+
+	if config.ValidationConfig != nil && config.ValidationConfig.EnableSHA512 {
+		for i := range result.Artifacts {
+			if err := result.Artifacts[i].fillSHA512(); err != nil {
+				warn("SHA-512 校验和", err)
+			}
+		}
+	}
+}
+
+// ResolveArtifacts 不做验证，直接定位预期产物并计算校验和（产物验证禁用时用于记录构建信息）
+func ResolveArtifacts(config *ArtifactConfig) ([]ArtifactFile, error) {
+	v := &ArtifactValidatorImpl{}
+	expectedPaths, err := v.GetExpectedPathsForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ValidationResult{ArtifactPath: expectedPaths[0]}
+	switch {
+	case config.Platform == PlatformAPK && config.SplitPerABI:
+		apkDir := filepath.Dir(expectedPaths[0])
+		apks, err := FindSplitAPKs(apkDir, config.Flavor, config.BuildMode)
+		if err != nil {
+			return nil, err
+		}
+		if len(apks) == 0 {
+			return nil, fmt.Errorf("在目录 %s 中未找到按ABI拆分的APK", apkDir)
+		}
+		for _, abi := range AndroidABIs {
+			if apkPath, ok := apks[abi]; ok {
+				artifactFile, err := NewArtifactFile(apkPath, abi)
+				if err != nil {
+					return nil, err
+				}
+				result.Artifacts = append(result.Artifacts, *artifactFile)
+			}
+		}
+	case config.Platform == PlatformIOS && config.IOSConfig != nil && config.IOSConfig.TeamID != "":
+		if result.ArtifactPath, err = v.findIPAFile(expectedPaths[0], config.Flavor); err != nil {
+			return nil, err
+		}
+	case config.Platform == PlatformIOS:
+		if found, err := FindIOSApp(filepath.Dir(expectedPaths[0]), config.Flavor); err == nil {
+			result.ArtifactPath = found
+		}
+	}
+	if len(result.Artifacts) == 0 {
+		if _, err := os.Stat(result.ArtifactPath); err != nil {
+			return nil, fmt.Errorf("未找到构建产物: %w", err)
+		}
+	}
+
+	v.collectArtifacts(result, config)
+	// collectArtifacts 只会记录校验和计算失败的警告
+	if len(result.ValidationDetails) > 0 {
+		detail := result.ValidationDetails[0]
+		return result.Artifacts, fmt.Errorf("%s: %s", detail.Check, detail.Message)
+	}
+	return result.Artifacts, nil
 }
 
-// GetExpectedPaths 获取预期的产物路径
+// GetExpectedPaths 获取预期的产物路径（默认 flavor 和 release 模式）
 func (v *ArtifactValidatorImpl) GetExpectedPaths(platform Platform, sourcePath string, iosConfig *types.IOSConfig) ([]string, error) {
-	switch platform {
+	return v.GetExpectedPathsForConfig(&ArtifactConfig{Platform: platform, SourcePath: sourcePath, IOSConfig: iosConfig})
+}
+
+// GetExpectedPathsForConfig 按验证配置获取预期的产物路径
+func (v *ArtifactValidatorImpl) GetExpectedPathsForConfig(config *ArtifactConfig) ([]string, error) {
+	if config == nil {
+		return nil, fmt.Errorf("验证配置不能为空")
+	}
+
+	sourcePath := config.SourcePath
+	switch config.Platform {
 	case PlatformAPK:
 		return []string{
-			filepath.Join(sourcePath, "build", "app", "outputs", "flutter-apk", "app-release.apk"),
+			filepath.Join(sourcePath, "build", "app", "outputs", "flutter-apk", APKFileName(config.Flavor, "", config.BuildMode)),
+		}, nil
+	case PlatformAAB:
+		return []string{
+			filepath.Join(sourcePath, "build", "app", "outputs", "bundle", AndroidVariantName(config.Flavor, config.BuildMode), AABFileName(config.Flavor, config.BuildMode)),
 		}, nil
 	case PlatformIOS:
-		if iosConfig != nil && iosConfig.TeamID != "" {
+		if config.IOSConfig != nil && config.IOSConfig.TeamID != "" {
 			// 有证书配置，构建IPA
 			ipaDir := filepath.Join(sourcePath, "build", "ios", "ipa")
 			return []string{ipaDir}, nil // 返回目录，稍后搜索.ipa文件
 		} else {
-			// 无证书配置，仅构建iOS App
+			// 无证书配置，仅构建iOS App（带flavor时.app名称可能不同，验证时再查找）
 			return []string{
 				filepath.Join(sourcePath, "build", "ios", "iphoneos", "Runner.app"),
 			}, nil
 		}
+	case PlatformWeb:
+		return []string{
+			filepath.Join(sourcePath, "build", "web"),
+		}, nil
+	case PlatformLinux:
+		return []string{LinuxBundlePath(sourcePath, config.LinuxArch, config.BuildMode)}, nil
 	default:
-		return nil, fmt.Errorf("不支持的平台: %s", platform)
+		return nil, fmt.Errorf("不支持的平台: %s", config.Platform)
 	}
 }
 
@@ -88,10 +227,13 @@ func (v *ArtifactValidatorImpl) setDefaultSizeLimits(config *ArtifactConfig) {
 		}
 	}
 
+	// debug产物未经优化，默认上限按倍数放宽（自定义上限不受影响）
+	relaxMaxSize := config.MaxFileSize == 0 && normalizeBuildMode(config.BuildMode) == BuildModeDebug
+
 	// 如果仍未设置，使用平台默认值
 	if config.MinFileSize == 0 || config.MaxFileSize == 0 {
 		switch config.Platform {
-		case PlatformAPK:
+		case PlatformAPK, PlatformAAB:
 			if config.MinFileSize == 0 {
 				config.MinFileSize = DefaultAndroidMinSize
 			}
@@ -116,8 +258,26 @@ func (v *ArtifactValidatorImpl) setDefaultSizeLimits(config *ArtifactConfig) {
 					config.MaxFileSize = DefaultIOSAppMinSize * 10 // 500MB 上限
 				}
 			}
+		case PlatformWeb:
+			if config.MinFileSize == 0 {
+				config.MinFileSize = DefaultWebMinSize
+			}
+			if config.MaxFileSize == 0 {
+				config.MaxFileSize = DefaultWebMaxSize
+			}
+		case PlatformLinux:
+			if config.MinFileSize == 0 {
+				config.MinFileSize = DefaultLinuxMinSize
+			}
+			if config.MaxFileSize == 0 {
+				config.MaxFileSize = DefaultLinuxMaxSize
+			}
 		}
 	}
+
+	if relaxMaxSize {
+		config.MaxFileSize *= DebugSizeLimitFactor
+	}
 }
 
 // validateAndroidArtifacts 验证Android产物
@@ -131,7 +291,7 @@ func (v *ArtifactValidatorImpl) validateIOSArtifacts(expectedPaths []string, con
 	if config.IOSConfig != nil && config.IOSConfig.TeamID != "" {
 		// 验证IPA文件
 		ipaDir := expectedPaths[0]
-		ipaPath, err := v.findIPAFile(ipaDir)
+		ipaPath, err := v.findIPAFile(ipaDir, config.Flavor)
 		if err != nil {
 			return &ValidationResult{
 				Success: false,
@@ -145,24 +305,26 @@ func (v *ArtifactValidatorImpl) validateIOSArtifacts(expectedPaths []string, con
 	} else {
 		// 验证iOS App目录
 		appPath := expectedPaths[0]
+		if found, err := FindIOSApp(filepath.Dir(appPath), config.Flavor); err == nil {
+			appPath = found
+		}
 		return v.ValidateIOSApp(appPath, config)
 	}
 }
 
 // findIPAFile 在指定目录中查找IPA文件
-func (v *ArtifactValidatorImpl) findIPAFile(ipaDir string) (string, error) {
-	files, err := os.ReadDir(ipaDir)
-	if err != nil {
-		return "", fmt.Errorf("读取IPA目录失败: %w", err)
+func (v *ArtifactValidatorImpl) findIPAFile(ipaDir, flavor string) (string, error) {
+	ipaPath, err := FindIPAFile(ipaDir, flavor)
+	if err == nil {
+		return ipaPath, nil
 	}
-
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(strings.ToLower(file.Name()), ".ipa") {
-			return filepath.Join(ipaDir, file.Name()), nil
-		}
+	if _, statErr := os.Stat(ipaDir); statErr != nil {
+		return "", fmt.Errorf("读取IPA目录失败: %w", statErr)
 	}
-
-	return "", fmt.Errorf("在目录 %s 中未找到.ipa文件", ipaDir)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("在目录 %s 中未找到.ipa文件", ipaDir)
+	}
+	return "", err
 }
 
 // checkFileExists 检查文件是否存在
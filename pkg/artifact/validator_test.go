@@ -101,6 +101,141 @@ func TestArtifactValidator_ValidateAPK(t *testing.T) {
 	})
 }
 
+func TestArtifactValidator_SplitPerABI(t *testing.T) {
+	validator := NewArtifactValidator()
+
+	t.Run("验证所有拆分APK", func(t *testing.T) {
+		tempDir := t.TempDir()
+		apkDir := filepath.Join(tempDir, "build", "app", "outputs", "flutter-apk")
+		if err := os.MkdirAll(apkDir, 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		for _, abi := range []string{"arm64-v8a", "armeabi-v7a", "x86_64"} {
+			if err := createTestAPK(filepath.Join(apkDir, "app-"+abi+"-release.apk")); err != nil {
+				t.Fatalf("创建测试APK失败: %v", err)
+			}
+		}
+
+		config := &ArtifactConfig{
+			Platform:          PlatformAPK,
+			SourcePath:        tempDir,
+			ValidateIntegrity: true,
+			SplitPerABI:       true,
+			ValidationConfig:  &ArtifactValidationConfig{EnableValidation: true, CustomMinSize: 100},
+		}
+
+		result, err := validator.ValidateArtifact(config)
+		if err != nil {
+			t.Fatalf("验证拆分APK失败: %v", err)
+		}
+		if len(result.Artifacts) != 3 {
+			t.Fatalf("预期3个产物，实际得到%d个", len(result.Artifacts))
+		}
+		if result.Artifacts[0].ABI != "arm64-v8a" {
+			t.Errorf("产物应按ABI排序，实际第一个为%s", result.Artifacts[0].ABI)
+		}
+		for _, file := range result.Artifacts {
+			if file.Size == 0 || len(file.Checksum) != 64 {
+				t.Errorf("产物信息不完整: %+v", file)
+			}
+		}
+	})
+
+	t.Run("未找到拆分APK", func(t *testing.T) {
+		tempDir := t.TempDir()
+		apkDir := filepath.Join(tempDir, "build", "app", "outputs", "flutter-apk")
+		if err := os.MkdirAll(apkDir, 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+
+		config := &ArtifactConfig{
+			Platform:    PlatformAPK,
+			SourcePath:  tempDir,
+			SplitPerABI: true,
+		}
+
+		result, err := validator.ValidateArtifact(config)
+		if err == nil || result.Success {
+			t.Error("预期验证应该失败")
+		}
+	})
+}
+
+func TestArtifactValidator_ValidateAAB(t *testing.T) {
+	validator := NewArtifactValidator()
+
+	t.Run("AAB文件不存在", func(t *testing.T) {
+		config := &ArtifactConfig{
+			Platform:          PlatformAAB,
+			SourcePath:        "/nonexistent",
+			MinFileSize:       DefaultAndroidMinSize,
+			MaxFileSize:       DefaultAndroidMaxSize,
+			ValidateIntegrity: false,
+		}
+
+		result, err := validator.ValidateAAB("/nonexistent/app-release.aab", config)
+		if err == nil {
+			t.Error("预期应该返回错误")
+		}
+		if result.Success {
+			t.Error("预期验证应该失败")
+		}
+	})
+
+	t.Run("创建和验证有效AAB", func(t *testing.T) {
+		tempDir := t.TempDir()
+		aabPath := filepath.Join(tempDir, "app-release.aab")
+
+		if err := createTestAAB(aabPath, true); err != nil {
+			t.Fatalf("创建测试AAB失败: %v", err)
+		}
+
+		config := &ArtifactConfig{
+			Platform:          PlatformAAB,
+			SourcePath:        tempDir,
+			MinFileSize:       100,
+			MaxFileSize:       DefaultAndroidMaxSize,
+			ValidateIntegrity: true,
+		}
+
+		result, err := validator.ValidateAAB(aabPath, config)
+		if err != nil {
+			t.Errorf("验证AAB失败: %v", err)
+		}
+		if !result.Success {
+			t.Error("预期验证应该成功")
+			for _, detail := range result.ValidationDetails {
+				t.Logf("  %s: %s - %s", detail.Check, detail.Status, detail.Message)
+			}
+		}
+	})
+
+	t.Run("AAB缺少native库", func(t *testing.T) {
+		tempDir := t.TempDir()
+		aabPath := filepath.Join(tempDir, "app-release.aab")
+
+		if err := createTestAAB(aabPath, false); err != nil {
+			t.Fatalf("创建测试AAB失败: %v", err)
+		}
+
+		config := &ArtifactConfig{
+			Platform:          PlatformAAB,
+			SourcePath:        tempDir,
+			MinFileSize:       100,
+			MaxFileSize:       DefaultAndroidMaxSize,
+			ValidateIntegrity: true,
+		}
+
+		result, err := validator.ValidateAAB(aabPath, config)
+		if err == nil {
+			t.Error("预期应该返回错误")
+		}
+		if result.Success {
+			t.Error("预期验证应该失败（缺少base/lib）")
+		}
+	})
+}
+
 func TestArtifactValidator_ValidateIOSApp(t *testing.T) {
 	validator := NewArtifactValidator()
 
@@ -220,6 +355,196 @@ func TestArtifactValidator_ValidateIPA(t *testing.T) {
 	})
 }
 
+func TestArtifactValidator_ValidateWeb(t *testing.T) {
+	validator := NewArtifactValidator()
+
+	t.Run("Web构建目录不存在", func(t *testing.T) {
+		config := &ArtifactConfig{
+			Platform:    PlatformWeb,
+			SourcePath:  "/nonexistent",
+			MinFileSize: DefaultWebMinSize,
+			MaxFileSize: DefaultWebMaxSize,
+		}
+
+		result, err := validator.ValidateWeb("/nonexistent/build/web", config)
+		if err == nil {
+			t.Error("预期应该返回错误")
+		}
+		if result.Success {
+			t.Error("预期验证应该失败")
+		}
+	})
+
+	t.Run("创建和验证有效Web目录", func(t *testing.T) {
+		webDir := filepath.Join(t.TempDir(), "web")
+		if err := createTestWebBuild(webDir, true); err != nil {
+			t.Fatalf("创建测试Web目录失败: %v", err)
+		}
+
+		config := &ArtifactConfig{
+			Platform:          PlatformWeb,
+			SourcePath:        filepath.Dir(webDir),
+			MinFileSize:       100,
+			MaxFileSize:       DefaultWebMaxSize,
+			ValidateIntegrity: true,
+		}
+
+		result, err := validator.ValidateWeb(webDir, config)
+		if err != nil {
+			t.Errorf("验证Web目录失败: %v", err)
+		}
+		if !result.Success {
+			t.Error("预期验证应该成功")
+			for _, detail := range result.ValidationDetails {
+				t.Logf("  %s: %s - %s", detail.Check, detail.Status, detail.Message)
+			}
+		}
+		if result.FileSize == 0 {
+			t.Error("预期目录大小应该大于0")
+		}
+	})
+
+	t.Run("Web目录缺少资源清单", func(t *testing.T) {
+		webDir := filepath.Join(t.TempDir(), "web")
+		if err := createTestWebBuild(webDir, false); err != nil {
+			t.Fatalf("创建测试Web目录失败: %v", err)
+		}
+
+		config := &ArtifactConfig{
+			Platform:    PlatformWeb,
+			SourcePath:  filepath.Dir(webDir),
+			MinFileSize: 100,
+			MaxFileSize: DefaultWebMaxSize,
+		}
+
+		result, _ := validator.ValidateWeb(webDir, config)
+		if result.Success {
+			t.Error("预期验证应该失败（缺少资源清单）")
+		}
+	})
+
+	t.Run("Web构建超出体积预算", func(t *testing.T) {
+		webDir := filepath.Join(t.TempDir(), "web")
+		if err := createTestWebBuild(webDir, true); err != nil {
+			t.Fatalf("创建测试Web目录失败: %v", err)
+		}
+
+		config := &ArtifactConfig{
+			Platform:    PlatformWeb,
+			SourcePath:  filepath.Dir(webDir),
+			MinFileSize: 1,
+			MaxFileSize: 10, // 10字节预算
+		}
+
+		result, _ := validator.ValidateWeb(webDir, config)
+		if result.Success {
+			t.Error("预期验证应该失败（超出体积预算）")
+		}
+	})
+}
+
+func TestArtifactValidator_ValidateLinuxBundle(t *testing.T) {
+	validator := NewArtifactValidator()
+
+	t.Run("Linux bundle目录不存在", func(t *testing.T) {
+		config := &ArtifactConfig{
+			Platform:    PlatformLinux,
+			SourcePath:  "/nonexistent",
+			MinFileSize: DefaultLinuxMinSize,
+			MaxFileSize: DefaultLinuxMaxSize,
+		}
+
+		result, err := validator.ValidateLinuxBundle("/nonexistent/bundle", config)
+		if err == nil {
+			t.Error("预期应该返回错误")
+		}
+		if result.Success {
+			t.Error("预期验证应该失败")
+		}
+	})
+
+	t.Run("创建和验证有效Linux bundle", func(t *testing.T) {
+		tempDir := t.TempDir()
+		config := &ArtifactConfig{
+			Platform:         PlatformLinux,
+			SourcePath:       tempDir,
+			ValidationConfig: &ArtifactValidationConfig{EnableValidation: true, CustomMinSize: 10},
+		}
+		paths, err := validator.GetExpectedPathsForConfig(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bundlePath := paths[0]
+		if bundlePath != LinuxBundlePath(tempDir, LinuxArch(""), BuildModeRelease) {
+			t.Errorf("未指定架构时应使用当前主机架构的bundle，实际 %s", bundlePath)
+		}
+		if err := createTestLinuxBundle(bundlePath, 0755); err != nil {
+			t.Fatalf("创建测试Linux bundle失败: %v", err)
+		}
+
+		result, err := validator.ValidateArtifact(config)
+		if err != nil {
+			t.Errorf("验证Linux bundle失败: %v", err)
+		}
+		if !result.Success {
+			t.Error("预期验证应该成功")
+			for _, detail := range result.ValidationDetails {
+				t.Logf("  %s: %s - %s", detail.Check, detail.Status, detail.Message)
+			}
+		}
+		if result.ArtifactPath != bundlePath {
+			t.Errorf("预期产物路径%s，实际得到%s", bundlePath, result.ArtifactPath)
+		}
+	})
+
+	t.Run("只验证目标架构的bundle", func(t *testing.T) {
+		tempDir := t.TempDir()
+		// 未执行 clean 时遗留的 x64 bundle 不应被当作 arm64 构建的产物
+		if err := createTestLinuxBundle(LinuxBundlePath(tempDir, "x64", BuildModeRelease), 0755); err != nil {
+			t.Fatalf("创建测试Linux bundle失败: %v", err)
+		}
+
+		config := &ArtifactConfig{
+			Platform:         PlatformLinux,
+			SourcePath:       tempDir,
+			LinuxArch:        LinuxArch("linux-arm64"),
+			ValidationConfig: &ArtifactValidationConfig{EnableValidation: true, CustomMinSize: 10},
+		}
+		result, err := validator.ValidateArtifact(config)
+		if err == nil || result.Success {
+			t.Error("目标架构的bundle不存在时验证应失败")
+		}
+
+		arm64Path := LinuxBundlePath(tempDir, "arm64", BuildModeRelease)
+		if err := createTestLinuxBundle(arm64Path, 0755); err != nil {
+			t.Fatalf("创建测试Linux bundle失败: %v", err)
+		}
+		result, err = validator.ValidateArtifact(config)
+		if err != nil || result.ArtifactPath != arm64Path {
+			t.Errorf("预期验证 %s，实际得到 %s（%v）", arm64Path, result.ArtifactPath, err)
+		}
+	})
+
+	t.Run("可执行文件缺少执行权限", func(t *testing.T) {
+		bundlePath := filepath.Join(t.TempDir(), "bundle")
+		if err := createTestLinuxBundle(bundlePath, 0644); err != nil {
+			t.Fatalf("创建测试Linux bundle失败: %v", err)
+		}
+
+		config := &ArtifactConfig{
+			Platform:    PlatformLinux,
+			SourcePath:  filepath.Dir(bundlePath),
+			MinFileSize: 10,
+			MaxFileSize: DefaultLinuxMaxSize,
+		}
+
+		result, _ := validator.ValidateLinuxBundle(bundlePath, config)
+		if result.Success {
+			t.Error("预期验证应该失败（可执行文件无执行权限）")
+		}
+	})
+}
+
 func TestArtifactValidator_GetExpectedPaths(t *testing.T) {
 	validator := NewArtifactValidator()
 
@@ -237,6 +562,28 @@ func TestArtifactValidator_GetExpectedPaths(t *testing.T) {
 		}
 	})
 
+	t.Run("Android App Bundle", func(t *testing.T) {
+		paths, err := validator.GetExpectedPathsForConfig(&ArtifactConfig{Platform: PlatformAAB, SourcePath: "/test/project"})
+		if err != nil {
+			t.Errorf("获取AAB预期路径失败: %v", err)
+		}
+		expectedPath := filepath.Join("/test/project", "build", "app", "outputs", "bundle", "release", "app-release.aab")
+		if len(paths) != 1 || paths[0] != expectedPath {
+			t.Errorf("预期路径%s，实际得到%v", expectedPath, paths)
+		}
+	})
+
+	t.Run("Web平台", func(t *testing.T) {
+		paths, err := validator.GetExpectedPathsForConfig(&ArtifactConfig{Platform: PlatformWeb, SourcePath: "/test/project"})
+		if err != nil {
+			t.Errorf("获取Web预期路径失败: %v", err)
+		}
+		expectedPath := filepath.Join("/test/project", "build", "web")
+		if len(paths) != 1 || paths[0] != expectedPath {
+			t.Errorf("预期路径%s，实际得到%v", expectedPath, paths)
+		}
+	})
+
 	t.Run("iOS平台无证书", func(t *testing.T) {
 		paths, err := validator.GetExpectedPaths(PlatformIOS, "/test/project", nil)
 		if err != nil {
@@ -268,6 +615,178 @@ func TestArtifactValidator_GetExpectedPaths(t *testing.T) {
 			t.Errorf("预期路径%s，实际得到%s", expectedPath, paths[0])
 		}
 	})
+
+	t.Run("带flavor的Android产物", func(t *testing.T) {
+		paths, err := validator.GetExpectedPathsForConfig(&ArtifactConfig{Platform: PlatformAPK, SourcePath: "/test/project", Flavor: "prod"})
+		if err != nil {
+			t.Errorf("获取APK预期路径失败: %v", err)
+		}
+		expectedPath := filepath.Join("/test/project", "build", "app", "outputs", "flutter-apk", "app-prod-release.apk")
+		if len(paths) != 1 || paths[0] != expectedPath {
+			t.Errorf("预期路径%s，实际得到%v", expectedPath, paths)
+		}
+
+		paths, err = validator.GetExpectedPathsForConfig(&ArtifactConfig{Platform: PlatformAAB, SourcePath: "/test/project", Flavor: "prod"})
+		if err != nil {
+			t.Errorf("获取AAB预期路径失败: %v", err)
+		}
+		expectedPath = filepath.Join("/test/project", "build", "app", "outputs", "bundle", "prodRelease", "app-prod-release.aab")
+		if len(paths) != 1 || paths[0] != expectedPath {
+			t.Errorf("预期路径%s，实际得到%v", expectedPath, paths)
+		}
+	})
+}
+
+func TestArtifactValidator_Flavor(t *testing.T) {
+	t.Run("带flavor的拆分APK", func(t *testing.T) {
+		tempDir := t.TempDir()
+		apkDir := filepath.Join(tempDir, "build", "app", "outputs", "flutter-apk")
+		if err := os.MkdirAll(apkDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"app-arm64-v8a-prod-release.apk", "app-x86_64-prod-release.apk", "app-prod-release.apk"} {
+			if err := os.WriteFile(filepath.Join(apkDir, name), []byte("apk"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		apks, err := FindSplitAPKs(apkDir, "prod", BuildModeRelease)
+		if err != nil {
+			t.Fatalf("查找拆分APK失败: %v", err)
+		}
+		if len(apks) != 2 || apks["arm64-v8a"] == "" || apks["x86_64"] == "" {
+			t.Errorf("预期找到 arm64-v8a 和 x86_64，实际得到%v", apks)
+		}
+
+		// 未指定flavor时不把带flavor的APK当作拆分APK（prod、arm64-v8a-prod 不是架构）
+		apks, err = FindSplitAPKs(apkDir, "", BuildModeRelease)
+		if err != nil {
+			t.Fatalf("查找拆分APK失败: %v", err)
+		}
+		if len(apks) != 0 {
+			t.Errorf("预期未找到拆分APK，实际得到%v", apks)
+		}
+	})
+
+	t.Run("带flavor的iOS App", func(t *testing.T) {
+		tempDir := t.TempDir()
+		appDir := filepath.Join(tempDir, "build", "ios", "iphoneos")
+		for _, name := range []string{"Runner.app", "MyApp Dev.app"} {
+			if err := os.MkdirAll(filepath.Join(appDir, name), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		appPath, err := FindIOSApp(appDir, "dev")
+		if err != nil || filepath.Base(appPath) != "MyApp Dev.app" {
+			t.Errorf("预期找到 MyApp Dev.app，实际得到%s (%v)", appPath, err)
+		}
+
+		appPath, err = FindIOSApp(appDir, "")
+		if err != nil || filepath.Base(appPath) != "Runner.app" {
+			t.Errorf("预期找到 Runner.app，实际得到%s (%v)", appPath, err)
+		}
+	})
+
+	t.Run("flavor按单词匹配且不使用其他IPA兜底", func(t *testing.T) {
+		ipaDir := t.TempDir()
+		for _, name := range []string{"MyApp Developer.ipa", "MyApp Prod.ipa", "shopStaging.ipa"} {
+			if err := os.WriteFile(filepath.Join(ipaDir, name), []byte("ipa"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for flavor, want := range map[string]string{"prod": "MyApp Prod.ipa", "staging": "shopStaging.ipa", "my-app-prod": "MyApp Prod.ipa"} {
+			if ipaPath, err := FindIPAFile(ipaDir, flavor); err != nil || filepath.Base(ipaPath) != want {
+				t.Errorf("flavor %s 预期找到 %s，实际得到%s (%v)", flavor, want, ipaPath, err)
+			}
+		}
+		// dev 不匹配 Developer，也不回退到其他 flavor 的IPA
+		if ipaPath, err := FindIPAFile(ipaDir, "dev"); err == nil {
+			t.Errorf("预期找不到 dev 的IPA，实际得到%s", ipaPath)
+		}
+	})
+}
+
+func TestArtifactValidator_BuildMode(t *testing.T) {
+	validator := &ArtifactValidatorImpl{}
+
+	t.Run("按构建模式的预期路径", func(t *testing.T) {
+		paths, err := validator.GetExpectedPathsForConfig(&ArtifactConfig{Platform: PlatformAPK, SourcePath: "/test/project", BuildMode: BuildModeDebug})
+		if err != nil {
+			t.Errorf("获取APK预期路径失败: %v", err)
+		}
+		expectedPath := filepath.Join("/test/project", "build", "app", "outputs", "flutter-apk", "app-debug.apk")
+		if len(paths) != 1 || paths[0] != expectedPath {
+			t.Errorf("预期路径%s，实际得到%v", expectedPath, paths)
+		}
+
+		paths, err = validator.GetExpectedPathsForConfig(&ArtifactConfig{Platform: PlatformAAB, SourcePath: "/test/project", Flavor: "prod", BuildMode: BuildModeProfile})
+		if err != nil {
+			t.Errorf("获取AAB预期路径失败: %v", err)
+		}
+		expectedPath = filepath.Join("/test/project", "build", "app", "outputs", "bundle", "prodProfile", "app-prod-profile.aab")
+		if len(paths) != 1 || paths[0] != expectedPath {
+			t.Errorf("预期路径%s，实际得到%v", expectedPath, paths)
+		}
+
+		paths, err = validator.GetExpectedPathsForConfig(&ArtifactConfig{Platform: PlatformLinux, SourcePath: "/test/project", BuildMode: BuildModeDebug})
+		if err != nil {
+			t.Errorf("获取Linux预期路径失败: %v", err)
+		}
+		for _, path := range paths {
+			if filepath.Base(filepath.Dir(path)) != "debug" {
+				t.Errorf("预期debug模式bundle路径，实际得到%s", path)
+			}
+		}
+	})
+
+	t.Run("debug模式放宽默认大小上限", func(t *testing.T) {
+		config := &ArtifactConfig{Platform: PlatformAPK, BuildMode: BuildModeDebug}
+		validator.setDefaultSizeLimits(config)
+		if config.MaxFileSize != DefaultAndroidMaxSize*DebugSizeLimitFactor {
+			t.Errorf("预期上限%d，实际得到%d", int64(DefaultAndroidMaxSize*DebugSizeLimitFactor), config.MaxFileSize)
+		}
+		if config.MinFileSize != DefaultAndroidMinSize {
+			t.Errorf("预期下限%d，实际得到%d", DefaultAndroidMinSize, config.MinFileSize)
+		}
+
+		// 自定义上限不受构建模式影响
+		config = &ArtifactConfig{
+			Platform:         PlatformAPK,
+			BuildMode:        BuildModeDebug,
+			ValidationConfig: &ArtifactValidationConfig{EnableValidation: true, CustomMaxSize: 100 * 1024 * 1024},
+		}
+		validator.setDefaultSizeLimits(config)
+		if config.MaxFileSize != 100*1024*1024 {
+			t.Errorf("预期自定义上限保持不变，实际得到%d", config.MaxFileSize)
+		}
+	})
+
+	t.Run("debug模式Linux bundle使用kernel_blob.bin", func(t *testing.T) {
+		tempDir := t.TempDir()
+		bundlePath := LinuxBundlePath(tempDir, "", BuildModeDebug)
+		if err := createTestLinuxBundle(bundlePath, 0755); err != nil {
+			t.Fatalf("创建测试Linux bundle失败: %v", err)
+		}
+		if err := os.Remove(filepath.Join(bundlePath, "lib", "libapp.so")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(bundlePath, "data", "flutter_assets", "kernel_blob.bin"), []byte("kernel"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		config := &ArtifactConfig{Platform: PlatformLinux, SourcePath: tempDir, BuildMode: BuildModeDebug, MinFileSize: 1, MaxFileSize: DefaultLinuxMaxSize}
+		result, err := validator.ValidateLinuxBundle(bundlePath, config)
+		if err != nil || !result.Success {
+			t.Errorf("预期debug bundle验证成功，实际错误: %v", err)
+		}
+
+		config.BuildMode = BuildModeRelease
+		if result, _ := validator.ValidateLinuxBundle(bundlePath, config); result.Success {
+			t.Error("预期release模式缺少libapp.so时验证失败")
+		}
+	})
 }
 
 func TestValidationConfig(t *testing.T) {
@@ -364,6 +883,89 @@ func createTestAPK(apkPath string) error {
 	return nil
 }
 
+// 辅助函数：创建测试AAB文件
+func createTestAAB(aabPath string, withNativeLibs bool) error {
+	file, err := os.Create(aabPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zipWriter := zip.NewWriter(file)
+	defer zipWriter.Close()
+
+	entries := []string{
+		"BundleConfig.pb",
+		"base/manifest/AndroidManifest.xml",
+		"base/dex/classes.dex",
+		"base/resources.pb",
+		"base/assets/flutter_assets/AssetManifest.json",
+	}
+	if withNativeLibs {
+		entries = append(entries,
+			"base/lib/arm64-v8a/libapp.so",
+			"base/lib/arm64-v8a/libflutter.so",
+		)
+	}
+
+	for _, name := range entries {
+		w, err := zipWriter.Create(name)
+		if err != nil {
+			return err
+		}
+		w.Write([]byte("test content for " + name))
+	}
+
+	return nil
+}
+
+// 辅助函数：创建测试Web构建目录
+func createTestWebBuild(webDir string, withManifest bool) error {
+	if err := os.MkdirAll(filepath.Join(webDir, "assets"), 0755); err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		"index.html":                `<!DOCTYPE html><html><head><base href="/"></head><body><script src="flutter_bootstrap.js" async></script></body></html>`,
+		"main.dart.js":              "void main() {}",
+		"flutter_service_worker.js": "'use strict';",
+	}
+	if withManifest {
+		files[filepath.Join("assets", "AssetManifest.json")] = "{}"
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(webDir, name), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 辅助函数：创建测试Linux bundle目录
+func createTestLinuxBundle(bundlePath string, execMode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Join(bundlePath, "lib"), 0755); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(bundlePath, "data", "flutter_assets"), 0755); err != nil {
+		return err
+	}
+
+	executable := append([]byte{0x7f, 'E', 'L', 'F'}, []byte("fake executable")...)
+	if err := os.WriteFile(filepath.Join(bundlePath, "my_app"), executable, execMode); err != nil {
+		return err
+	}
+
+	for _, lib := range []string{"libapp.so", "libflutter_linux_gtk.so"} {
+		if err := os.WriteFile(filepath.Join(bundlePath, "lib", lib), []byte("fake library"), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // 辅助函数：创建测试iOS App目录
 func createTestIOSApp(appPath string) error {
 	// 创建目录
@@ -0,0 +1,145 @@
+package artifact
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// normalizeBuildMode 未指定构建模式时视为release
+func normalizeBuildMode(mode BuildMode) BuildMode {
+	if mode == "" {
+		return BuildModeRelease
+	}
+	return mode
+}
+
+// AndroidVariantName 返回Gradle构建变体名（如 release、debug、prodRelease、prodProfile）
+func AndroidVariantName(flavor string, mode BuildMode) string {
+	modeName := string(normalizeBuildMode(mode))
+	if flavor == "" {
+		return modeName
+	}
+	return flavor + strings.ToUpper(modeName[:1]) + modeName[1:]
+}
+
+// APKFileName 返回flutter-apk目录中的APK文件名
+// 例如 app-release.apk、app-prod-debug.apk、app-arm64-v8a-prod-release.apk
+func APKFileName(flavor, abi string, mode BuildMode) string {
+	name := "app"
+	if abi != "" {
+		name += "-" + abi
+	}
+	if flavor != "" {
+		name += "-" + flavor
+	}
+	return name + "-" + string(normalizeBuildMode(mode)) + ".apk"
+}
+
+// AABFileName 返回AAB文件名（如 app-release.aab、app-<flavor>-profile.aab）
+func AABFileName(flavor string, mode BuildMode) string {
+	name := "app"
+	if flavor != "" {
+		name += "-" + flavor
+	}
+	return name + "-" + string(normalizeBuildMode(mode)) + ".aab"
+}
+
+// FindIPAFile 在IPA目录中查找IPA文件，指定flavor时选择名称包含该flavor的文件（见 findByFlavor）
+func FindIPAFile(ipaDir, flavor string) (string, error) {
+	return findByFlavor(ipaDir, ".ipa", flavor, "Runner.ipa", false)
+}
+
+// FindIOSApp 在iphoneos目录中查找.app包，指定flavor时选择名称包含该flavor的包，其次Runner.app
+func FindIOSApp(appDir, flavor string) (string, error) {
+	return findByFlavor(appDir, ".app", flavor, "Runner.app", true)
+}
+
+// findByFlavor 按扩展名查找产物：名称中含有flavor的全部单词（见 containsWords） > 默认名称 > 按字母序第一个。
+// 指定flavor时不使用第一个文件兜底（可能是其他flavor遗留的产物），找不到时返回错误
+func findByFlavor(dir, ext, flavor, defaultName string, wantDir bool) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() != wantDir || !strings.HasSuffix(strings.ToLower(entry.Name()), ext) {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	if len(names) == 0 {
+		return "", os.ErrNotExist
+	}
+	sort.Strings(names)
+
+	if flavor != "" {
+		for _, name := range names {
+			if containsWords(strings.TrimSuffix(name, filepath.Ext(name)), flavor) {
+				return filepath.Join(dir, name), nil
+			}
+		}
+	}
+	for _, name := range names {
+		if name == defaultName {
+			return filepath.Join(dir, name), nil
+		}
+	}
+	if flavor != "" {
+		return "", fmt.Errorf("在目录 %s 中未找到 flavor %s 的%s产物（现有: %s）", dir, flavor, ext, strings.Join(names, ", "))
+	}
+	return filepath.Join(dir, names[0]), nil
+}
+
+// containsWords 判断名称中是否连续出现flavor的全部单词（忽略大小写），
+// 如 "MyApp Dev"、"MyAppDev"、"myapp-dev" 含有 dev，"MyApp Developer" 不含
+func containsWords(name, flavor string) bool {
+	nameWords, flavorWords := splitWords(name), splitWords(flavor)
+	if len(flavorWords) == 0 {
+		return false
+	}
+	for i := 0; i+len(flavorWords) <= len(nameWords); i++ {
+		match := true
+		for j, word := range flavorWords {
+			if !strings.EqualFold(nameWords[i+j], word) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// splitWords 按非字母数字字符和小写到大写的边界拆分单词（"MyApp prodCN" -> My App prod CN）
+func splitWords(s string) []string {
+	var words []string
+	var word []rune
+	var prev rune
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word, prev = nil, 0
+			continue
+		}
+		if len(word) > 0 && unicode.IsUpper(r) && unicode.IsLower(prev) {
+			words = append(words, string(word))
+			word = nil
+		}
+		word = append(word, r)
+		prev = r
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
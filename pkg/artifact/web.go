@@ -0,0 +1,188 @@
+package artifact
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// webAssetManifests Flutter不同版本生成的资源清单文件
+var webAssetManifests = []string{
+	filepath.Join("assets", "AssetManifest.json"),
+	filepath.Join("assets", "AssetManifest.bin.json"),
+	filepath.Join("assets", "AssetManifest.bin"),
+}
+
+// ValidateWeb 验证Flutter Web构建目录
+func (v *ArtifactValidatorImpl) ValidateWeb(webDir string, config *ArtifactConfig) (*ValidationResult, error) {
+	var details []ValidationDetail
+	var success = true
+
+	// 1. 检查build/web目录是否存在
+	exists, fileInfo, err := v.checkFileExists(webDir)
+	if err != nil {
+		details = append(details, ValidationDetail{
+			Check:    "Web构建目录存在性检查",
+			Status:   "failed",
+			Message:  fmt.Sprintf("检查目录状态失败: %v", err),
+			Critical: true,
+		})
+		return v.createValidationResult(false, webDir, 0, details, err), err
+	}
+
+	if !exists || !fileInfo.IsDir() {
+		details = append(details, ValidationDetail{
+			Check:    "Web构建目录存在性检查",
+			Status:   "failed",
+			Message:  fmt.Sprintf("Web构建目录不存在: %s", webDir),
+			Critical: true,
+		})
+		return v.createValidationResult(false, webDir, 0, details, fmt.Errorf("Web构建目录不存在")), fmt.Errorf("Web构建目录不存在")
+	}
+
+	details = append(details, ValidationDetail{
+		Check:    "Web构建目录存在性检查",
+		Status:   "success",
+		Message:  "Web构建目录存在",
+		Critical: true,
+	})
+
+	// 2. 检查必要文件
+	requiredFiles := []string{"index.html", "main.dart.js", "flutter_service_worker.js"}
+	for _, requiredFile := range requiredFiles {
+		filePath := filepath.Join(webDir, requiredFile)
+		if fileExists, _, _ := v.checkFileExists(filePath); fileExists {
+			details = append(details, ValidationDetail{
+				Check:    fmt.Sprintf("必要文件检查 (%s)", requiredFile),
+				Status:   "success",
+				Message:  fmt.Sprintf("%s 文件存在", requiredFile),
+				Critical: true,
+			})
+		} else {
+			details = append(details, ValidationDetail{
+				Check:    fmt.Sprintf("必要文件检查 (%s)", requiredFile),
+				Status:   "failed",
+				Message:  fmt.Sprintf("%s 文件不存在", requiredFile),
+				Critical: true,
+			})
+			success = false
+		}
+	}
+
+	// 3. 检查资源清单
+	var manifestFound string
+	for _, manifest := range webAssetManifests {
+		if manifestExists, _, _ := v.checkFileExists(filepath.Join(webDir, manifest)); manifestExists {
+			manifestFound = manifest
+			break
+		}
+	}
+	if manifestFound != "" {
+		details = append(details, ValidationDetail{
+			Check:    "资源清单检查",
+			Status:   "success",
+			Message:  fmt.Sprintf("%s 存在", filepath.ToSlash(manifestFound)),
+			Critical: true,
+		})
+	} else {
+		details = append(details, ValidationDetail{
+			Check:    "资源清单检查",
+			Status:   "failed",
+			Message:  "未找到资源清单 (assets/AssetManifest.json)",
+			Critical: true,
+		})
+		success = false
+	}
+
+	// 4. 检查总体积预算
+	dirSize, err := v.calculateDirectorySize(webDir)
+	if err != nil {
+		details = append(details, ValidationDetail{
+			Check:    "Web构建体积检查",
+			Status:   "warning",
+			Message:  fmt.Sprintf("无法计算目录大小: %v", err),
+			Critical: false,
+		})
+	} else {
+		sizeOK, sizeMsg := v.checkFileSize(dirSize, config.MinFileSize, config.MaxFileSize)
+		if !sizeOK {
+			details = append(details, ValidationDetail{
+				Check:    "Web构建体积检查",
+				Status:   "failed",
+				Message:  sizeMsg,
+				Critical: true,
+			})
+			success = false
+		} else {
+			details = append(details, ValidationDetail{
+				Check:    "Web构建体积检查",
+				Status:   "success",
+				Message:  sizeMsg,
+				Critical: false,
+			})
+		}
+	}
+
+	// 5. Web完整性检查（如果启用）
+	if config.ValidateIntegrity || (config.ValidationConfig != nil && config.ValidationConfig.EnableIntegrityCheck) {
+		integrityOK, integrityMsg := v.validateWebIntegrity(webDir)
+		if !integrityOK {
+			details = append(details, ValidationDetail{
+				Check:    "Web完整性检查",
+				Status:   "failed",
+				Message:  integrityMsg,
+				Critical: true,
+			})
+			success = false
+		} else {
+			details = append(details, ValidationDetail{
+				Check:    "Web完整性检查",
+				Status:   "success",
+				Message:  integrityMsg,
+				Critical: false,
+			})
+		}
+	}
+
+	var resultErr error
+	if !success {
+		resultErr = fmt.Errorf("Web构建验证失败")
+	}
+
+	return v.createValidationResult(success, webDir, dirSize, details, resultErr), resultErr
+}
+
+// validateWebIntegrity 验证Web构建产物完整性
+func (v *ArtifactValidatorImpl) validateWebIntegrity(webDir string) (bool, string) {
+	indexContent, err := os.ReadFile(filepath.Join(webDir, "index.html"))
+	if err != nil {
+		return false, fmt.Sprintf("无法读取index.html: %v", err)
+	}
+
+	// index.html 需要引导Flutter应用加载
+	index := string(indexContent)
+	if !strings.Contains(index, "flutter") && !strings.Contains(index, "main.dart.js") {
+		return false, "index.html 未引用Flutter引导脚本"
+	}
+
+	mainJSInfo, err := os.Stat(filepath.Join(webDir, "main.dart.js"))
+	if err != nil {
+		return false, fmt.Sprintf("无法读取main.dart.js: %v", err)
+	}
+	if mainJSInfo.Size() == 0 {
+		return false, "main.dart.js 文件为空"
+	}
+
+	details := []string{
+		"index.html 引导脚本正常",
+		fmt.Sprintf("main.dart.js %.2f MB", float64(mainJSInfo.Size())/(1024*1024)),
+	}
+
+	// base href 仅作提示
+	if strings.Contains(index, "<base href=") {
+		details = append(details, "base href 已设置")
+	}
+
+	return true, fmt.Sprintf("Web完整性正常 (%s)", strings.Join(details, ", "))
+}
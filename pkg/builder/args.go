@@ -0,0 +1,115 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// argKey 返回参数的比较键：
+// --name=value 取 --name，--no-name 取 --name，--dart-define=KEY=VALUE 取 --dart-define=KEY
+func argKey(arg string) string {
+	if strings.HasPrefix(arg, "--dart-define=") {
+		define := strings.TrimPrefix(arg, "--dart-define=")
+		return "--dart-define=" + strings.SplitN(define, "=", 2)[0]
+	}
+
+	name := strings.SplitN(arg, "=", 2)[0]
+	if strings.HasPrefix(name, "--no-") {
+		name = "--" + strings.TrimPrefix(name, "--no-")
+	}
+	return name
+}
+
+// customBuildArgs 返回用户提供的所有构建参数（flutter_build_args 与 dart_defines）
+func (b *FlutterBuilderImpl) customBuildArgs() []string {
+	args := append([]string(nil), b.GetCustomArgStringSlice("flutter_build_args")...)
+	for _, define := range b.GetCustomArgStringSlice("dart_defines") {
+		args = append(args, "--dart-define="+define)
+	}
+	return args
+}
+
+// validateCustomArgs 检查自定义参数与专用参数（build_mode、flavor、target、target_platform）之间的冲突
+func (b *FlutterBuilderImpl) validateCustomArgs() error {
+	buildArgs := b.GetCustomArgStringSlice("flutter_build_args")
+	for i, arg := range buildArgs {
+		key := argKey(arg)
+		switch key {
+		case "--debug", "--profile", "--release":
+			return fmt.Errorf("flutter_build_args 中不能包含 %s，请使用 build_mode（--mode）指定构建模式", arg)
+		case "--flavor":
+			if flavor := b.GetCustomArgString("flavor"); flavor != "" && argValue(buildArgs, i) != flavor {
+				return fmt.Errorf("flutter_build_args 中的 --flavor 与 flavor 参数冲突: %s / %s", argValue(buildArgs, i), flavor)
+			}
+		case "--target", "-t":
+			if b.GetCustomArgString("target") != "" {
+				return fmt.Errorf("flutter_build_args 中的 %s 与 target 参数冲突，请只使用其中一个", key)
+			}
+		case "--target-platform":
+			if b.GetCustomArgString("target_platform") != "" {
+				return fmt.Errorf("flutter_build_args 中的 --target-platform 与 target_platform 参数冲突，请只使用其中一个")
+			}
+		}
+	}
+
+	defines := make(map[string]string)
+	for _, arg := range b.customBuildArgs() {
+		if !strings.HasPrefix(arg, "--dart-define=") {
+			continue
+		}
+		define := strings.TrimPrefix(arg, "--dart-define=")
+		key := strings.SplitN(define, "=", 2)[0]
+		if key == "" || !strings.Contains(define, "=") {
+			return fmt.Errorf("无效的 dart-define: %s（格式应为 KEY=VALUE）", define)
+		}
+		if previous, exists := defines[key]; exists && previous != define {
+			return fmt.Errorf("dart-define %s 重复且取值不同: %s / %s", key, previous, define)
+		}
+		defines[key] = define
+	}
+
+	return nil
+}
+
+// argValue 返回参数值（支持 --name=value 和 --name value 两种形式）
+func argValue(args []string, i int) string {
+	if parts := strings.SplitN(args[i], "=", 2); len(parts) == 2 {
+		return parts[1]
+	}
+	if i+1 < len(args) {
+		return args[i+1]
+	}
+	return ""
+}
+
+// dropOverriddenDefaults 移除被自定义参数覆盖的默认参数（同名参数、--no- 取反参数或同 KEY 的 --dart-define）
+func (b *FlutterBuilderImpl) dropOverriddenDefaults(defaults []string) []string {
+	customKeys := make(map[string]string)
+	for _, arg := range b.customBuildArgs() {
+		customKeys[argKey(arg)] = arg
+	}
+	if len(customKeys) == 0 {
+		return defaults
+	}
+
+	result := make([]string, 0, len(defaults))
+	for i := 0; i < len(defaults); i++ {
+		arg := defaults[i]
+		hasValue := b.isParameterWithValue(arg) && i+1 < len(defaults)
+
+		if custom, overridden := customKeys[argKey(arg)]; overridden {
+			if hasValue {
+				i++
+			}
+			b.logger.Warning("自定义参数 %s 覆盖默认参数 %s", custom, arg)
+			continue
+		}
+
+		result = append(result, arg)
+		if hasValue {
+			result = append(result, defaults[i+1])
+			i++
+		}
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package builder
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+)
+
+func newTestBuilder(t *testing.T, platform string, customArgs map[string]interface{}) *FlutterBuilderImpl {
+	t.Helper()
+	b := NewFlutterBuilderWithLogger(platform, nil, t.TempDir(), logger.New()).(*FlutterBuilderImpl)
+	b.SetCustomArgs(customArgs)
+	return b
+}
+
+func TestSecurityRemindersTargetPlatform(t *testing.T) {
+	tests := map[string][]string{
+		"- 目标平台: android-arm64\n":                                        {"flutter", "build", "apk", "--target-platform", "android-arm64"},
+		"- 目标平台: android-arm, android-arm64, android-x64\n":              {"flutter", "build", "apk", "--target-platform=android-arm,android-arm64,android-x64", "--split-per-abi"},
+		"- 目标平台: Flutter 默认 (android-arm, android-arm64, android-x64)\n": {"flutter", "build", "apk", "--release"},
+	}
+	for want, cmd := range tests {
+		var out bytes.Buffer
+		log := logger.New()
+		log.SetOutput(&out, &out)
+		b := NewFlutterBuilderWithLogger("apk", nil, t.TempDir(), log).(*FlutterBuilderImpl)
+		b.buildCommand = cmd
+		b.showSecurityReminders()
+		if !strings.Contains(out.String(), want) || strings.Contains(out.String(), "仅ARM64") {
+			t.Errorf("命令 %v 的安全提醒应包含 %q:\n%s", cmd, want, out.String())
+		}
+	}
+}
+
+func TestArgKey(t *testing.T) {
+	tests := map[string]string{
+		"--obfuscate":                         "--obfuscate",
+		"--split-debug-info=build/debug-info": "--split-debug-info",
+		"--no-tree-shake-icons":               "--tree-shake-icons",
+		"--dart-define=API_URL=https://x":     "--dart-define=API_URL",
+		"--target-platform":                   "--target-platform",
+	}
+	for arg, want := range tests {
+		if got := argKey(arg); got != want {
+			t.Errorf("argKey(%q) 期望 %q，实际 %q", arg, want, got)
+		}
+	}
+}
+
+func TestApplyBuildArgsOverridesDefaults(t *testing.T) {
+	b := newTestBuilder(t, "apk", map[string]interface{}{
+		"flutter_build_args": []string{"--no-tree-shake-icons", "--split-debug-info=out/symbols", "--target-platform", "android-arm"},
+		"dart_defines":       []string{"FLUTTER_WEB_USE_SKIA=false", "API_URL=https://api.example.com"},
+	})
+
+	defaults := []string{
+		"--obfuscate",
+		"--split-debug-info=build/debug-info",
+		"--tree-shake-icons",
+		"--target-platform", "android-arm64",
+		"--dart-define=FLUTTER_WEB_USE_SKIA=true",
+		"--dart-define=FLUTTER_WEB_AUTO_DETECT=true",
+	}
+	got := b.applyBuildArgs([]string{"flutter", "build", "apk", "--release"}, defaults)
+
+	want := []string{
+		"flutter", "build", "apk", "--release",
+		"--obfuscate",
+		"--dart-define=FLUTTER_WEB_AUTO_DETECT=true",
+		"--no-tree-shake-icons", "--split-debug-info=out/symbols", "--target-platform", "android-arm",
+		"--dart-define=FLUTTER_WEB_USE_SKIA=false", "--dart-define=API_URL=https://api.example.com",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("构建命令错误\n期望: %v\n实际: %v", want, got)
+	}
+}
+
+func TestApplyBuildArgsRemoveAndDisable(t *testing.T) {
+	defaults := []string{"--obfuscate", "--tree-shake-icons", "--target-platform", "android-arm64"}
+
+	b := newTestBuilder(t, "apk", map[string]interface{}{
+		"remove_default_args": []string{"--obfuscate", "--target-platform"},
+	})
+	if got := b.applyBuildArgs(nil, defaults); !reflect.DeepEqual(got, []string{"--tree-shake-icons"}) {
+		t.Errorf("移除默认参数后期望 [--tree-shake-icons]，实际 %v", got)
+	}
+
+	b = newTestBuilder(t, "apk", map[string]interface{}{
+		"disable_default_args": true,
+		"flutter_build_args":   []string{"--no-pub"},
+	})
+	if got := b.applyBuildArgs(nil, defaults); !reflect.DeepEqual(got, []string{"--no-pub"}) {
+		t.Errorf("禁用默认参数后期望 [--no-pub]，实际 %v", got)
+	}
+}
+
+func TestValidateCustomArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{"valid", map[string]interface{}{
+			"flutter_build_args": []string{"--build-number=3", "--flavor", "prod"},
+			"flavor":             "prod",
+			"dart_defines":       []string{"A=1", "A=1"},
+		}, ""},
+		{"mode flag", map[string]interface{}{"flutter_build_args": []string{"--profile"}}, "build_mode"},
+		{"flavor mismatch", map[string]interface{}{
+			"flutter_build_args": []string{"--flavor=dev"},
+			"flavor":             "prod",
+		}, "flavor"},
+		{"target duplicate", map[string]interface{}{
+			"flutter_build_args": []string{"-t", "lib/main_dev.dart"},
+			"target":             "lib/main_prod.dart",
+		}, "target"},
+		{"target platform duplicate", map[string]interface{}{
+			"flutter_build_args": []string{"--target-platform=android-arm"},
+			"target_platform":    "android-x64",
+		}, "target_platform"},
+		{"invalid define", map[string]interface{}{"dart_defines": []string{"=1"}}, "KEY=VALUE"},
+		{"conflicting define", map[string]interface{}{
+			"dart_defines":       []string{"ENV=prod"},
+			"flutter_build_args": []string{"--dart-define=ENV=dev"},
+		}, "ENV"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestBuilder(t, "apk", tt.args).validateCustomArgs()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("期望无错误，实际 %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("期望包含 %q 的错误，实际 %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package builder
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+	"github.com/mimicode/flutterbuilder/pkg/hooks"
+)
+
+// BuildInfoFile 构建信息文件（相对项目根目录）
+const BuildInfoFile = "build/build_info.json"
+
+// BuildInfoVersion 构建信息文件的格式版本，字段发生不兼容变化时递增
+const BuildInfoVersion = 1
+
+// redactedValue 脱敏后的 dart-define 取值
+const redactedValue = "***"
+
+// secretDefinePattern 取值需要脱敏的 dart-define 键
+var secretDefinePattern = regexp.MustCompile(`(?i)(secret|token|password|passwd|pwd|key|credential|private|auth|signature|cert)`)
+
+// BuildInfo 构建信息：记录产物由什么命令、什么环境、什么源码构建而来，写入 build/build_info.json
+type BuildInfo struct {
+	Version        int                 `json:"version"`
+	BuildID        string              `json:"build_id"`
+	Platform       string              `json:"platform"`
+	BuildMode      string              `json:"build_mode"`
+	Flavor         string              `json:"flavor"`
+	CreatedAt      time.Time           `json:"created_at"`
+	Command        []string            `json:"command"`          // 实际执行的 flutter build 命令（敏感的 dart-define 已脱敏）
+	DartDefines    map[string]string   `json:"dart_defines"`     // 生效的 dart-define（同 KEY 以最后一个为准，敏感值已脱敏）
+	Obfuscated     bool                `json:"obfuscated"`       // 命令中是否包含 --obfuscate
+	SplitDebugInfo string              `json:"split_debug_info"` // --split-debug-info 目录，未分离时为空
+	Flutter        json.RawMessage     `json:"flutter"`          // flutter --version --machine 的输出
+	Git            *GitInfo            `json:"git"`              // 项目不在 git 仓库中时为 null
+	Stages         []BuildInfoStage    `json:"stages"`           // 截至后处理阶段的各阶段耗时
+	Hooks          []BuildInfoHook     `json:"hooks"`
+	Artifacts      []BuildInfoArtifact `json:"artifacts"`
+	System         BuildInfoSystem     `json:"system"`
+}
+
+// GitInfo 构建时项目所在 git 仓库的状态
+type GitInfo struct {
+	Commit string `json:"commit"`
+	Branch string `json:"branch"` // 分离 HEAD 时为 HEAD
+	Dirty  bool   `json:"dirty"`  // 是否有未提交的修改（包括未跟踪的文件）
+}
+
+// BuildInfoStage 阶段执行记录
+type BuildInfoStage struct {
+	Stage      string `json:"stage"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// BuildInfoHook 钩子执行结果
+type BuildInfoHook struct {
+	HookType   string `json:"hook_type"`
+	ScriptPath string `json:"script_path"`
+	Success    bool   `json:"success"`
+	ExitCode   int    `json:"exit_code"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error"`
+}
+
+// BuildInfoArtifact 产物文件
+type BuildInfoArtifact struct {
+	ABI       string                    `json:"abi"`
+	Path      string                    `json:"path"` // 相对项目根目录
+	Size      int64                     `json:"size"`
+	SHA256    string                    `json:"sha256"` // 目录为树哈希
+	SHA512    string                    `json:"sha512,omitempty"`
+	Dir       bool                      `json:"dir"`
+	DebugInfo bool                      `json:"debug_info"`
+	Manifest  *artifact.AndroidManifest `json:"manifest,omitempty"` // APK清单信息
+}
+
+// BuildInfoSystem 构建机环境
+type BuildInfoSystem struct {
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	GoVersion string `json:"go_version"`
+}
+
+// recordHookResult 记录钩子执行结果供构建信息使用
+func (b *FlutterBuilderImpl) recordHookResult(hookType hooks.HookType, hook *hooks.HookConfig, result *hooks.HookResult) {
+	errMsg := ""
+	if result.Error != nil {
+		errMsg = result.Error.Error()
+	}
+	b.hookResults = append(b.hookResults, BuildInfoHook{
+		HookType:   string(hookType),
+		ScriptPath: hook.ScriptPath,
+		Success:    result.Success,
+		ExitCode:   result.ExitCode,
+		DurationMs: result.Duration.Milliseconds(),
+		Error:      errMsg,
+	})
+}
+
+// createBuildInfo 生成构建信息并写入 build/build_info.json
+func (b *FlutterBuilderImpl) createBuildInfo() error {
+	buildInfoPath := filepath.Join(b.projectRoot, filepath.FromSlash(BuildInfoFile))
+	if err := os.MkdirAll(filepath.Dir(buildInfoPath), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(b.buildInfo(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(buildInfoPath, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	b.logger.Info("构建信息: %s", buildInfoPath)
+	return nil
+}
+
+// buildInfo 收集本次构建的构建信息
+func (b *FlutterBuilderImpl) buildInfo() *BuildInfo {
+	info := &BuildInfo{
+		Version:     BuildInfoVersion,
+		BuildID:     b.logger.BuildID(),
+		Platform:    string(b.platform),
+		BuildMode:   string(b.getBuildMode()),
+		Flavor:      b.getFlavor(),
+		CreatedAt:   time.Now(),
+		Command:     redactCommand(b.buildCommand),
+		DartDefines: effectiveDartDefines(b.buildCommand),
+		Git:         b.gitInfo(),
+		Stages:      []BuildInfoStage{},
+		Hooks:       append([]BuildInfoHook{}, b.hookResults...),
+		Artifacts:   []BuildInfoArtifact{},
+		System: BuildInfoSystem{
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+			GoVersion: runtime.Version(),
+		},
+	}
+	if info.Command == nil {
+		info.Command = []string{}
+	}
+	for _, arg := range b.buildCommand {
+		if arg == "--obfuscate" {
+			info.Obfuscated = true
+		}
+	}
+	info.SplitDebugInfo = splitDebugInfoDir(b.buildCommand)
+
+	// 非 JSON 输出（如旧版本 flutter）按字符串记录
+	flutterVersion, err := b.executor.RunCommandWithOutputContext(b.buildContext(), []string{"flutter", "--version", "--machine"}, b.projectRoot)
+	if err != nil {
+		b.logger.Warning("无法获取Flutter版本信息: %v", err)
+	} else if json.Valid([]byte(flutterVersion)) {
+		info.Flutter = json.RawMessage(flutterVersion)
+	} else if flutterVersion != "" {
+		info.Flutter, _ = json.Marshal(flutterVersion)
+	}
+
+	for _, result := range b.stageResults {
+		info.Stages = append(info.Stages, BuildInfoStage{
+			Stage:      string(result.Stage),
+			Status:     string(result.Status),
+			DurationMs: result.Duration.Milliseconds(),
+		})
+	}
+
+	for _, file := range b.buildArtifacts() {
+		path := file.Path
+		if rel, err := filepath.Rel(b.projectRoot, file.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = filepath.ToSlash(rel)
+		}
+		info.Artifacts = append(info.Artifacts, BuildInfoArtifact{
+			ABI:       file.ABI,
+			Path:      path,
+			Size:      file.Size,
+			SHA256:    file.Checksum,
+			SHA512:    file.SHA512,
+			Dir:       file.Dir,
+			DebugInfo: file.DebugInfo,
+			Manifest:  file.Manifest,
+		})
+	}
+	return info
+}
+
+// buildArtifacts 返回验证时记录的产物；产物验证被禁用时直接定位预期产物并计算校验和（每次构建只计算一次）
+func (b *FlutterBuilderImpl) buildArtifacts() []artifact.ArtifactFile {
+	if b.validationResult != nil && len(b.validationResult.Artifacts) > 0 {
+		return b.validationResult.Artifacts
+	}
+	if b.validationConfig == nil || b.validationConfig.EnableValidation {
+		return nil
+	}
+	if b.resolvedArtifacts != nil {
+		return b.resolvedArtifacts
+	}
+
+	files, err := artifact.ResolveArtifacts(b.artifactConfig())
+	if err != nil {
+		b.logger.Warning("无法计算构建产物校验和: %v", err)
+	}
+	b.resolvedArtifacts = append([]artifact.ArtifactFile{}, files...)
+	return b.resolvedArtifacts
+}
+
+// gitInfo 返回项目所在 git 仓库的提交、分支和是否有未提交修改，不在仓库中或 git 不可用时返回nil
+func (b *FlutterBuilderImpl) gitInfo() *GitInfo {
+	ctx := b.buildContext()
+	commit, err := b.executor.RunCommandWithOutputContext(ctx, []string{"git", "rev-parse", "HEAD"}, b.projectRoot)
+	if err != nil {
+		b.logger.Debug("无法获取git提交信息: %v", err)
+		return nil
+	}
+
+	info := &GitInfo{Commit: commit}
+	if branch, err := b.executor.RunCommandWithOutputContext(ctx, []string{"git", "rev-parse", "--abbrev-ref", "HEAD"}, b.projectRoot); err == nil {
+		info.Branch = branch
+	}
+	if status, err := b.executor.RunCommandWithOutputContext(ctx, []string{"git", "status", "--porcelain"}, b.projectRoot); err == nil {
+		info.Dirty = status != ""
+	}
+	return info
+}
+
+// splitDebugInfoDir 返回命令中 --split-debug-info 指定的目录，未分离调试信息时为空
+func splitDebugInfoDir(cmd []string) string {
+	return commandFlagValue(cmd, "--split-debug-info")
+}
+
+// commandFlagValue 返回命令中参数 flag 的取值（--flag=value 或 --flag value，多次出现时以最后一个为准），未设置时为空
+func commandFlagValue(cmd []string, flag string) string {
+	value := ""
+	for i, arg := range cmd {
+		if v, ok := strings.CutPrefix(arg, flag+"="); ok {
+			value = v
+		} else if arg == flag && i+1 < len(cmd) {
+			value = cmd[i+1]
+		}
+	}
+	return value
+}
+
+// dartDefineValues 返回命令中的 dart-define 参数（KEY=VALUE）及其在命令中的位置
+func dartDefineValues(cmd []string) ([]string, []int) {
+	var defines []string
+	var indexes []int
+	for i, arg := range cmd {
+		if strings.HasPrefix(arg, "--dart-define=") {
+			defines = append(defines, strings.TrimPrefix(arg, "--dart-define="))
+			indexes = append(indexes, i)
+		} else if arg == "--dart-define" && i+1 < len(cmd) {
+			defines = append(defines, cmd[i+1])
+			indexes = append(indexes, i+1)
+		}
+	}
+	return defines, indexes
+}
+
+// redactDefine 对敏感键的 dart-define 取值脱敏
+func redactDefine(define string) (string, string) {
+	key, value, _ := strings.Cut(define, "=")
+	if secretDefinePattern.MatchString(key) && value != "" {
+		value = redactedValue
+	}
+	return key, value
+}
+
+// redactCommand 返回敏感的 dart-define 取值已脱敏的命令副本
+func redactCommand(cmd []string) []string {
+	if cmd == nil {
+		return nil
+	}
+	redacted := append([]string{}, cmd...)
+	defines, indexes := dartDefineValues(cmd)
+	for i, define := range defines {
+		key, value := redactDefine(define)
+		if value != redactedValue {
+			continue
+		}
+		if strings.HasPrefix(redacted[indexes[i]], "--dart-define=") {
+			redacted[indexes[i]] = "--dart-define=" + key + "=" + value
+		} else {
+			redacted[indexes[i]] = key + "=" + value
+		}
+	}
+	return redacted
+}
+
+// effectiveDartDefines 返回命令中生效的 dart-define（同 KEY 以最后一个为准，与 flutter 一致），敏感值已脱敏
+func effectiveDartDefines(cmd []string) map[string]string {
+	result := make(map[string]string)
+	defines, _ := dartDefineValues(cmd)
+	for _, define := range defines {
+		key, value := redactDefine(define)
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,195 @@
+//go:build !windows
+
+package builder
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+	"github.com/mimicode/flutterbuilder/pkg/hooks"
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+)
+
+// apkFlutterScript 模拟flutter命令：--version --machine 输出 JSON，build 复制 $TEST_APK 作为APK
+const apkFlutterScript = `#!/bin/sh
+if [ "$1" = "--version" ]; then
+	echo '{"frameworkVersion":"3.22.0","channel":"stable"}'
+fi
+if [ "$1" = "build" ]; then
+	mkdir -p build/app/outputs/flutter-apk
+	cp "$TEST_APK" build/app/outputs/flutter-apk/app-release.apk
+fi
+exit 0
+`
+
+// writeTestAPK 生成能通过完整性检查的最小APK
+func writeTestAPK(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.apk")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	names := []string{"AndroidManifest.xml", "classes.dex", "resources.arsc"}
+	for i := 0; i < 8; i++ {
+		names = append(names, fmt.Sprintf("res/raw/file%d", i))
+	}
+	for _, name := range names {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(name))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBuildInfo(t *testing.T) {
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "flutter"), []byte(apkFlutterScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "dart"), []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("TEST_APK", writeTestAPK(t))
+
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "hook.dart"), []byte("void main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// 有提交记录的仓库，构建产生的文件使工作区变为有未提交修改
+	gitAvailable := exec.Command("git", "init", "-q", project).Run() == nil &&
+		exec.Command("git", "-C", project, "-c", "user.name=test", "-c", "user.email=test@example.com",
+			"commit", "-q", "--allow-empty", "-m", "init").Run() == nil
+
+	log := logger.New()
+	log.SetOutput(io.Discard, io.Discard)
+	b := NewFlutterBuilderWithLogger("apk", nil, project, log).(*FlutterBuilderImpl)
+	b.SetValidationConfig(&artifact.ArtifactValidationConfig{EnableValidation: true, CustomMinSize: 1})
+	b.SetCustomArgs(map[string]interface{}{
+		"disable_default_args": true,
+		"dart_defines":         []string{"API_URL=https://api.example.com", "API_KEY=s3cr3t"},
+	})
+	if err := b.RegisterHook(hooks.HookPreBuild, &hooks.HookConfig{ScriptPath: "hook.dart"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.RunContext(context.Background()); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(project, BuildInfoFile))
+	if err != nil {
+		t.Fatalf("应生成构建信息文件: %v", err)
+	}
+	if strings.Contains(string(data), "s3cr3t") {
+		t.Error("构建信息中不应包含敏感的 dart-define 取值")
+	}
+	var info BuildInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatalf("构建信息不是合法的 JSON: %v", err)
+	}
+
+	if info.Obfuscated || info.SplitDebugInfo != "" {
+		t.Errorf("禁用默认参数时不应记录为已混淆: %+v", info)
+	}
+	if !reflect.DeepEqual(info.Command[:3], []string{"flutter", "build", "apk"}) {
+		t.Errorf("构建命令错误: %v", info.Command)
+	}
+	if info.DartDefines["API_URL"] != "https://api.example.com" || info.DartDefines["API_KEY"] != redactedValue {
+		t.Errorf("dart-define 错误: %v", info.DartDefines)
+	}
+	var flutter struct {
+		FrameworkVersion string `json:"frameworkVersion"`
+	}
+	if err := json.Unmarshal(info.Flutter, &flutter); err != nil || flutter.FrameworkVersion != "3.22.0" {
+		t.Errorf("Flutter版本信息错误: %s", info.Flutter)
+	}
+	if len(info.Hooks) != 1 || info.Hooks[0].ScriptPath != "hook.dart" || !info.Hooks[0].Success {
+		t.Errorf("钩子记录错误: %+v", info.Hooks)
+	}
+	if len(info.Stages) != 5 || info.Stages[4].Stage != string(StageBuild) {
+		t.Errorf("阶段记录错误: %+v", info.Stages)
+	}
+	if len(info.Artifacts) != 1 || info.Artifacts[0].Path != "build/app/outputs/flutter-apk/app-release.apk" || len(info.Artifacts[0].SHA256) != 64 {
+		t.Fatalf("产物记录错误: %+v", info.Artifacts)
+	}
+	sums, err := os.ReadFile(filepath.Join(project, "build", artifact.SHA256SumsFile))
+	if err != nil || !strings.Contains(string(sums), info.Artifacts[0].SHA256+"  app/outputs/flutter-apk/app-release.apk\n") {
+		t.Errorf("应在 build 目录写出校验和清单: %s %v", sums, err)
+	}
+	if gitAvailable && (info.Git == nil || len(info.Git.Commit) != 40 || !info.Git.Dirty) {
+		t.Errorf("git 状态错误: %+v", info.Git)
+	}
+}
+
+func TestBuildInfoWithoutValidation(t *testing.T) {
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "flutter"), []byte(apkFlutterScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("TEST_APK", writeTestAPK(t))
+
+	project := t.TempDir()
+	log := logger.New()
+	log.SetOutput(io.Discard, io.Discard)
+	b := NewFlutterBuilderWithLogger("apk", nil, project, log).(*FlutterBuilderImpl)
+	b.SetValidationConfig(&artifact.ArtifactValidationConfig{EnableValidation: false})
+	b.SetCustomArgs(map[string]interface{}{"disable_default_args": true})
+	if err := b.RunContext(context.Background()); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(project, BuildInfoFile))
+	if err != nil {
+		t.Fatalf("应生成构建信息文件: %v", err)
+	}
+	var info BuildInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatalf("构建信息不是合法的 JSON: %v", err)
+	}
+	sum, err := artifact.FileSHA256(os.Getenv("TEST_APK"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Artifacts) != 1 || info.Artifacts[0].Path != "build/app/outputs/flutter-apk/app-release.apk" || info.Artifacts[0].SHA256 != sum {
+		t.Errorf("禁用产物验证时也应记录产物及其校验和: %+v", info.Artifacts)
+	}
+}
+
+func TestRedactDartDefines(t *testing.T) {
+	cmd := []string{"flutter", "build", "apk", "--dart-define=SENTRY_AUTH_TOKEN=abc", "--dart-define", "DB_PASSWORD=pw",
+		"--dart-define=CHANNEL=play", "--dart-define=CHANNEL=web"}
+
+	want := []string{"flutter", "build", "apk", "--dart-define=SENTRY_AUTH_TOKEN=***", "--dart-define", "DB_PASSWORD=***",
+		"--dart-define=CHANNEL=play", "--dart-define=CHANNEL=web"}
+	if got := redactCommand(cmd); !reflect.DeepEqual(got, want) {
+		t.Errorf("脱敏后的命令错误: %v", got)
+	}
+	if cmd[3] != "--dart-define=SENTRY_AUTH_TOKEN=abc" {
+		t.Error("脱敏不应修改原命令")
+	}
+
+	defines := effectiveDartDefines(cmd)
+	if !reflect.DeepEqual(defines, map[string]string{"SENTRY_AUTH_TOKEN": "***", "DB_PASSWORD": "***", "CHANNEL": "web"}) {
+		t.Errorf("生效的 dart-define 错误: %v", defines)
+	}
+}
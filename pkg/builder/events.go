@@ -0,0 +1,57 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/events"
+	"github.com/mimicode/flutterbuilder/pkg/hooks"
+)
+
+// SetEventHandler 设置构建事件处理函数（nil 表示不发送事件）
+// 阶段、钩子、外部命令和产物验证的进度以 events 包中的类型化事件同步回调
+func (b *FlutterBuilderImpl) SetEventHandler(handler events.Handler) {
+	b.events = events.NewEmitter(b.logger.BuildID(), handler)
+}
+
+// buildObserver 接收命令执行器和钩子执行器的通知，写入当前阶段的输出日志并转换为构建事件。
+// 写入日志和事件的命令中敏感的 dart-define 取值已脱敏
+type buildObserver struct {
+	b *FlutterBuilderImpl
+}
+
+func (o *buildObserver) CommandStarted(cmd []string, cwd string) {
+	cmd = redactCommand(cmd)
+	o.b.currentStageLog().writeLine("$ " + strings.Join(cmd, " "))
+	o.b.events.Emit(&events.CommandStarted{Command: cmd, Dir: cwd})
+}
+
+func (o *buildObserver) CommandOutputLine(cmd []string, stream, line string) {
+	o.b.currentStageLog().writeLine(line)
+	o.b.events.Emit(&events.CommandOutputLine{Command: redactCommand(cmd), Stream: stream, Line: line})
+}
+
+func (o *buildObserver) CommandExited(cmd []string, exitCode int, duration time.Duration, err error) {
+	o.b.currentStageLog().writeLine(fmt.Sprintf("(退出码: %d，耗时: %.2fs)", exitCode, duration.Seconds()))
+	o.b.events.Emit(&events.CommandExited{Command: redactCommand(cmd), ExitCode: exitCode, Duration: duration, Error: err})
+}
+
+func (o *buildObserver) HookStarted(hookType hooks.HookType, hook *hooks.HookConfig, index, total int) {
+	o.b.events.Emit(&events.HookStarted{HookType: hookType, ScriptPath: hook.ScriptPath, Index: index, Total: total})
+}
+
+func (o *buildObserver) HookFinished(hookType hooks.HookType, hook *hooks.HookConfig, result *hooks.HookResult) {
+	// 钩子脚本输出在结束后一次性写入阶段日志
+	log := o.b.currentStageLog()
+	log.writeLine(fmt.Sprintf("$ [%s 钩子] dart run %s", hookType, hook.ScriptPath))
+	if output := strings.TrimRight(result.Output, "\n"); output != "" {
+		for _, line := range strings.Split(output, "\n") {
+			log.writeLine(strings.TrimRight(line, "\r"))
+		}
+	}
+	log.writeLine(fmt.Sprintf("(退出码: %d，耗时: %.2fs)", result.ExitCode, result.Duration.Seconds()))
+	o.b.recordHookResult(hookType, hook, result)
+
+	o.b.events.Emit(&events.HookFinished{HookType: hookType, ScriptPath: hook.ScriptPath, Result: result})
+}
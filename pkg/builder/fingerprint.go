@@ -0,0 +1,159 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+)
+
+// fingerprintFile 依赖指纹记录文件（位于 build/ 下，flutter clean 时一并删除）
+const fingerprintFile = ".flutter_builder_fingerprint.json"
+
+// fingerprintVersion 指纹记录格式版本，计算方式变化时递增使旧记录失效
+const fingerprintVersion = 1
+
+// fingerprintInputs 参与依赖指纹计算的文件（相对项目根目录）
+// 不包含 flutter 每次构建都会重新生成的文件（如 android/local.properties、ios/Flutter/Generated.xcconfig）
+var fingerprintInputs = []string{
+	"pubspec.yaml",
+	"pubspec.lock",
+	"build.yaml",
+	"android/build.gradle",
+	"android/build.gradle.kts",
+	"android/settings.gradle",
+	"android/settings.gradle.kts",
+	"android/gradle.properties",
+	"android/gradle/wrapper/gradle-wrapper.properties",
+	"android/app/build.gradle",
+	"android/app/build.gradle.kts",
+	"ios/Podfile",
+	"ios/Podfile.lock",
+	"ios/Runner.xcodeproj/project.pbxproj",
+	"ios/Flutter/Debug.xcconfig",
+	"ios/Flutter/Release.xcconfig",
+	"linux/CMakeLists.txt",
+}
+
+// dependencyFingerprint 依赖指纹：输入文件的 SHA-256 和 Flutter SDK 版本
+type dependencyFingerprint struct {
+	Version        int               `json:"version"`
+	FlutterVersion string            `json:"flutter_version"`
+	Files          map[string]string `json:"files"` // 文件路径 -> SHA-256，不存在的文件为空
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+// computeFingerprint 计算项目当前的依赖指纹
+func (b *FlutterBuilderImpl) computeFingerprint() (*dependencyFingerprint, error) {
+	flutterVersion, err := b.executor.RunCommandWithOutputContext(b.buildContext(), []string{"flutter", "--version", "--machine"}, b.projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("获取Flutter版本失败: %w", err)
+	}
+
+	fingerprint := &dependencyFingerprint{
+		Version:        fingerprintVersion,
+		FlutterVersion: strings.TrimSpace(flutterVersion),
+		Files:          make(map[string]string, len(fingerprintInputs)),
+	}
+	for _, input := range fingerprintInputs {
+		sum, err := artifact.FileSHA256(filepath.Join(b.projectRoot, filepath.FromSlash(input)))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("计算 %s 指纹失败: %w", input, err)
+		}
+		fingerprint.Files[input] = sum
+	}
+	return fingerprint, nil
+}
+
+// changes 返回与上次记录相比发生变化的输入，完全一致时返回空
+func (f *dependencyFingerprint) changes(previous *dependencyFingerprint) []string {
+	if previous == nil {
+		return []string{"无上次构建记录"}
+	}
+	if previous.Version != f.Version {
+		return []string{"指纹格式版本"}
+	}
+
+	var changed []string
+	if previous.FlutterVersion != f.FlutterVersion {
+		changed = append(changed, "Flutter SDK 版本")
+	}
+	for input, sum := range f.Files {
+		if previous.Files[input] != sum {
+			changed = append(changed, input)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// loadFingerprint 读取上次成功构建记录的依赖指纹，不存在或无法解析时返回nil
+func (b *FlutterBuilderImpl) loadFingerprint() *dependencyFingerprint {
+	data, err := os.ReadFile(filepath.Join(b.projectRoot, "build", fingerprintFile))
+	if err != nil {
+		return nil
+	}
+	var fingerprint dependencyFingerprint
+	if err := json.Unmarshal(data, &fingerprint); err != nil {
+		return nil
+	}
+	return &fingerprint
+}
+
+// saveFingerprint 记录成功构建后的依赖指纹
+func (b *FlutterBuilderImpl) saveFingerprint(fingerprint *dependencyFingerprint) error {
+	fingerprint.CreatedAt = time.Now()
+	data, err := json.MarshalIndent(fingerprint, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(b.projectRoot, "build", fingerprintFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// isDependencyStage 判断是否为增量构建可跳过的依赖相关阶段
+func isDependencyStage(stage Stage) bool {
+	return stage == StageClean || stage == StageGetDeps || stage == StageCodeGen
+}
+
+// dependenciesFresh 判断本次构建后依赖是否与项目文件一致：get_deps、code_gen 均已执行，或因指纹一致而跳过
+func (b *FlutterBuilderImpl) dependenciesFresh(incremental bool) bool {
+	for _, result := range b.stageResults {
+		if result.Stage != StageGetDeps && result.Stage != StageCodeGen {
+			continue
+		}
+		if result.Status != StageStatusSuccess && !(incremental && result.Status == StageStatusSkipped) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkIncremental 判断能否跳过 clean、get_deps、code_gen：依赖指纹与上次成功构建一致且依赖缓存存在
+func (b *FlutterBuilderImpl) checkIncremental() bool {
+	current, err := b.computeFingerprint()
+	if err != nil {
+		b.logger.Warning("无法计算依赖指纹，执行完整构建: %v", err)
+		return false
+	}
+
+	if changed := current.changes(b.loadFingerprint()); len(changed) > 0 {
+		b.logger.Info("依赖指纹已变化（%s），执行完整构建", strings.Join(changed, "、"))
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(b.projectRoot, ".dart_tool", "package_config.json")); err != nil {
+		b.logger.Info("依赖缓存不存在，执行完整构建")
+		return false
+	}
+
+	b.logger.Info("依赖指纹未变化，增量构建跳过 clean、get_deps、code_gen")
+	return true
+}
@@ -0,0 +1,139 @@
+//go:build !windows
+
+package builder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+)
+
+// loggingFlutterScript 模拟flutter命令：记录调用参数，pub get 生成依赖缓存，build 生成APK
+const loggingFlutterScript = `#!/bin/sh
+echo "$*" >> "$FLUTTER_LOG"
+case "$1" in
+pub)
+	mkdir -p .dart_tool
+	echo "{}" > .dart_tool/package_config.json
+	;;
+build)
+	mkdir -p build/app/outputs/flutter-apk
+	echo apk > build/app/outputs/flutter-apk/app-release.apk
+	;;
+clean)
+	rm -rf build .dart_tool
+	;;
+esac
+exit 0
+`
+
+func TestIncrementalBuild(t *testing.T) {
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "flutter"), []byte(loggingFlutterScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "dart"), []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	flutterLog := filepath.Join(t.TempDir(), "flutter.log")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FLUTTER_LOG", flutterLog)
+
+	project := t.TempDir()
+	pubspec := filepath.Join(project, "pubspec.yaml")
+	if err := os.WriteFile(pubspec, []byte("name: app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// build 依次返回本次构建执行的 flutter 命令和各阶段状态
+	build := func() (string, map[Stage]StageStatus) {
+		t.Helper()
+		os.Remove(flutterLog)
+		b := NewFlutterBuilderWithLogger("apk", nil, project, logger.New()).(*FlutterBuilderImpl)
+		b.SetValidationConfig(&artifact.ArtifactValidationConfig{EnableValidation: false})
+		b.SetIncremental(true)
+		if err := b.RunContext(context.Background()); err != nil {
+			t.Fatalf("构建失败: %v", err)
+		}
+		data, _ := os.ReadFile(flutterLog)
+		statuses := make(map[Stage]StageStatus)
+		for _, result := range b.GetStageResults() {
+			statuses[result.Stage] = result.Status
+		}
+		return string(data), statuses
+	}
+
+	// 首次构建：无指纹记录，执行完整流程
+	commands, statuses := build()
+	if !strings.Contains(commands, "clean") || !strings.Contains(commands, "pub get") {
+		t.Errorf("首次构建应执行完整流程: %s", commands)
+	}
+	if _, err := os.Stat(filepath.Join(project, "build", fingerprintFile)); err != nil {
+		t.Fatalf("成功构建后应记录依赖指纹: %v", err)
+	}
+	if statuses[StageClean] != StageStatusSuccess {
+		t.Errorf("首次构建 clean 状态错误: %s", statuses[StageClean])
+	}
+
+	// 依赖未变化：跳过 clean、get_deps、code_gen
+	commands, statuses = build()
+	if strings.Contains(commands, "clean") || strings.Contains(commands, "pub get") {
+		t.Errorf("指纹未变化时不应执行 clean/pub get: %s", commands)
+	}
+	for _, stage := range []Stage{StageClean, StageGetDeps, StageCodeGen} {
+		if statuses[stage] != StageStatusSkipped {
+			t.Errorf("阶段 %s 应被跳过，实际 %s", stage, statuses[stage])
+		}
+	}
+	if statuses[StageBuild] != StageStatusSuccess {
+		t.Errorf("build 阶段应执行: %s", statuses[StageBuild])
+	}
+
+	// pubspec.yaml 变化：重新执行完整流程
+	if err := os.WriteFile(pubspec, []byte("name: app\ndependencies: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commands, _ = build()
+	if !strings.Contains(commands, "clean") || !strings.Contains(commands, "pub get") {
+		t.Errorf("依赖变化后应执行完整流程: %s", commands)
+	}
+
+	// 依赖缓存被删除：重新执行完整流程
+	os.RemoveAll(filepath.Join(project, ".dart_tool"))
+	commands, _ = build()
+	if !strings.Contains(commands, "pub get") {
+		t.Errorf("依赖缓存不存在时应重新获取依赖: %s", commands)
+	}
+}
+
+func TestFingerprintChanges(t *testing.T) {
+	current := &dependencyFingerprint{
+		Version:        fingerprintVersion,
+		FlutterVersion: "3.22.0",
+		Files:          map[string]string{"pubspec.yaml": "a", "pubspec.lock": "b", "ios/Podfile": ""},
+	}
+
+	if changed := current.changes(nil); len(changed) == 0 {
+		t.Error("无上次记录时应视为变化")
+	}
+
+	previous := &dependencyFingerprint{
+		Version:        fingerprintVersion,
+		FlutterVersion: "3.19.0",
+		Files:          map[string]string{"pubspec.yaml": "a", "pubspec.lock": "old", "ios/Podfile": ""},
+	}
+	changed := current.changes(previous)
+	if strings.Join(changed, ",") != "Flutter SDK 版本,pubspec.lock" {
+		t.Errorf("变化项错误: %v", changed)
+	}
+
+	previous.FlutterVersion, previous.Files["pubspec.lock"] = "3.22.0", "b"
+	if changed := current.changes(previous); len(changed) != 0 {
+		t.Errorf("指纹一致时不应有变化项: %v", changed)
+	}
+}
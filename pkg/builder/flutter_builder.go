@@ -1,18 +1,24 @@
 package builder
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mimicode/flutterbuilder/pkg/artifact"
 	"github.com/mimicode/flutterbuilder/pkg/certificates"
+	"github.com/mimicode/flutterbuilder/pkg/events"
 	"github.com/mimicode/flutterbuilder/pkg/executor"
 	"github.com/mimicode/flutterbuilder/pkg/hooks"
 	"github.com/mimicode/flutterbuilder/pkg/logger"
+	"github.com/mimicode/flutterbuilder/pkg/publish"
 	"github.com/mimicode/flutterbuilder/pkg/security"
 	"github.com/mimicode/flutterbuilder/pkg/types"
 )
@@ -29,10 +35,40 @@ type FlutterBuilderImpl struct {
 	hookExecutor      hooks.HookExecutor                 // 钩子执行器
 	artifactValidator artifact.ArtifactValidator         // 产物验证器
 	validationConfig  *artifact.ArtifactValidationConfig // 验证配置
+	validationResult  *artifact.ValidationResult         // 最近一次产物验证结果
+	stageSelection    *StageSelection                    // 阶段选择（跳过、只执行、继续）
+	stageResults      []StageResult                      // 最近一次构建各阶段的执行记录
+	incremental       bool                               // 增量构建：依赖指纹未变化时跳过 clean、get_deps、code_gen
+	events            *events.Emitter                    // 构建事件发送器（未设置处理函数时为nil）
+	stageLog          *stageLog                          // 当前阶段的输出日志（不在阶段中时为nil）
+	stageLogMu        sync.Mutex
+	retryPolicies     map[Stage]*RetryPolicy  // 各阶段命令的重试策略
+	attempts          []CommandAttempt        // 当前阶段设置了重试策略的命令执行记录
+	timeouts          *Timeouts               // 构建和各阶段的超时时间
+	currentStage      Stage                   // 正在执行（或失败时所在）的阶段
+	buildCommand      []string                // 最近一次执行的 flutter build 命令
+	hookResults       []BuildInfoHook         // 本次构建已执行钩子的结果
+	resolvedArtifacts []artifact.ArtifactFile // 产物验证禁用时直接定位的产物（nil 表示尚未定位）
+	output            *OutputConfig           // 产物收集设置（nil 表示不收集）
+	outputFiles       []string                // 最近一次构建收集到输出目录的文件和目录
+	publishers        []publish.Publisher     // 产物发布后端
+	publishedURLs     []string                // 最近一次构建发布的产物地址
+	ctx               context.Context         // 当前构建的上下文（RunContext 设置）
+	logger            *logger.Logger          // 本构建的日志实例
 }
 
 // NewFlutterBuilder 创建新的Flutter构建器
 func NewFlutterBuilder(platform string, iosConfig *IOSConfig, sourcePath string) FlutterBuilder {
+	return NewFlutterBuilderWithLogger(platform, iosConfig, sourcePath, logger.Default())
+}
+
+// NewFlutterBuilderWithLogger 创建使用指定日志实例的Flutter构建器
+// 构建器不修改进程工作目录和全局日志状态，多个实例可以并发运行
+func NewFlutterBuilderWithLogger(platform string, iosConfig *IOSConfig, sourcePath string, log *logger.Logger) FlutterBuilder {
+	if log == nil {
+		log = logger.Default()
+	}
+
 	// 使用提供的源代码路径作为项目根目录
 	projectRoot := sourcePath
 
@@ -40,13 +76,21 @@ func NewFlutterBuilder(platform string, iosConfig *IOSConfig, sourcePath string)
 		platform:          Platform(platform),
 		iosConfig:         iosConfig,
 		projectRoot:       projectRoot,
-		executor:          executor.NewCommandExecutor(),
-		security:          security.NewSecurityChecker(projectRoot),
-		customArgs:        make(map[string]interface{}),          // 初始化自定义参数
-		hookExecutor:      hooks.NewHookExecutor(projectRoot),    // 初始化钩子执行器
-		artifactValidator: artifact.NewArtifactValidator(),       // 初始化产物验证器
-		validationConfig:  artifact.GetDefaultValidationConfig(), // 默认验证配置
+		executor:          executor.NewCommandExecutorWithLogger(log),
+		security:          security.NewSecurityCheckerWithLogger(projectRoot, log),
+		customArgs:        make(map[string]interface{}),                      // 初始化自定义参数
+		hookExecutor:      hooks.NewHookExecutorWithLogger(projectRoot, log), // 初始化钩子执行器
+		artifactValidator: artifact.NewArtifactValidator(),                   // 初始化产物验证器
+		validationConfig:  artifact.GetDefaultValidationConfig(),             // 默认验证配置
+		logger:            log,
+	}
+
+	// 命令和钩子输出写入当前阶段的日志文件，同时转换为构建事件
+	observer := &buildObserver{b: builder}
+	if impl, ok := builder.executor.(*executor.CommandExecutorImpl); ok {
+		impl.SetObserver(observer)
 	}
+	builder.hookExecutor.SetObserver(observer)
 
 	// 如果是iOS平台且有证书配置，创建证书管理器
 	if platform == "ios" && iosConfig != nil {
@@ -58,7 +102,7 @@ func NewFlutterBuilder(platform string, iosConfig *IOSConfig, sourcePath string)
 			TeamID:              iosConfig.TeamID,
 			BundleID:            iosConfig.BundleID,
 		}
-		builder.certManager = certificates.NewCertificateManager(typesIOSConfig, projectRoot)
+		builder.certManager = certificates.NewCertificateManagerWithLogger(typesIOSConfig, projectRoot, log)
 	}
 
 	return builder
@@ -66,53 +110,148 @@ func NewFlutterBuilder(platform string, iosConfig *IOSConfig, sourcePath string)
 
 // Run 执行完整的构建流程
 func (b *FlutterBuilderImpl) Run() error {
-	startTime := time.Now()
-
-	logger.Info("项目根目录: %s", b.projectRoot)
-	logger.Info("构建平台: %s", b.platform)
-	logger.Info("操作系统: %s", runtime.GOOS)
-	logger.Println()
+	return b.RunContext(context.Background())
+}
 
-	// 验证环境和参数
-	if err := b.validateEnvironment(); err != nil {
-		return fmt.Errorf("环境验证失败: %w", err)
+// RunContext 执行完整的构建流程，ctx 取消时终止正在运行的命令和钩子并返回取消错误
+func (b *FlutterBuilderImpl) RunContext(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	parent := ctx
+	if timeout := b.buildTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	b.ctx = ctx
+	b.currentStage = ""
+	defer func() {
+		b.ctx = nil
+	}()
+
+	if err := b.runStages(); err != nil {
+		// 整个构建超时（调用方未取消）
+		if ctx.Err() == context.DeadlineExceeded && parent.Err() == nil {
+			timeoutErr := &TimeoutError{Stage: b.currentStage, Timeout: b.buildTimeout(), Build: true}
+			b.logger.Error("%v，已终止正在运行的命令", timeoutErr)
+			return timeoutErr
+		}
+		// 取消导致的失败统一返回取消错误，便于调用方使用 errors.Is 判断
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			b.logger.Warning("构建已取消: %v", ctxErr)
+			return fmt.Errorf("构建已取消: %w", ctxErr)
+		}
+		return err
 	}
+	return nil
+}
 
-	// 切换到项目根目录
-	if err := os.Chdir(b.projectRoot); err != nil {
-		return fmt.Errorf("切换目录失败: %w", err)
+// buildContext 返回当前构建上下文（直接调用阶段方法时为 Background）
+func (b *FlutterBuilderImpl) buildContext() context.Context {
+	if b.ctx == nil {
+		return context.Background()
 	}
+	return b.ctx
+}
 
-	// 执行构建流程
-	if err := b.Clean(); err != nil {
-		return fmt.Errorf("清理项目失败: %w", err)
+// runStages 依次执行各构建阶段，阶段之间检查取消
+func (b *FlutterBuilderImpl) runStages() error {
+	startTime := time.Now()
+	ctx := b.buildContext()
+
+	b.stageResults = nil
+	b.buildCommand = nil
+	b.hookResults = nil
+	b.resolvedArtifacts = nil
+	b.outputFiles = nil
+	b.publishedURLs = nil
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	if err := b.GetDependencies(); err != nil {
-		return fmt.Errorf("获取依赖失败: %w", err)
+	b.logger.Info("项目根目录: %s", b.projectRoot)
+	b.logger.Info("构建平台: %s", b.platform)
+	b.logger.Info("操作系统: %s", runtime.GOOS)
+	b.logger.Println()
+
+	// 验证环境和参数
+	if err := b.validateEnvironment(); err != nil {
+		return fmt.Errorf("环境验证失败: %w", err)
 	}
 
-	if err := b.RunCodeGeneration(); err != nil {
-		return fmt.Errorf("代码生成失败: %w", err)
+	// 所有命令均通过工作目录参数在项目根目录执行，不切换进程工作目录
+
+	// 增量构建：依赖指纹与上次成功构建一致时跳过依赖相关阶段
+	incremental := b.incremental && b.checkIncremental()
+
+	// 执行构建流程，跳过的阶段连同其前置/后置钩子一起跳过
+	stages := []struct {
+		stage   Stage
+		run     func() error
+		failMsg string
+	}{
+		{StageClean, b.Clean, "清理项目失败"},
+		{StageGetDeps, b.GetDependencies, "获取依赖失败"},
+		{StageCodeGen, b.RunCodeGeneration, "代码生成失败"},
+		{StageSecurityCheck, b.CheckSecurityConfig, "安全配置检查失败"},
+		{StageBuild, b.Build, "构建失败"},
+		{StagePostProcess, b.PostBuildProcessing, "构建后处理失败"},
+	}
+	for i, stage := range stages {
+		index, total := i+1, len(stages)
+		skip, reason := b.stageSelection.skipReason(stage.stage)
+		if !skip && incremental && isDependencyStage(stage.stage) {
+			skip, reason = true, "依赖指纹未变化（增量构建）"
+		}
+		if skip {
+			b.logger.Info("跳过阶段 %s（%s）", stage.stage, reason)
+			b.stageResults = append(b.stageResults, StageResult{Stage: stage.stage, Status: StageStatusSkipped, Reason: reason})
+			b.events.Emit(&events.StageFinished{Stage: string(stage.stage), Index: index, Total: total,
+				Status: string(StageStatusSkipped), Reason: reason})
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		b.events.Emit(&events.StageStarted{Stage: string(stage.stage), Index: index, Total: total})
+		result, err := b.runStage(stage.stage, stage.run)
+		b.stageResults = append(b.stageResults, result)
+		b.events.Emit(&events.StageFinished{Stage: string(stage.stage), Index: index, Total: total,
+			Status: string(result.Status), Duration: result.Duration, Error: err})
+		if err != nil {
+			if result.LogFile != "" {
+				b.logger.Error("阶段 %s 的完整输出: %s", stage.stage, result.LogFile)
+			}
+			return fmt.Errorf("%s: %w", stage.failMsg, err)
+		}
 	}
 
-	if err := b.CheckSecurityConfig(); err != nil {
-		return fmt.Errorf("安全配置检查失败: %w", err)
+	// 将产物、调试信息和构建信息复制到输出目录
+	if err := b.collectOutputs(); err != nil {
+		return fmt.Errorf("收集产物失败: %w", err)
 	}
 
-	if err := b.Build(); err != nil {
-		return fmt.Errorf("构建失败: %w", err)
+	// 发布产物
+	if err := b.publishArtifacts(); err != nil {
+		return fmt.Errorf("发布产物失败: %w", err)
 	}
 
-	if err := b.PostBuildProcessing(); err != nil {
-		return fmt.Errorf("构建后处理失败: %w", err)
+	// 记录依赖指纹（依赖阶段完整执行或因指纹一致而跳过时），供下次增量构建比较
+	if b.incremental && b.dependenciesFresh(incremental) {
+		if fingerprint, err := b.computeFingerprint(); err != nil {
+			b.logger.Warning("无法计算依赖指纹: %v", err)
+		} else if err := b.saveFingerprint(fingerprint); err != nil {
+			b.logger.Warning("保存依赖指纹失败: %v", err)
+		}
 	}
 
 	// 显示完成信息
 	elapsedTime := time.Since(startTime)
-	logger.Println()
-	logger.Header("构建完成")
-	logger.Success("构建成功完成！耗时: %.2f秒", elapsedTime.Seconds())
+	b.logger.Println()
+	b.logger.Header("构建完成")
+	b.logger.Success("构建成功完成！耗时: %.2f秒", elapsedTime.Seconds())
 
 	return nil
 }
@@ -124,19 +263,19 @@ func (b *FlutterBuilderImpl) Clean() error {
 		return fmt.Errorf("清理前置钩子执行失败: %w", err)
 	}
 
-	logger.Info("[1/6] 清理构建缓存...")
+	b.logger.Info("[1/6] 清理构建缓存...")
 
 	// Flutter clean
-	if err := b.executor.RunCommand([]string{"flutter", "clean"}, b.projectRoot); err != nil {
+	if err := b.executor.RunCommandContext(b.buildContext(), []string{"flutter", "clean"}, b.projectRoot); err != nil {
 		return fmt.Errorf("flutter clean 执行失败: %w", err)
 	}
-	logger.Success("Flutter clean 执行成功")
+	b.logger.Success("Flutter clean 执行成功")
 
 	// 删除旧的构建目录
 	buildDir := filepath.Join(b.projectRoot, "build")
 	if err := b.cleanBuildDirectory(buildDir); err != nil {
-		logger.Warning("无法删除构建目录，文件可能被占用: %v", err)
-		logger.Info("继续构建过程...")
+		b.logger.Warning("无法删除构建目录，文件可能被占用: %v", err)
+		b.logger.Info("继续构建过程...")
 	}
 
 	// 执行后置钩子
@@ -154,13 +293,13 @@ func (b *FlutterBuilderImpl) GetDependencies() error {
 		return fmt.Errorf("获取依赖前置钩子执行失败: %w", err)
 	}
 
-	logger.Info("[2/6] 获取项目依赖...")
+	b.logger.Info("[2/6] 获取项目依赖...")
 
-	if err := b.executor.RunCommand([]string{"flutter", "pub", "get"}, b.projectRoot); err != nil {
+	if err := b.runCommand(StageGetDeps, []string{"flutter", "pub", "get"}); err != nil {
 		return fmt.Errorf("依赖获取失败: %w", err)
 	}
 
-	logger.Success("依赖获取成功")
+	b.logger.Success("依赖获取成功")
 
 	// 执行后置钩子
 	if err := b.executeHooks(hooks.HookPostGetDeps, "GetDependencies"); err != nil {
@@ -177,17 +316,17 @@ func (b *FlutterBuilderImpl) RunCodeGeneration() error {
 		return fmt.Errorf("代码生成前置钩子执行失败: %w", err)
 	}
 
-	logger.Info("[3/6] 运行代码生成...")
+	b.logger.Info("[3/6] 运行代码生成...")
 
 	// 尝试运行build_runner，如果失败则忽略
 	// 使用新的 dart run 命令替代已废弃的 flutter packages pub run
-	if err := b.executor.RunCommand([]string{
+	if err := b.runCommand(StageCodeGen, []string{
 		"dart", "run", "build_runner",
 		"build", "--delete-conflicting-outputs",
-	}, b.projectRoot); err != nil {
-		logger.Info("跳过代码生成（build_runner未配置或不需要）")
+	}); err != nil {
+		b.logger.Info("跳过代码生成（build_runner未配置或不需要）")
 	} else {
-		logger.Success("代码生成完成")
+		b.logger.Success("代码生成完成")
 	}
 
 	// 执行后置钩子
@@ -205,10 +344,10 @@ func (b *FlutterBuilderImpl) CheckSecurityConfig() error {
 		return fmt.Errorf("安全检查前置钩子执行失败: %w", err)
 	}
 
-	logger.Info("[4/6] 检查安全配置...")
+	b.logger.Info("[4/6] 检查安全配置...")
 
 	var checkErr error
-	if b.platform == PlatformAPK {
+	if b.platform == PlatformAPK || b.platform == PlatformAAB {
 		checkErr = b.security.CheckAndroidSecurity()
 	} else if b.platform == PlatformIOS {
 		checkErr = b.security.CheckIOSSecurity()
@@ -229,13 +368,19 @@ func (b *FlutterBuilderImpl) Build() error {
 		return fmt.Errorf("构建前置钩子执行失败: %w", err)
 	}
 
-	logger.Info("[5/6] 构建发布版本...")
+	b.logger.Info("[5/6] 构建发布版本...")
 
 	var buildErr error
 	if b.platform == PlatformAPK {
 		buildErr = b.buildAndroidAPK()
+	} else if b.platform == PlatformAAB {
+		buildErr = b.buildAndroidAAB()
 	} else if b.platform == PlatformIOS {
 		buildErr = b.buildIOS()
+	} else if b.platform == PlatformWeb {
+		buildErr = b.buildWeb()
+	} else if b.platform == PlatformLinux {
+		buildErr = b.buildLinux()
 	} else {
 		buildErr = fmt.Errorf("不支持的平台: %s", b.platform)
 	}
@@ -255,11 +400,11 @@ func (b *FlutterBuilderImpl) PostBuildProcessing() error {
 		return fmt.Errorf("后处理前置钩子执行失败: %w", err)
 	}
 
-	logger.Info("[6/6] 构建后处理...")
+	b.logger.Info("[6/6] 构建后处理...")
 
 	// 创建构建信息文件
 	if err := b.createBuildInfo(); err != nil {
-		logger.Warning("创建构建信息文件失败: %v", err)
+		b.logger.Warning("创建构建信息文件失败: %v", err)
 	}
 
 	// 显示安全提醒
@@ -314,7 +459,12 @@ func (b *FlutterBuilderImpl) GetCustomArgBool(key string) bool {
 
 // GetCustomArgStringSlice 获取字符串数组类型的自定义参数
 func (b *FlutterBuilderImpl) GetCustomArgStringSlice(key string) []string {
-	if val, exists := b.GetCustomArg(key); exists {
+	return CustomArgStringSlice(b.customArgs, key)
+}
+
+// CustomArgStringSlice 读取字符串数组类型的自定义参数，兼容 []string 和 []interface{}（如从JSON/YAML解析得到）
+func CustomArgStringSlice(customArgs map[string]interface{}, key string) []string {
+	if val, exists := customArgs[key]; exists {
 		if slice, ok := val.([]string); ok {
 			return slice
 		}
@@ -392,16 +542,65 @@ func (b *FlutterBuilderImpl) GetValidationConfig() *artifact.ArtifactValidationC
 	return b.validationConfig
 }
 
-// validateBuildArtifacts 验证构建产物
-func (b *FlutterBuilderImpl) validateBuildArtifacts() error {
-	logger.Info("[6/6] 验证构建产物...")
+// SetStageSelection 设置阶段选择（nil 表示执行全部阶段）
+func (b *FlutterBuilderImpl) SetStageSelection(selection *StageSelection) error {
+	if err := selection.Validate(); err != nil {
+		return err
+	}
+	b.stageSelection = selection
+	return nil
+}
+
+// SetIncremental 设置增量构建：依赖指纹与上次成功构建一致时跳过 clean、get_deps、code_gen
+func (b *FlutterBuilderImpl) SetIncremental(enabled bool) {
+	b.incremental = enabled
+}
+
+// GetStageResults 获取最近一次构建各阶段的执行记录（包括跳过的阶段）
+func (b *FlutterBuilderImpl) GetStageResults() []StageResult {
+	return append([]StageResult(nil), b.stageResults...)
+}
+
+// GetValidationResult 获取最近一次产物验证结果（验证失败时同样保留）
+func (b *FlutterBuilderImpl) GetValidationResult() *artifact.ValidationResult {
+	return b.validationResult
+}
 
-	// 创建验证配置
+// manifestValidationConfig 返回用于产物验证的配置：设置了APK清单预期值但未指定版本时，
+// 使用 flutter build 命令中的 --build-number/--build-name 作为预期的 versionCode/versionName
+func (b *FlutterBuilderImpl) manifestValidationConfig() *artifact.ArtifactValidationConfig {
+	if b.validationConfig == nil || b.validationConfig.ExpectedManifest == nil {
+		return b.validationConfig
+	}
+
+	expected := *b.validationConfig.ExpectedManifest
+	if expected.VersionCode == 0 {
+		expected.VersionCode, _ = strconv.ParseInt(commandFlagValue(b.buildCommand, "--build-number"), 10, 64)
+	}
+	if expected.VersionName == "" {
+		expected.VersionName = commandFlagValue(b.buildCommand, "--build-name")
+	}
+	config := *b.validationConfig
+	config.ExpectedManifest = &expected
+	return &config
+}
+
+// artifactConfig 按本次构建的平台、flavor 和构建命令创建产物配置
+func (b *FlutterBuilderImpl) artifactConfig() *artifact.ArtifactConfig {
 	config := &artifact.ArtifactConfig{
 		Platform:          convertPlatform(b.platform),
 		SourcePath:        b.projectRoot,
 		ValidateIntegrity: true,
-		ValidationConfig:  b.validationConfig,
+		ValidationConfig:  b.manifestValidationConfig(),
+		SplitPerABI:       b.platform == PlatformAPK && b.GetCustomArgBool("split_per_abi"),
+		BuildMode:         artifact.BuildMode(b.getBuildMode()),
+		DebugInfoDir:      splitDebugInfoDir(b.buildCommand),
+	}
+	if b.supportsFlavor() {
+		config.Flavor = b.getFlavor()
+	}
+	if b.platform == PlatformLinux {
+		config.LinuxArch = b.linuxArch()
 	}
 
 	// 如果是iOS平台，传递iOS配置
@@ -414,9 +613,22 @@ func (b *FlutterBuilderImpl) validateBuildArtifacts() error {
 			BundleID:            b.iosConfig.BundleID,
 		}
 	}
+	return config
+}
+
+// validateBuildArtifacts 验证构建产物
+func (b *FlutterBuilderImpl) validateBuildArtifacts() error {
+	b.logger.Info("[6/6] 验证构建产物...")
 
 	// 执行验证
-	result, err := b.artifactValidator.ValidateArtifact(config)
+	result, err := b.artifactValidator.ValidateArtifact(b.artifactConfig())
+	if result != nil {
+		// 验证器出错时也可能返回已完成的检查项
+		b.validationResult = result
+		for _, detail := range result.ValidationDetails {
+			b.events.Emit(&events.ValidationCheck{Detail: detail})
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("产物验证执行失败: %w", err)
 	}
@@ -426,28 +638,49 @@ func (b *FlutterBuilderImpl) validateBuildArtifacts() error {
 	}
 
 	// 记录验证详情
-	logger.Success("产物验证成功")
+	b.logger.Success("产物验证成功")
 	if result.ArtifactPath != "" {
-		logger.Printf("  文件路径: %s", result.ArtifactPath)
+		b.logger.Printf("  文件路径: %s", result.ArtifactPath)
 	}
 	if result.FileSize > 0 {
-		logger.Printf("  文件大小: %.2f MB", float64(result.FileSize)/(1024*1024))
+		b.logger.Printf("  文件大小: %.2f MB", float64(result.FileSize)/(1024*1024))
+	}
+	for _, file := range result.Artifacts {
+		label := file.ABI
+		if file.DebugInfo {
+			label = "调试信息"
+		}
+		if label != "" {
+			label = "[" + label + "] "
+		}
+		b.logger.Printf("  %s%s (%.2f MB, SHA-256: %s)", label, filepath.Base(file.Path),
+			float64(file.Size)/(1024*1024), file.Checksum)
+	}
+
+	// 写出校验和清单
+	checksumFiles, err := artifact.WriteChecksumFiles(filepath.Join(b.projectRoot, "build"), result.Artifacts)
+	if err != nil {
+		b.logger.Warning("写出校验和清单失败: %v", err)
+	}
+	result.ChecksumFiles = checksumFiles
+	for _, path := range checksumFiles {
+		b.logger.Printf("  校验和清单: %s", path)
 	}
 
 	for _, detail := range result.ValidationDetails {
 		switch detail.Status {
 		case "success":
-			logger.Success("  ✓ %s", detail.Check)
+			b.logger.Success("  ✓ %s", detail.Check)
 		case "failed":
 			if detail.Critical {
-				logger.Error("  ✗ %s: %s", detail.Check, detail.Message)
+				b.logger.Error("  ✗ %s: %s", detail.Check, detail.Message)
 			} else {
-				logger.Warning("  ⚠ %s: %s", detail.Check, detail.Message)
+				b.logger.Warning("  ⚠ %s: %s", detail.Check, detail.Message)
 			}
 		case "warning":
-			logger.Warning("  ⚠ %s: %s", detail.Check, detail.Message)
+			b.logger.Warning("  ⚠ %s: %s", detail.Check, detail.Message)
 		default:
-			logger.Info("  • %s: %s", detail.Check, detail.Message)
+			b.logger.Info("  • %s: %s", detail.Check, detail.Message)
 		}
 	}
 
@@ -456,7 +689,7 @@ func (b *FlutterBuilderImpl) validateBuildArtifacts() error {
 
 // executeHooks 执行指定类型的钩子
 func (b *FlutterBuilderImpl) executeHooks(hookType hooks.HookType, buildStage string) error {
-	context := &hooks.HookContext{
+	hookContext := &hooks.HookContext{
 		HookType:    hookType,
 		Platform:    string(b.platform),
 		ProjectRoot: b.projectRoot,
@@ -465,10 +698,67 @@ func (b *FlutterBuilderImpl) executeHooks(hookType hooks.HookType, buildStage st
 		CustomArgs:  b.customArgs,
 	}
 
-	_, err := b.hookExecutor.ExecuteHooks(hookType, context)
+	_, err := b.hookExecutor.ExecuteHooksContext(b.buildContext(), hookType, hookContext)
 	return err
 }
 
+// getFlavor 获取产品风味（custom args "flavor"，或 flutter_build_args 中的 --flavor）
+func (b *FlutterBuilderImpl) getFlavor() string {
+	if flavor := b.GetCustomArgString("flavor"); flavor != "" {
+		return flavor
+	}
+
+	buildArgs := b.GetCustomArgStringSlice("flutter_build_args")
+	for i, arg := range buildArgs {
+		if arg == "--flavor" && i+1 < len(buildArgs) {
+			return buildArgs[i+1]
+		}
+		if strings.HasPrefix(arg, "--flavor=") {
+			return strings.TrimPrefix(arg, "--flavor=")
+		}
+	}
+	return ""
+}
+
+// getBuildMode 获取构建模式（custom args "build_mode"，默认release）
+func (b *FlutterBuilderImpl) getBuildMode() BuildMode {
+	if mode := b.GetCustomArgString("build_mode"); mode != "" {
+		return BuildMode(mode)
+	}
+	return BuildModeRelease
+}
+
+// buildModeFlag 返回构建模式参数（--debug、--profile、--release）
+func (b *FlutterBuilderImpl) buildModeFlag() string {
+	return "--" + string(b.getBuildMode())
+}
+
+// modeDefaultArgs 按构建模式过滤默认参数
+// 代码混淆和调试信息分离仅用于release，debug模式不支持图标Tree Shaking
+func (b *FlutterBuilderImpl) modeDefaultArgs(defaultArgs []string) []string {
+	mode := b.getBuildMode()
+	if mode == BuildModeRelease {
+		return defaultArgs
+	}
+
+	result := make([]string, 0, len(defaultArgs))
+	for _, arg := range defaultArgs {
+		if arg == "--obfuscate" || strings.HasPrefix(arg, "--split-debug-info") {
+			continue
+		}
+		if mode == BuildModeDebug && arg == "--tree-shake-icons" {
+			continue
+		}
+		result = append(result, arg)
+	}
+	return result
+}
+
+// supportsFlavor 当前平台是否支持 --flavor
+func (b *FlutterBuilderImpl) supportsFlavor() bool {
+	return b.platform == PlatformAPK || b.platform == PlatformAAB || b.platform == PlatformIOS
+}
+
 // removeSpecificArgs 移除指定的参数
 func (b *FlutterBuilderImpl) removeSpecificArgs(args []string, removeList []string) []string {
 	if len(removeList) == 0 {
@@ -517,6 +807,10 @@ func (b *FlutterBuilderImpl) isParameterWithValue(arg string) bool {
 		"--bundle-id",
 		"--build-name",
 		"--build-number",
+		"--web-renderer",
+		"--base-href",
+		"--target",
+		"-t",
 	}
 
 	for _, param := range paramsWithValue {
@@ -536,10 +830,7 @@ func (b *FlutterBuilderImpl) isParameterWithEquals(arg string, removeList []stri
 				return true
 			}
 		}
-		// 如果移除列表中有具体的 dart-define 参数
-		if arg == removeArg {
-			return true
-		}
+		// 具体参数的完全匹配由调用方处理，以便同时移除带值参数的值
 	}
 	return false
 }
@@ -556,22 +847,40 @@ func (b *FlutterBuilderImpl) validateEnvironment() error {
 		return err
 	}
 
+	// 验证自定义参数冲突
+	if err := b.validateCustomArgs(); err != nil {
+		return err
+	}
+
+	// 验证入口文件
+	if target := b.GetCustomArgString("target"); target != "" {
+		targetPath := target
+		if !filepath.IsAbs(targetPath) {
+			targetPath = filepath.Join(b.projectRoot, target)
+		}
+		if _, err := os.Stat(targetPath); err != nil {
+			return fmt.Errorf("入口文件不存在: %s", target)
+		}
+	}
+
 	return nil
 }
 
 func (b *FlutterBuilderImpl) checkFlutterEnvironment() error {
-	logger.Info("检查Flutter环境...")
+	b.logger.Info("检查Flutter环境...")
 
-	if err := b.executor.RunCommand([]string{"flutter", "--version"}, b.projectRoot); err != nil {
+	if err := b.executor.RunCommandContext(b.buildContext(), []string{"flutter", "--version"}, b.projectRoot); err != nil {
 		return fmt.Errorf("Flutter未安装或不在PATH中")
 	}
 
-	logger.Success("Flutter环境正常")
+	b.logger.Success("Flutter环境正常")
 	return nil
 }
 
 func (b *FlutterBuilderImpl) validatePlatform() error {
-	if b.platform != PlatformAPK && b.platform != PlatformIOS {
+	switch b.platform {
+	case PlatformAPK, PlatformAAB, PlatformIOS, PlatformWeb, PlatformLinux:
+	default:
 		return fmt.Errorf("无效的平台参数: %s", b.platform)
 	}
 
@@ -579,6 +888,19 @@ func (b *FlutterBuilderImpl) validatePlatform() error {
 		return fmt.Errorf("iOS构建需要macOS环境，当前操作系统: %s", runtime.GOOS)
 	}
 
+	if b.platform == PlatformLinux && runtime.GOOS != "linux" {
+		return fmt.Errorf("Linux桌面构建需要Linux环境，当前操作系统: %s", runtime.GOOS)
+	}
+
+	switch mode := b.getBuildMode(); mode {
+	case BuildModeDebug, BuildModeProfile, BuildModeRelease:
+		if mode == BuildModeDebug && b.platform == PlatformIOS && b.iosConfig != nil && b.iosConfig.TeamID != "" {
+			return fmt.Errorf("IPA构建不支持debug模式，请使用profile或release")
+		}
+	default:
+		return fmt.Errorf("无效的构建模式: %s", mode)
+	}
+
 	return nil
 }
 
@@ -587,16 +909,24 @@ func (b *FlutterBuilderImpl) cleanBuildDirectory(buildDir string) error {
 		return nil // 目录不存在，无需清理
 	}
 
-	logger.Info("删除旧的构建目录...")
+	b.logger.Info("删除旧的构建目录...")
 	return os.RemoveAll(buildDir)
 }
 
 func (b *FlutterBuilderImpl) buildAndroidAPK() error {
-	logger.Info("构建Android APK（仅ARM64架构）...")
+	splitPerABI := b.GetCustomArgBool("split_per_abi")
+	targetPlatform := "android-arm64"
+	if splitPerABI {
+		b.logger.Info("构建Android APK（按ABI拆分）...")
+		// 拆分构建默认覆盖所有主流架构
+		targetPlatform = "android-arm,android-arm64,android-x64"
+	} else {
+		b.logger.Info("构建Android APK（仅ARM64架构）...")
+	}
 
 	buildCmd := []string{
 		"flutter", "build", "apk",
-		"--release",
+		b.buildModeFlag(),
 	}
 
 	// 添加默认参数（可被自定义参数覆盖）
@@ -604,83 +934,121 @@ func (b *FlutterBuilderImpl) buildAndroidAPK() error {
 		"--obfuscate",
 		"--split-debug-info=build/debug-info",
 		"--tree-shake-icons",
-		"--target-platform", "android-arm64",
+		"--target-platform", targetPlatform,
 		"--dart-define=FLUTTER_WEB_USE_SKIA=true",
 		"--dart-define=FLUTTER_WEB_AUTO_DETECT=true",
 	}
 
-	// 检查是否禁用默认参数
-	if !b.GetCustomArgBool("disable_default_args") {
-		// 添加默认参数
-		buildCmd = append(buildCmd, defaultArgs...)
+	buildCmd = b.applyBuildArgs(buildCmd, defaultArgs)
+	buildCmd = b.applyTargetPlatform(buildCmd)
 
-		// 移除指定的默认参数
-		if removeArgs := b.GetCustomArgStringSlice("remove_default_args"); len(removeArgs) > 0 {
-			buildCmd = b.removeSpecificArgs(buildCmd, removeArgs)
-			logger.Debug("移除指定默认参数: %v", removeArgs)
-		}
+	if splitPerABI {
+		buildCmd = append(buildCmd, "--split-per-abi")
 	}
 
-	// 添加自定义参数
-	if customArgs := b.GetCustomArgStringSlice("flutter_build_args"); len(customArgs) > 0 {
-		buildCmd = append(buildCmd, customArgs...)
+	if err := b.runCommand(StageBuild, buildCmd); err != nil {
+		return fmt.Errorf("android构建失败: %w", err)
 	}
 
-	// 添加自定义dart-define参数
-	if dartDefines := b.GetCustomArgStringSlice("dart_defines"); len(dartDefines) > 0 {
-		for _, define := range dartDefines {
-			buildCmd = append(buildCmd, "--dart-define="+define)
-		}
+	b.logger.Success("Android APK构建完成")
+
+	// 验证构建产物
+	if err := b.validateBuildArtifacts(); err != nil {
+		return fmt.Errorf("构建产物验证失败: %w", err)
 	}
 
-	// 自定义目标平台
-	if targetPlatform := b.GetCustomArgString("target_platform"); targetPlatform != "" {
-		// 移除默认的target-platform参数
-		for i := 0; i < len(buildCmd)-1; i++ {
-			if buildCmd[i] == "--target-platform" {
-				buildCmd = append(buildCmd[:i], buildCmd[i+2:]...)
-				break
-			}
-		}
-		buildCmd = append(buildCmd, "--target-platform", targetPlatform)
+	b.showAndroidBuildArtifacts()
+	return nil
+}
+
+// buildAndroidAAB 构建Android App Bundle（用于Google Play发布）
+func (b *FlutterBuilderImpl) buildAndroidAAB() error {
+	b.logger.Info("构建Android App Bundle...")
+
+	buildCmd := []string{
+		"flutter", "build", "appbundle",
+		b.buildModeFlag(),
 	}
 
-	if err := b.executor.RunCommand(buildCmd, b.projectRoot); err != nil {
-		return fmt.Errorf("android构建失败: %w", err)
+	// 添加默认参数（可被自定义参数覆盖）
+	defaultArgs := []string{
+		"--obfuscate",
+		"--split-debug-info=build/debug-info",
+		"--tree-shake-icons",
+		"--target-platform", "android-arm64",
+		"--dart-define=FLUTTER_WEB_USE_SKIA=true",
+		"--dart-define=FLUTTER_WEB_AUTO_DETECT=true",
 	}
 
-	logger.Success("Android APK构建完成")
+	buildCmd = b.applyBuildArgs(buildCmd, defaultArgs)
+	buildCmd = b.applyTargetPlatform(buildCmd)
+
+	if err := b.runCommand(StageBuild, buildCmd); err != nil {
+		return fmt.Errorf("android App Bundle构建失败: %w", err)
+	}
+
+	b.logger.Success("Android App Bundle构建完成")
 
 	// 验证构建产物
 	if err := b.validateBuildArtifacts(); err != nil {
 		return fmt.Errorf("构建产物验证失败: %w", err)
 	}
 
-	b.showAndroidBuildArtifacts()
+	b.showAndroidBundleArtifacts()
 	return nil
 }
 
-func (b *FlutterBuilderImpl) buildIOS() error {
-	logger.Info("构建iOS发布版本...")
+// buildWeb 构建Flutter Web发布版本
+func (b *FlutterBuilderImpl) buildWeb() error {
+	b.logger.Info("构建Flutter Web发布版本...")
 
-	// 判断是否提供了证书配置，决定构建类型
-	if b.iosConfig != nil && b.iosConfig.TeamID != "" {
-		// 提供了证书配置，直接构建IPA
-		return b.buildIPA()
-	} else {
-		// 未提供证书配置，仅构建iOS
-		return b.buildIOSOnly()
+	buildCmd := []string{
+		"flutter", "build", "web",
+		b.buildModeFlag(),
 	}
+
+	// 添加默认参数（可被自定义参数覆盖）
+	// Web平台不支持 --obfuscate 和 --split-debug-info
+	defaultArgs := []string{
+		"--tree-shake-icons",
+		"--dart-define=FLUTTER_WEB_USE_SKIA=true",
+		"--dart-define=FLUTTER_WEB_AUTO_DETECT=true",
+	}
+
+	buildCmd = b.applyBuildArgs(buildCmd, defaultArgs)
+
+	// Web渲染器（如 canvaskit、html、skwasm）
+	if renderer := b.GetCustomArgString("web_renderer"); renderer != "" {
+		buildCmd = append(buildCmd, "--web-renderer", renderer)
+	}
+
+	// 部署子路径（如 /admin/）
+	if baseHref := b.GetCustomArgString("base_href"); baseHref != "" {
+		buildCmd = append(buildCmd, "--base-href", baseHref)
+	}
+
+	if err := b.runCommand(StageBuild, buildCmd); err != nil {
+		return fmt.Errorf("web构建失败: %w", err)
+	}
+
+	b.logger.Success("Flutter Web构建完成")
+
+	// 验证构建产物
+	if err := b.validateBuildArtifacts(); err != nil {
+		return fmt.Errorf("构建产物验证失败: %w", err)
+	}
+
+	b.showWebBuildArtifacts()
+	return nil
 }
 
-// buildIOSOnly 仅构建iOS项目（不生成IPA）
-func (b *FlutterBuilderImpl) buildIOSOnly() error {
-	logger.Info("构建iOS发布版本...")
+// buildLinux 构建Linux桌面发布版本
+func (b *FlutterBuilderImpl) buildLinux() error {
+	b.logger.Info("构建Linux桌面发布版本...")
 
-	// 构建iOS项目（不生成IPA）
 	buildCmd := []string{
-		"flutter", "build", "ios",
-		"--release",
+		"flutter", "build", "linux",
+		b.buildModeFlag(),
 	}
 
 	// 添加默认参数（可被自定义参数覆盖）
@@ -692,18 +1060,56 @@ func (b *FlutterBuilderImpl) buildIOSOnly() error {
 		"--dart-define=FLUTTER_WEB_AUTO_DETECT=true",
 	}
 
+	buildCmd = b.applyBuildArgs(buildCmd, defaultArgs)
+	// 支持 linux-x64 / linux-arm64
+	buildCmd = b.applyTargetPlatform(buildCmd)
+
+	if err := b.runCommand(StageBuild, buildCmd); err != nil {
+		return fmt.Errorf("linux构建失败: %w", err)
+	}
+
+	b.logger.Success("Linux桌面构建完成")
+
+	// 验证构建产物
+	if err := b.validateBuildArtifacts(); err != nil {
+		return fmt.Errorf("构建产物验证失败: %w", err)
+	}
+
+	b.showLinuxBuildArtifacts()
+	return nil
+}
+
+// applyBuildArgs 组装默认参数与自定义参数
+func (b *FlutterBuilderImpl) applyBuildArgs(buildCmd []string, defaultArgs []string) []string {
 	// 检查是否禁用默认参数
 	if !b.GetCustomArgBool("disable_default_args") {
-		// 添加默认参数
-		buildCmd = append(buildCmd, defaultArgs...)
+		// 默认参数按构建模式调整
+		defaults := b.modeDefaultArgs(defaultArgs)
 
 		// 移除指定的默认参数
 		if removeArgs := b.GetCustomArgStringSlice("remove_default_args"); len(removeArgs) > 0 {
-			buildCmd = b.removeSpecificArgs(buildCmd, removeArgs)
-			logger.Debug("移除指定默认参数: %v", removeArgs)
+			defaults = b.removeSpecificArgs(defaults, removeArgs)
+			b.logger.Debug("移除指定默认参数: %v", removeArgs)
+		}
+
+		// 与自定义参数同名的默认参数由自定义参数覆盖，避免重复传参
+		buildCmd = append(buildCmd, b.dropOverriddenDefaults(defaults)...)
+	}
+
+	// 产品风味（Web/Linux 不支持 --flavor）
+	if flavor := b.GetCustomArgString("flavor"); flavor != "" {
+		if b.supportsFlavor() {
+			buildCmd = append(buildCmd, "--flavor", flavor)
+		} else {
+			b.logger.Warning("%s 平台不支持 --flavor，已忽略: %s", b.platform, flavor)
 		}
 	}
 
+	// 入口文件（如 lib/main_prod.dart）
+	if target := b.GetCustomArgString("target"); target != "" {
+		buildCmd = append(buildCmd, "--target", target)
+	}
+
 	// 添加自定义参数
 	if customArgs := b.GetCustomArgStringSlice("flutter_build_args"); len(customArgs) > 0 {
 		buildCmd = append(buildCmd, customArgs...)
@@ -716,11 +1122,65 @@ func (b *FlutterBuilderImpl) buildIOSOnly() error {
 		}
 	}
 
-	if err := b.executor.RunCommand(buildCmd, b.projectRoot); err != nil {
+	return buildCmd
+}
+
+// applyTargetPlatform 应用自定义目标平台（仅Android）
+func (b *FlutterBuilderImpl) applyTargetPlatform(buildCmd []string) []string {
+	targetPlatform := b.GetCustomArgString("target_platform")
+	if targetPlatform == "" {
+		return buildCmd
+	}
+
+	// 移除默认的target-platform参数
+	for i := 0; i < len(buildCmd)-1; i++ {
+		if buildCmd[i] == "--target-platform" {
+			buildCmd = append(buildCmd[:i], buildCmd[i+2:]...)
+			break
+		}
+	}
+	return append(buildCmd, "--target-platform", targetPlatform)
+}
+
+func (b *FlutterBuilderImpl) buildIOS() error {
+	b.logger.Info("构建iOS发布版本...")
+
+	// 判断是否提供了证书配置，决定构建类型
+	if b.iosConfig != nil && b.iosConfig.TeamID != "" {
+		// 提供了证书配置，直接构建IPA
+		return b.buildIPA()
+	} else {
+		// 未提供证书配置，仅构建iOS
+		return b.buildIOSOnly()
+	}
+}
+
+// buildIOSOnly 仅构建iOS项目（不生成IPA）
+func (b *FlutterBuilderImpl) buildIOSOnly() error {
+	b.logger.Info("构建iOS发布版本...")
+
+	// 构建iOS项目（不生成IPA）
+	buildCmd := []string{
+		"flutter", "build", "ios",
+		b.buildModeFlag(),
+	}
+
+	// 添加默认参数（可被自定义参数覆盖）
+	defaultArgs := []string{
+		"--obfuscate",
+		"--split-debug-info=build/debug-info",
+		"--tree-shake-icons",
+		"--dart-define=FLUTTER_WEB_USE_SKIA=true",
+		"--dart-define=FLUTTER_WEB_AUTO_DETECT=true",
+	}
+
+	buildCmd = b.applyBuildArgs(buildCmd, defaultArgs)
+
+	if err := b.runCommand(StageBuild, buildCmd); err != nil {
 		return fmt.Errorf("iOS构建失败: %w", err)
 	}
 
-	logger.Success("iOS构建完成")
+	b.logger.Success("iOS构建完成")
 
 	// 验证构建产物
 	if err := b.validateBuildArtifacts(); err != nil {
@@ -732,7 +1192,7 @@ func (b *FlutterBuilderImpl) buildIOSOnly() error {
 }
 
 func (b *FlutterBuilderImpl) buildIPA() error {
-	logger.Info("构建IPA文件...")
+	b.logger.Info("构建IPA文件...")
 
 	// 设置证书（如果提供了动态证书）
 	if b.certManager != nil {
@@ -742,7 +1202,7 @@ func (b *FlutterBuilderImpl) buildIPA() error {
 		// 使用 defer 确保无论是否成功都能清理资源
 		defer func() {
 			if err := b.certManager.ForceCleanupAll(); err != nil {
-				logger.Warning("清理证书资源时发生错误: %v", err)
+				b.logger.Warning("清理证书资源时发生错误: %v", err)
 			}
 		}()
 	}
@@ -756,7 +1216,7 @@ func (b *FlutterBuilderImpl) buildIPA() error {
 	// 使用flutter build ipa命令生成IPA
 	ipaCmd := []string{
 		"flutter", "build", "ipa",
-		"--release",
+		b.buildModeFlag(),
 	}
 
 	// 添加默认参数（可被自定义参数覆盖）
@@ -769,35 +1229,13 @@ func (b *FlutterBuilderImpl) buildIPA() error {
 		"--export-options-plist", exportOptionsPlist,
 	}
 
-	// 检查是否禁用默认参数
-	if !b.GetCustomArgBool("disable_default_args") {
-		// 添加默认参数
-		ipaCmd = append(ipaCmd, defaultArgs...)
-
-		// 移除指定的默认参数
-		if removeArgs := b.GetCustomArgStringSlice("remove_default_args"); len(removeArgs) > 0 {
-			ipaCmd = b.removeSpecificArgs(ipaCmd, removeArgs)
-			logger.Debug("移除指定默认参数: %v", removeArgs)
-		}
-	}
-
-	// 添加自定义参数
-	if customArgs := b.GetCustomArgStringSlice("flutter_build_args"); len(customArgs) > 0 {
-		ipaCmd = append(ipaCmd, customArgs...)
-	}
-
-	// 添加自定义dart-define参数
-	if dartDefines := b.GetCustomArgStringSlice("dart_defines"); len(dartDefines) > 0 {
-		for _, define := range dartDefines {
-			ipaCmd = append(ipaCmd, "--dart-define="+define)
-		}
-	}
+	ipaCmd = b.applyBuildArgs(ipaCmd, defaultArgs)
 
-	if err := b.executor.RunCommand(ipaCmd, b.projectRoot); err != nil {
+	if err := b.runCommand(StageBuild, ipaCmd); err != nil {
 		return fmt.Errorf("IPA构建失败: %w", err)
 	}
 
-	logger.Success("IPA文件生成完成")
+	b.logger.Success("IPA文件生成完成")
 
 	// 验证构建产物
 	if err := b.validateBuildArtifacts(); err != nil {
@@ -807,95 +1245,147 @@ func (b *FlutterBuilderImpl) buildIPA() error {
 	return nil
 }
 
-func (b *FlutterBuilderImpl) createBuildInfo() error {
-	buildInfoPath := filepath.Join(b.projectRoot, "build", "build_info.txt")
-
-	// 确保目录存在
-	if err := os.MkdirAll(filepath.Dir(buildInfoPath), 0755); err != nil {
-		return err
+func (b *FlutterBuilderImpl) showSecurityReminders() {
+	b.logger.Println()
+	b.logger.Header("安全提醒")
+	if b.getBuildMode() == BuildModeRelease {
+		b.logger.Success("代码混淆已启用")
+		b.logger.Success("调试信息已分离")
+		b.logger.Success("Tree Shaking已应用")
+		b.logger.Success("图标Tree Shaking已启用")
+	} else {
+		b.logger.Warning("%s 模式未启用代码混淆，产物仅供内部测试，请勿用于正式发布", b.getBuildMode())
+	}
+
+	b.logger.Println()
+	b.logger.Info("额外安全措施:")
+	b.logger.Println("- 保护调试符号安全 (build/debug-info/)")
+	b.logger.Println("- 验证签名证书配置")
+	b.logger.Println("- 在真实设备上测试")
+	b.logger.Println("- 考虑使用额外的安全工具 (R8, DexGuard)")
+
+	if b.platform == PlatformAPK || b.platform == PlatformAAB {
+		b.logger.Println()
+		b.logger.Info("Android特定:")
+		b.logger.Println("- ProGuard/R8混淆已应用")
+		if targetPlatform := commandFlagValue(b.buildCommand, "--target-platform"); targetPlatform != "" {
+			b.logger.Println("- 目标平台: " + strings.ReplaceAll(targetPlatform, ",", ", "))
+		} else {
+			b.logger.Println("- 目标平台: Flutter 默认 (android-arm, android-arm64, android-x64)")
+		}
+		b.logger.Println("- 验证应用签名配置")
+	} else if b.platform == PlatformIOS {
+		b.logger.Println()
+		b.logger.Info("iOS特定:")
+		b.logger.Println("- Bitcode优化已应用")
+		b.logger.Println("- App Store提交就绪")
+		b.logger.Println("- 验证配置文件")
+	} else if b.platform == PlatformWeb {
+		b.logger.Println()
+		b.logger.Info("Web特定:")
+		b.logger.Println("- Web平台不支持Dart代码混淆，请勿在前端代码中存放密钥")
+		b.logger.Println("- 确认部署路径与 --base-href 一致")
+		b.logger.Println("- 配置服务器对 flutter_service_worker.js 禁用长缓存")
+	} else if b.platform == PlatformLinux {
+		b.logger.Println()
+		b.logger.Info("Linux特定:")
+		b.logger.Println("- 分发时保持bundle目录结构完整 (lib/、data/)")
+		b.logger.Println("- 确认目标系统已安装GTK 3运行库")
 	}
+}
 
-	// 获取Flutter版本信息
-	flutterVersion, err := b.executor.RunCommandWithOutput([]string{"flutter", "--version"}, b.projectRoot)
-	if err != nil {
-		flutterVersion = "无法获取Flutter版本信息"
+func (b *FlutterBuilderImpl) showAndroidBuildArtifacts() {
+	apkDir := filepath.Join(b.projectRoot, "build", "app", "outputs", "flutter-apk")
+
+	if b.GetCustomArgBool("split_per_abi") {
+		apks, err := artifact.FindSplitAPKs(apkDir, b.getFlavor(), artifact.BuildMode(b.getBuildMode()))
+		if err != nil || len(apks) == 0 {
+			return
+		}
+
+		abis := make([]string, 0, len(apks))
+		for abi := range apks {
+			abis = append(abis, abi)
+		}
+		sort.Strings(abis)
+
+		b.logger.Println()
+		b.logger.Info("构建产物:")
+		for _, abi := range abis {
+			apkPath := apks[abi]
+			if info, err := os.Stat(apkPath); err == nil {
+				b.logger.Printf("  APK文件 [%s]: %s (%.2f MB)", abi, filepath.Base(apkPath), float64(info.Size())/(1024*1024))
+			}
+		}
+		b.logger.Printf("  位置: %s", apkDir)
+		b.logger.Printf("  调试信息: %s/build/debug-info/", b.projectRoot)
+		return
 	}
 
-	buildInfoContent := fmt.Sprintf(`构建信息
-==================
-平台: %s
-构建日期: %s
-构建类型: Release
-代码混淆: 已启用
-Tree Shaking: 已启用
-调试信息分离: 已启用
-架构: %s
+	apkName := artifact.APKFileName(b.getFlavor(), "", artifact.BuildMode(b.getBuildMode()))
+	apkPath := filepath.Join(apkDir, apkName)
 
-Flutter版本信息:
-%s
+	if info, err := os.Stat(apkPath); err == nil {
+		apkSizeMB := float64(info.Size()) / (1024 * 1024)
+
+		b.logger.Println()
+		b.logger.Info("构建产物:")
+		b.logger.Printf("  APK文件: %s (%.2f MB)", apkName, apkSizeMB)
+		b.logger.Printf("  位置: %s", filepath.Dir(apkPath))
+		b.logger.Printf("  调试信息: %s/build/debug-info/", b.projectRoot)
+	}
+}
+
+func (b *FlutterBuilderImpl) showAndroidBundleArtifacts() {
+	flavor := b.getFlavor()
+	mode := artifact.BuildMode(b.getBuildMode())
+	aabName := artifact.AABFileName(flavor, mode)
+	aabPath := filepath.Join(b.projectRoot, "build", "app", "outputs", "bundle", artifact.AndroidVariantName(flavor, mode), aabName)
 
-系统信息:
-操作系统: %s
-Go版本: %s
-`,
-		b.platform,
-		time.Now().Format("2006-01-02 15:04:05"),
-		getArchitecture(b.platform),
-		flutterVersion,
-		runtime.GOOS,
-		runtime.Version(),
-	)
+	if info, err := os.Stat(aabPath); err == nil {
+		aabSizeMB := float64(info.Size()) / (1024 * 1024)
 
-	return os.WriteFile(buildInfoPath, []byte(buildInfoContent), 0644)
+		b.logger.Println()
+		b.logger.Info("构建产物:")
+		b.logger.Printf("  AAB文件: %s (%.2f MB)", aabName, aabSizeMB)
+		b.logger.Printf("  位置: %s", filepath.Dir(aabPath))
+		b.logger.Printf("  调试信息: %s/build/debug-info/", b.projectRoot)
+		b.logger.Println()
+		b.logger.Success("AAB文件已生成，可直接上传到Google Play Console")
+	}
 }
 
-func (b *FlutterBuilderImpl) showSecurityReminders() {
-	logger.Println()
-	logger.Header("安全提醒")
-	logger.Success("代码混淆已启用")
-	logger.Success("调试信息已分离")
-	logger.Success("Tree Shaking已应用")
-	logger.Success("图标Tree Shaking已启用")
-
-	logger.Println()
-	logger.Info("额外安全措施:")
-	logger.Println("- 保护调试符号安全 (build/debug-info/)")
-	logger.Println("- 验证签名证书配置")
-	logger.Println("- 在真实设备上测试")
-	logger.Println("- 考虑使用额外的安全工具 (R8, DexGuard)")
+func (b *FlutterBuilderImpl) showWebBuildArtifacts() {
+	webDir := filepath.Join(b.projectRoot, "build", "web")
 
-	if b.platform == PlatformAPK {
-		logger.Println()
-		logger.Info("Android特定:")
-		logger.Println("- ProGuard/R8混淆已应用")
-		logger.Println("- 仅ARM64架构 (已排除x86/x86_64)")
-		logger.Println("- 验证应用签名配置")
-	} else if b.platform == PlatformIOS {
-		logger.Println()
-		logger.Info("iOS特定:")
-		logger.Println("- Bitcode优化已应用")
-		logger.Println("- App Store提交就绪")
-		logger.Println("- 验证配置文件")
+	b.logger.Println()
+	b.logger.Info("构建产物:")
+	b.logger.Printf("  Web目录: %s", webDir)
+	if mainJS, err := os.Stat(filepath.Join(webDir, "main.dart.js")); err == nil {
+		b.logger.Printf("  main.dart.js: %.2f MB", float64(mainJS.Size())/(1024*1024))
 	}
+	b.logger.Println()
+	b.logger.Success("Web产物已生成，可直接部署到静态文件服务器")
 }
 
-func (b *FlutterBuilderImpl) showAndroidBuildArtifacts() {
-	apkPath := filepath.Join(b.projectRoot, "build", "app", "outputs", "flutter-apk", "app-release.apk")
+// linuxArch 返回本次 Linux 构建的目标架构（取自 --target-platform，未指定时为当前主机架构）
+func (b *FlutterBuilderImpl) linuxArch() string {
+	return artifact.LinuxArch(commandFlagValue(b.buildCommand, "--target-platform"))
+}
 
-	if info, err := os.Stat(apkPath); err == nil {
-		apkSizeMB := float64(info.Size()) / (1024 * 1024)
+func (b *FlutterBuilderImpl) showLinuxBuildArtifacts() {
+	b.logger.Println()
+	b.logger.Info("构建产物:")
 
-		logger.Println()
-		logger.Info("构建产物:")
-		logger.Printf("  APK文件: app-release.apk (%.2f MB)", apkSizeMB)
-		logger.Printf("  位置: %s", filepath.Dir(apkPath))
-		logger.Printf("  调试信息: %s/build/debug-info/", b.projectRoot)
-	}
+	b.logger.Printf("  Bundle目录: %s", artifact.LinuxBundlePath(b.projectRoot, b.linuxArch(), artifact.BuildMode(b.getBuildMode())))
+	b.logger.Printf("  调试信息: %s/build/debug-info/", b.projectRoot)
+	b.logger.Println()
+	b.logger.Success("Linux bundle已生成，可整体打包分发")
 }
 
 func (b *FlutterBuilderImpl) showIOSBuildArtifacts() {
-	logger.Println()
-	logger.Info("构建产物:")
+	b.logger.Println()
+	b.logger.Info("构建产物:")
 
 	// 根据是否有证书配置来显示不同的信息
 	if b.iosConfig != nil && b.iosConfig.TeamID != "" {
@@ -903,46 +1393,48 @@ func (b *FlutterBuilderImpl) showIOSBuildArtifacts() {
 		ipaDir := filepath.Join(b.projectRoot, "build", "ios", "ipa")
 
 		// 尝试找到实际生成的IPA文件
-		if files, err := filepath.Glob(filepath.Join(ipaDir, "*.ipa")); err == nil && len(files) > 0 {
-			logger.Printf("  IPA文件: %s", files[0])
+		if ipaPath, err := artifact.FindIPAFile(ipaDir, b.getFlavor()); err == nil {
+			b.logger.Printf("  IPA文件: %s", ipaPath)
 		} else {
-			logger.Printf("  IPA文件目录: %s", ipaDir)
+			b.logger.Printf("  IPA文件目录: %s", ipaDir)
 		}
 
-		logger.Printf("  调试信息: %s/build/debug-info/", b.projectRoot)
-		logger.Println()
-		logger.Success("IPA文件已生成，可直接上传到App Store Connect")
+		b.logger.Printf("  调试信息: %s/build/debug-info/", b.projectRoot)
+		b.logger.Println()
+		b.logger.Success("IPA文件已生成，可直接上传到App Store Connect")
 	} else {
 		// 仅构建iOS项目
 		iosBuildPath := filepath.Join(b.projectRoot, "build", "ios", "iphoneos")
-		runnerAppPath := filepath.Join(iosBuildPath, "Runner.app")
-		logger.Printf("  Runner.app位置: %s", runnerAppPath)
-		logger.Printf("  调试信息: %s/build/debug-info/", b.projectRoot)
-		logger.Println()
-		logger.Info("创建IPA文件:")
-		logger.Println("  1. 在Xcode中打开 ios/Runner.xcworkspace")
-		logger.Println("  2. 选择 'Any iOS Device' 作为目标")
-		logger.Println("  3. Product > Archive")
-		logger.Println("  4. Distribute App > App Store Connect / Ad Hoc / Enterprise")
+		appPath := filepath.Join(iosBuildPath, "Runner.app")
+		if found, err := artifact.FindIOSApp(iosBuildPath, b.getFlavor()); err == nil {
+			appPath = found
+		}
+		b.logger.Printf("  %s位置: %s", filepath.Base(appPath), appPath)
+		b.logger.Printf("  调试信息: %s/build/debug-info/", b.projectRoot)
+		b.logger.Println()
+		b.logger.Info("创建IPA文件:")
+		b.logger.Println("  1. 在Xcode中打开 ios/Runner.xcworkspace")
+		b.logger.Println("  2. 选择 'Any iOS Device' 作为目标")
+		b.logger.Println("  3. Product > Archive")
+		b.logger.Println("  4. Distribute App > App Store Connect / Ad Hoc / Enterprise")
 	}
 }
 
 // 辅助函数（已移除getProjectRoot，现在通过参数传递项目根目录）
 
-func getArchitecture(platform Platform) string {
-	if platform == PlatformAPK {
-		return "ARM64"
-	}
-	return "iOS Universal"
-}
-
 // convertPlatform 将构建器平台转换为验证器平台
 func convertPlatform(platform Platform) artifact.Platform {
 	switch platform {
 	case PlatformAPK:
 		return artifact.PlatformAPK
+	case PlatformAAB:
+		return artifact.PlatformAAB
 	case PlatformIOS:
 		return artifact.PlatformIOS
+	case PlatformWeb:
+		return artifact.PlatformWeb
+	case PlatformLinux:
+		return artifact.PlatformLinux
 	default:
 		return artifact.Platform(platform)
 	}
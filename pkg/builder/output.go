@@ -0,0 +1,226 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mimicode/flutterbuilder/internal/fsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultOutputNameTemplate 默认的产物命名模板
+const DefaultOutputNameTemplate = "{app}-{version}+{build}-{flavor}-{platform}.{ext}"
+
+// outputPlaceholders 命名模板支持的占位符
+var outputPlaceholders = []string{"app", "version", "build", "flavor", "platform", "mode", "abi", "ext"}
+
+// placeholderPattern 匹配占位符及其前面的一个分隔符（占位符取值为空时一并去掉）
+var placeholderPattern = regexp.MustCompile(`([-_+.]?)\{([a-z_]+)\}`)
+
+// OutputConfig 产物收集设置：构建成功后将产物、调试信息和构建信息复制到输出目录，
+// 避免被下一次 flutter clean 删除。命名模板同时用于产物发布
+type OutputConfig struct {
+	// Dir 输出目录（相对路径相对项目根目录），可使用命名模板中的占位符（如 dist/{version}+{build}），为空时不收集
+	Dir string
+	// NameTemplate 产物命名模板，为空时使用 DefaultOutputNameTemplate。
+	// 占位符: {app}（pubspec.yaml 的 name）、{version}、{build}（pubspec.yaml 的 version 或 --build-name/--build-number）、
+	// {flavor}、{platform}、{mode}、{abi}（拆分APK的架构）、{ext}（产物扩展名）。
+	// 取值为空的占位符连同其前面的一个分隔符（- _ + .）一起去掉
+	NameTemplate string
+}
+
+// Validate 检查输出目录和命名模板
+func (c *OutputConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if strings.ContainsAny(c.NameTemplate, `/\`) {
+		return fmt.Errorf("命名模板不能包含路径分隔符: %s", c.NameTemplate)
+	}
+	for _, template := range []string{c.Dir, c.NameTemplate} {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+			if !isOutputPlaceholder(match[2]) {
+				return fmt.Errorf("未知的占位符 {%s}（可选 {%s}）", match[2], strings.Join(outputPlaceholders, "}、{"))
+			}
+		}
+	}
+	return nil
+}
+
+// isOutputPlaceholder 判断是否为支持的占位符
+func isOutputPlaceholder(name string) bool {
+	for _, placeholder := range outputPlaceholders {
+		if placeholder == name {
+			return true
+		}
+	}
+	return false
+}
+
+// nameTemplate 返回命名模板
+func (c *OutputConfig) nameTemplate() string {
+	if c.NameTemplate == "" {
+		return DefaultOutputNameTemplate
+	}
+	return c.NameTemplate
+}
+
+// SetOutput 设置产物收集（nil 表示不收集）
+func (b *FlutterBuilderImpl) SetOutput(output *OutputConfig) error {
+	if err := output.Validate(); err != nil {
+		return err
+	}
+	b.output = output
+	return nil
+}
+
+// GetOutputFiles 返回最近一次构建收集到输出目录的文件和目录
+func (b *FlutterBuilderImpl) GetOutputFiles() []string {
+	return b.outputFiles
+}
+
+// collectOutputs 将构建产物、调试信息目录和构建信息复制到输出目录
+func (b *FlutterBuilderImpl) collectOutputs() error {
+	if b.output == nil || b.output.Dir == "" {
+		return nil
+	}
+	if len(b.buildArtifacts()) == 0 {
+		b.logger.Warning("没有可收集的产物（未执行构建阶段或未找到构建产物）")
+		return nil
+	}
+
+	values := b.outputValues()
+	dir := renderTemplate(b.output.Dir, values)
+	entries, err := b.outputEntries(values)
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(b.projectRoot, dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建输出目录失败: %w", err)
+	}
+	b.logger.Info("收集产物到 %s", dir)
+
+	b.outputFiles = nil
+	for _, entry := range entries {
+		dst := filepath.Join(dir, entry.name)
+		if err := fsutil.CopyPath(entry.src, dst); err != nil {
+			return fmt.Errorf("复制 %s 失败: %w", entry.src, err)
+		}
+		b.outputFiles = append(b.outputFiles, dst)
+		b.logger.Printf("  %s", dst)
+	}
+	return nil
+}
+
+// outputEntry 收集或发布的单个产物
+type outputEntry struct {
+	src  string // 产物路径（文件或目录）
+	name string // 按命名模板生成的名称
+}
+
+// outputEntries 返回构建产物、调试信息目录和构建信息，以及按命名模板生成的名称
+func (b *FlutterBuilderImpl) outputEntries(values map[string]string) ([]outputEntry, error) {
+	template := DefaultOutputNameTemplate
+	if b.output != nil {
+		template = b.output.nameTemplate()
+	}
+
+	// base 为不含扩展名和架构的名称，用于调试信息和构建信息
+	values["abi"], values["ext"] = "", ""
+	base := renderName(template, values)
+
+	var entries []outputEntry
+	for _, file := range b.buildArtifacts() {
+		if file.DebugInfo {
+			entries = append(entries, outputEntry{file.Path, base + "-debug-info"})
+			continue
+		}
+		values["abi"] = file.ABI
+		values["ext"] = strings.TrimPrefix(filepath.Ext(file.Path), ".")
+		name := template
+		// 拆分构建有多个同类产物，模板中没有 {abi} 时在扩展名前追加架构
+		if file.ABI != "" && !strings.Contains(name, "{abi}") {
+			if strings.HasSuffix(name, ".{ext}") {
+				name = strings.TrimSuffix(name, ".{ext}") + "-{abi}.{ext}"
+			} else {
+				name += "-{abi}"
+			}
+		}
+		entries = append(entries, outputEntry{file.Path, renderName(name, values)})
+	}
+	if buildInfo := filepath.Join(b.projectRoot, filepath.FromSlash(BuildInfoFile)); fileExists(buildInfo) {
+		entries = append(entries, outputEntry{buildInfo, base + "-" + filepath.Base(BuildInfoFile)})
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if seen[entry.name] {
+			return nil, fmt.Errorf("产物命名冲突: %s（请检查命名模板）", entry.name)
+		}
+		seen[entry.name] = true
+	}
+	return entries, nil
+}
+
+// outputValues 返回命名模板占位符的取值
+// 版本号和构建号取自 pubspec.yaml 的 version（如 1.2.0+42），flutter build 命令中的 --build-name/--build-number 优先
+func (b *FlutterBuilderImpl) outputValues() map[string]string {
+	values := map[string]string{
+		"flavor":   b.getFlavor(),
+		"platform": string(b.platform),
+		"mode":     string(b.getBuildMode()),
+	}
+
+	var pubspec struct {
+		Name    string `yaml:"name"`
+		Version string `yaml:"version"`
+	}
+	if data, err := os.ReadFile(filepath.Join(b.projectRoot, "pubspec.yaml")); err != nil {
+		b.logger.Warning("读取 pubspec.yaml 失败: %v", err)
+	} else if err := yaml.Unmarshal(data, &pubspec); err != nil {
+		b.logger.Warning("解析 pubspec.yaml 失败: %v", err)
+	}
+	values["app"] = pubspec.Name
+	values["version"], values["build"], _ = strings.Cut(pubspec.Version, "+")
+
+	for flag, key := range map[string]string{"--build-name": "version", "--build-number": "build"} {
+		if value := commandFlagValue(b.buildCommand, flag); value != "" {
+			values[key] = value
+		}
+	}
+
+	// 取值中的路径分隔符替换为下划线，避免产物写到输出目录之外
+	for key, value := range values {
+		values[key] = strings.NewReplacer("/", "_", `\`, "_").Replace(value)
+	}
+	return values
+}
+
+// renderTemplate 替换模板中的占位符，取值为空的占位符连同其前面的一个分隔符一起去掉
+func renderTemplate(template string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(template, func(match string) string {
+		parts := placeholderPattern.FindStringSubmatch(match)
+		value := values[parts[2]]
+		if value == "" {
+			return ""
+		}
+		return parts[1] + value
+	})
+}
+
+// renderName 按命名模板生成文件名（去掉开头多余的分隔符）
+func renderName(template string, values map[string]string) string {
+	return strings.TrimLeft(renderTemplate(template, values), "-_+.")
+}
+
+// fileExists 判断文件是否存在
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
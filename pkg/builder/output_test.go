@@ -0,0 +1,171 @@
+//go:build !windows
+
+package builder
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+)
+
+// splitAPKFlutterScript 模拟按ABI拆分的 flavor 构建，并按 --split-debug-info 生成调试信息
+const splitAPKFlutterScript = `#!/bin/sh
+if [ "$1" = "build" ]; then
+	mkdir -p build/app/outputs/flutter-apk
+	for abi in arm64-v8a x86_64; do
+		cp "$TEST_APK" build/app/outputs/flutter-apk/app-$abi-prod-release.apk
+	done
+	for arg in "$@"; do
+		case "$arg" in
+		--split-debug-info=*)
+			mkdir -p "${arg#*=}"
+			echo symbols > "${arg#*=}/app.android-arm64.symbols"
+			;;
+		esac
+	done
+fi
+exit 0
+`
+
+func TestCollectOutputs(t *testing.T) {
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "flutter"), []byte(splitAPKFlutterScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "dart"), []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("TEST_APK", writeTestAPK(t))
+
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "pubspec.yaml"), []byte("name: shop\nversion: 1.2.0+42\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	log := logger.New()
+	log.SetOutput(io.Discard, io.Discard)
+	b := NewFlutterBuilderWithLogger("apk", nil, project, log).(*FlutterBuilderImpl)
+	b.SetValidationConfig(&artifact.ArtifactValidationConfig{EnableValidation: true, CustomMinSize: 1})
+	b.SetCustomArgs(map[string]interface{}{
+		"flavor":             "prod",
+		"split_per_abi":      true,
+		"flutter_build_args": []string{"--build-number=43"},
+	})
+	if err := b.SetOutput(&OutputConfig{Dir: "dist/{version}"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.RunContext(context.Background()); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	// --build-number 优先于 pubspec.yaml，拆分APK在扩展名前追加架构
+	dir := filepath.Join(project, "dist", "1.2.0")
+	want := []string{
+		filepath.Join(dir, "shop-1.2.0+43-prod-apk-arm64-v8a.apk"),
+		filepath.Join(dir, "shop-1.2.0+43-prod-apk-x86_64.apk"),
+		filepath.Join(dir, "shop-1.2.0+43-prod-apk-debug-info"),
+		filepath.Join(dir, "shop-1.2.0+43-prod-apk-build_info.json"),
+	}
+	if !reflect.DeepEqual(b.GetOutputFiles(), want) {
+		t.Fatalf("收集的文件错误:\n%v\n应为:\n%v", b.GetOutputFiles(), want)
+	}
+	for _, path := range want {
+		if !fileExists(path) {
+			t.Errorf("输出文件不存在: %s", path)
+		}
+	}
+	if !fileExists(filepath.Join(dir, "shop-1.2.0+43-prod-apk-debug-info", "app.android-arm64.symbols")) {
+		t.Error("调试信息目录应完整复制")
+	}
+	if sum, _ := artifact.FileSHA256(want[0]); sum != b.GetValidationResult().Artifacts[0].Checksum {
+		t.Error("复制的产物内容应与原产物一致")
+	}
+}
+
+func TestCollectOutputsWithoutValidation(t *testing.T) {
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "flutter"), []byte(splitAPKFlutterScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("TEST_APK", writeTestAPK(t))
+
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "pubspec.yaml"), []byte("name: shop\nversion: 1.2.0+42\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	log := logger.New()
+	log.SetOutput(io.Discard, io.Discard)
+	b := NewFlutterBuilderWithLogger("apk", nil, project, log).(*FlutterBuilderImpl)
+	b.SetValidationConfig(&artifact.ArtifactValidationConfig{EnableValidation: false})
+	b.SetCustomArgs(map[string]interface{}{
+		"flavor":               "prod",
+		"split_per_abi":        true,
+		"disable_default_args": true,
+	})
+	if err := b.SetOutput(&OutputConfig{Dir: "dist"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.RunContext(context.Background()); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	// 产物验证禁用时直接收集构建生成的产物
+	dir := filepath.Join(project, "dist")
+	want := []string{
+		filepath.Join(dir, "shop-1.2.0+42-prod-apk-arm64-v8a.apk"),
+		filepath.Join(dir, "shop-1.2.0+42-prod-apk-x86_64.apk"),
+		filepath.Join(dir, "shop-1.2.0+42-prod-apk-build_info.json"),
+	}
+	if !reflect.DeepEqual(b.GetOutputFiles(), want) {
+		t.Fatalf("收集的文件错误:\n%v\n应为:\n%v", b.GetOutputFiles(), want)
+	}
+	for _, path := range want {
+		if !fileExists(path) {
+			t.Errorf("输出文件不存在: %s", path)
+		}
+	}
+}
+
+func TestRenderOutputName(t *testing.T) {
+	values := map[string]string{"app": "shop", "version": "1.2.0", "build": "", "flavor": "", "platform": "web", "ext": ""}
+	tests := map[string]string{
+		DefaultOutputNameTemplate:    "shop-1.2.0-web",
+		"{flavor}-{app}_{version}":   "shop_1.2.0",
+		"{app}-v{version}+{build}":   "shop-v1.2.0",
+		"{app}.{platform}.{ext}.zip": "shop.web.zip",
+	}
+	for template, want := range tests {
+		if got := renderName(template, values); got != want {
+			t.Errorf("renderName(%q) = %q，应为 %q", template, got, want)
+		}
+	}
+}
+
+func TestValidateOutputConfig(t *testing.T) {
+	tests := []struct {
+		config  *OutputConfig
+		wantErr bool
+	}{
+		{nil, false},
+		{&OutputConfig{Dir: "dist/{version}+{build}"}, false},
+		{&OutputConfig{Dir: "dist", NameTemplate: "{app}-{abi}.{ext}"}, false},
+		{&OutputConfig{NameTemplate: "{app}.{ext}"}, false},
+		{&OutputConfig{Dir: "dist", NameTemplate: "{app}-{commit}"}, true},
+		{&OutputConfig{Dir: "dist/{branch}"}, true},
+		{&OutputConfig{Dir: "dist", NameTemplate: "{app}/{version}"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) 错误 = %v，期望出错 %v", tt.config, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package builder
+
+import (
+	"github.com/mimicode/flutterbuilder/pkg/publish"
+)
+
+// SetPublishers 设置产物发布后端，构建成功且产物验证通过后依次发布
+func (b *FlutterBuilderImpl) SetPublishers(publishers []publish.Publisher) {
+	b.publishers = publishers
+}
+
+// GetPublishedURLs 返回最近一次构建发布的产物地址（按发布后端和产物顺序）
+func (b *FlutterBuilderImpl) GetPublishedURLs() []string {
+	return b.publishedURLs
+}
+
+// publishArtifacts 将产物、调试信息目录和构建信息发布到各发布后端，
+// 发布名称与产物收集相同（按命名模板生成，未设置产物收集时使用默认模板）
+func (b *FlutterBuilderImpl) publishArtifacts() error {
+	if len(b.publishers) == 0 {
+		return nil
+	}
+	if b.validationResult == nil || !b.validationResult.Success || len(b.validationResult.Artifacts) == 0 {
+		b.logger.Warning("没有可发布的产物（未执行构建阶段或产物验证已禁用）")
+		return nil
+	}
+
+	entries, err := b.outputEntries(b.outputValues())
+	if err != nil {
+		return err
+	}
+
+	ctx := b.buildContext()
+	for _, publisher := range b.publishers {
+		b.logger.Info("发布产物到 %s", publisher.Name())
+		for _, entry := range entries {
+			url, err := publisher.Publish(ctx, entry.name, entry.src)
+			if err != nil {
+				return err
+			}
+			b.publishedURLs = append(b.publishedURLs, url)
+			b.logger.Printf("  %s", url)
+		}
+	}
+	return nil
+}
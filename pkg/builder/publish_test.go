@@ -0,0 +1,93 @@
+//go:build !windows
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+	"github.com/mimicode/flutterbuilder/pkg/publish"
+)
+
+// recordingPublisher 记录发布的名称，err 不为空时发布失败
+type recordingPublisher struct {
+	keys []string
+	err  error
+}
+
+func (p *recordingPublisher) Name() string { return "recording" }
+
+func (p *recordingPublisher) Publish(ctx context.Context, key, localPath string) (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	p.keys = append(p.keys, key)
+	return "mem://" + key, nil
+}
+
+func TestPublishArtifacts(t *testing.T) {
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "flutter"), []byte(apkFlutterScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "dart"), []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("TEST_APK", writeTestAPK(t))
+
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "pubspec.yaml"), []byte("name: shop\nversion: 2.0.0+7\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	newBuilder := func(publishers ...publish.Publisher) *FlutterBuilderImpl {
+		log := logger.New()
+		log.SetOutput(io.Discard, io.Discard)
+		b := NewFlutterBuilderWithLogger("apk", nil, project, log).(*FlutterBuilderImpl)
+		b.SetValidationConfig(&artifact.ArtifactValidationConfig{EnableValidation: true, CustomMinSize: 1})
+		b.SetCustomArgs(map[string]interface{}{"disable_default_args": true})
+		b.SetPublishers(publishers)
+		return b
+	}
+
+	recorder := &recordingPublisher{}
+	nfs := &publish.FilesystemPublisher{Dir: filepath.Join(t.TempDir(), "nfs"), BaseURL: "https://builds.example.com"}
+	b := newBuilder(recorder, nfs)
+	if err := b.RunContext(context.Background()); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	// 未设置产物收集时按默认命名模板发布
+	wantKeys := []string{"shop-2.0.0+7-apk.apk", "shop-2.0.0+7-apk-build_info.json"}
+	if !reflect.DeepEqual(recorder.keys, wantKeys) {
+		t.Errorf("发布的名称错误: %v", recorder.keys)
+	}
+	wantURLs := []string{
+		"mem://shop-2.0.0+7-apk.apk",
+		"mem://shop-2.0.0+7-apk-build_info.json",
+		"https://builds.example.com/shop-2.0.0+7-apk.apk",
+		"https://builds.example.com/shop-2.0.0+7-apk-build_info.json",
+	}
+	if !reflect.DeepEqual(b.GetPublishedURLs(), wantURLs) {
+		t.Errorf("发布地址错误: %v", b.GetPublishedURLs())
+	}
+	if !fileExists(filepath.Join(nfs.Dir, "shop-2.0.0+7-apk.apk")) {
+		t.Error("产物应发布到文件系统目录")
+	}
+
+	// 发布失败时构建失败
+	b = newBuilder(&recordingPublisher{err: errors.New("connection refused")})
+	err := b.RunContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "发布产物失败") {
+		t.Errorf("发布失败时应返回错误: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/executor"
+)
+
+// DefaultRetryBackoff 重试策略未设置等待时间时，首次重试前的等待时间
+const DefaultRetryBackoff = 10 * time.Second
+
+// retryableStages 可以设置重试策略的阶段（执行 flutter pub get、build_runner 和 flutter build 的阶段）
+var retryableStages = []Stage{StageGetDeps, StageCodeGen, StageBuild}
+
+// RetryPolicy 阶段命令的重试策略
+type RetryPolicy struct {
+	Attempts   int           // 最大执行次数（包括首次），小于2时不重试
+	Backoff    time.Duration // 首次重试前的等待时间（0 表示 DefaultRetryBackoff），之后每次翻倍
+	MaxBackoff time.Duration // 等待时间上限（0 表示不限制）
+	RetryOn    []string      // 仅当失败命令的输出匹配其中任一正则表达式时重试（为空时任何失败都重试）
+
+	retryOn []*regexp.Regexp
+}
+
+// CommandAttempt 设置了重试策略的阶段中一次命令执行的记录
+type CommandAttempt struct {
+	Command  []string      // 命令行
+	Attempt  int           // 第几次执行（从1开始）
+	Success  bool          // 是否成功
+	Error    error         // 失败原因
+	Duration time.Duration // 耗时
+}
+
+// ValidateRetryPolicies 检查重试策略的阶段和参数
+func ValidateRetryPolicies(policies map[Stage]*RetryPolicy) error {
+	for stage, policy := range policies {
+		if !containsStage(retryableStages, stage) {
+			names := make([]string, len(retryableStages))
+			for i, s := range retryableStages {
+				names[i] = string(s)
+			}
+			return fmt.Errorf("阶段 %s 不支持重试（可选 %s）", stage, strings.Join(names, "、"))
+		}
+		if policy == nil {
+			continue
+		}
+		if policy.Attempts < 0 || policy.Backoff < 0 || policy.MaxBackoff < 0 {
+			return fmt.Errorf("阶段 %s 的重试次数和等待时间不能为负数", stage)
+		}
+		if _, err := compileRetryPatterns(policy.RetryOn); err != nil {
+			return fmt.Errorf("阶段 %s 的重试条件无效: %w", stage, err)
+		}
+	}
+	return nil
+}
+
+// compileRetryPatterns 编译重试条件
+func compileRetryPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// SetRetryPolicies 设置各阶段的重试策略（nil 表示不重试）
+func (b *FlutterBuilderImpl) SetRetryPolicies(policies map[Stage]*RetryPolicy) error {
+	if err := ValidateRetryPolicies(policies); err != nil {
+		return err
+	}
+
+	b.retryPolicies = make(map[Stage]*RetryPolicy, len(policies))
+	for stage, policy := range policies {
+		if policy == nil {
+			continue
+		}
+		p := *policy
+		p.retryOn, _ = compileRetryPatterns(policy.RetryOn)
+		b.retryPolicies[stage] = &p
+	}
+	return nil
+}
+
+// shouldRetry 判断失败的命令是否满足重试条件
+func (p *RetryPolicy) shouldRetry(err error) bool {
+	if len(p.retryOn) == 0 {
+		return true
+	}
+	output := executor.CommandOutput(err)
+	for _, line := range output {
+		for _, re := range p.retryOn {
+			if re.MatchString(line) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// backoff 返回第 attempt 次失败后的等待时间
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.Backoff
+	if wait == 0 {
+		wait = DefaultRetryBackoff
+	}
+	for i := 1; i < attempt && (p.MaxBackoff == 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	return wait
+}
+
+// runCommand 在项目根目录执行阶段命令，阶段设置了重试策略时按策略重试并记录每次执行
+func (b *FlutterBuilderImpl) runCommand(stage Stage, cmd []string) error {
+	ctx := b.buildContext()
+	if stage == StageBuild {
+		b.buildCommand = cmd
+	}
+	policy := b.retryPolicies[stage]
+	if policy == nil {
+		return b.executor.RunCommandContext(ctx, cmd, b.projectRoot)
+	}
+
+	for attempt := 1; ; attempt++ {
+		startTime := time.Now()
+		err := b.executor.RunCommandContext(ctx, cmd, b.projectRoot)
+		b.attempts = append(b.attempts, CommandAttempt{
+			Command:  cmd,
+			Attempt:  attempt,
+			Success:  err == nil,
+			Error:    err,
+			Duration: time.Since(startTime),
+		})
+		if err == nil || ctx.Err() != nil || attempt >= policy.Attempts {
+			return err
+		}
+		if !policy.shouldRetry(err) {
+			b.logger.Warning("命令执行失败，输出不满足重试条件，不再重试: %s", strings.Join(cmd, " "))
+			return err
+		}
+
+		wait := policy.backoff(attempt)
+		b.logger.Warning("命令执行失败（第 %d/%d 次），%v 后重试: %s", attempt, policy.Attempts, wait, strings.Join(cmd, " "))
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// sleepContext 等待指定时间，ctx 取消时提前返回取消错误
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+//go:build !windows
+
+package builder
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+)
+
+// flakyFlutterScript 模拟flutter命令：pub get 在计数文件中的次数用完前一直失败
+const flakyFlutterScript = `#!/bin/sh
+if [ "$1" = "pub" ]; then
+	count=$(cat "$FLAKY_COUNT")
+	if [ "$count" -gt 0 ]; then
+		echo $((count - 1)) > "$FLAKY_COUNT"
+		echo "Got socket error trying to find package: Connection reset by peer" >&2
+		exit 1
+	fi
+fi
+exit 0
+`
+
+func TestRetryPolicy(t *testing.T) {
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "flutter"), []byte(flakyFlutterScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "dart"), []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	countFile := filepath.Join(t.TempDir(), "count")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FLAKY_COUNT", countFile)
+
+	// run 以给定的失败次数和重试策略只执行 get_deps 阶段
+	run := func(failures string, policy *RetryPolicy) ([]CommandAttempt, error) {
+		t.Helper()
+		if err := os.WriteFile(countFile, []byte(failures), 0644); err != nil {
+			t.Fatal(err)
+		}
+		log := logger.New()
+		log.SetOutput(io.Discard, io.Discard)
+		b := NewFlutterBuilderWithLogger("apk", nil, t.TempDir(), log).(*FlutterBuilderImpl)
+		b.SetValidationConfig(&artifact.ArtifactValidationConfig{EnableValidation: false})
+		if err := b.SetStageSelection(&StageSelection{Only: []Stage{StageGetDeps}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.SetRetryPolicies(map[Stage]*RetryPolicy{StageGetDeps: policy}); err != nil {
+			t.Fatal(err)
+		}
+		err := b.RunContext(context.Background())
+		return b.GetStageResults()[1].Attempts, err
+	}
+
+	attempts, err := run("2", &RetryPolicy{Attempts: 3, Backoff: time.Millisecond, RetryOn: []string{"Connection reset"}})
+	if err != nil {
+		t.Fatalf("第三次执行应成功: %v", err)
+	}
+	if len(attempts) != 3 || attempts[0].Success || attempts[1].Success || !attempts[2].Success || attempts[2].Attempt != 3 {
+		t.Errorf("执行记录错误: %+v", attempts)
+	}
+
+	attempts, err = run("5", &RetryPolicy{Attempts: 2, Backoff: time.Millisecond})
+	if err == nil || len(attempts) != 2 {
+		t.Errorf("超过最大次数后应失败: err=%v, 记录=%d", err, len(attempts))
+	}
+
+	attempts, err = run("1", &RetryPolicy{Attempts: 3, Backoff: time.Millisecond, RetryOn: []string{"version solving failed"}})
+	if err == nil || len(attempts) != 1 {
+		t.Errorf("输出不满足重试条件时不应重试: err=%v, 记录=%d", err, len(attempts))
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := policy.backoff(attempt); got != want {
+			t.Errorf("第 %d 次失败后期望等待 %v，实际 %v", attempt, want, got)
+		}
+	}
+	if got := (&RetryPolicy{}).backoff(1); got != DefaultRetryBackoff {
+		t.Errorf("未设置等待时间时期望 %v，实际 %v", DefaultRetryBackoff, got)
+	}
+}
+
+func TestValidateRetryPolicies(t *testing.T) {
+	tests := map[string]map[Stage]*RetryPolicy{
+		"stage":    {StageClean: {Attempts: 2}},
+		"negative": {StageBuild: {Attempts: -1}},
+		"pattern":  {StageBuild: {Attempts: 2, RetryOn: []string{"("}}},
+	}
+	for name, policies := range tests {
+		if err := ValidateRetryPolicies(policies); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+	}
+	if err := ValidateRetryPolicies(map[Stage]*RetryPolicy{StageGetDeps: {Attempts: 3}}); err != nil {
+		t.Errorf("有效策略不应返回错误: %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package builder
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// StageLogDir 阶段输出日志目录（相对项目根目录），每个阶段一个 <stage>.log
+const StageLogDir = "build/flutterbuilder-logs"
+
+// stageOutputTailLines 阶段记录中保留的输出末尾行数
+const stageOutputTailLines = 50
+
+// stageLog 单个阶段的输出日志：命令和钩子输出写入日志文件，并保留末尾若干行
+// 命令的标准输出和标准错误在不同的 goroutine 中写入，所有方法均并发安全，nil 日志忽略写入
+type stageLog struct {
+	mu   sync.Mutex
+	path string   // 日志文件最终路径
+	file *os.File // 正在写入的文件（clean 阶段写入临时文件，结束后移动到 path）
+	temp bool
+	tail []string
+	err  error // 第一个写入错误，之后不再写入文件
+}
+
+// openStageLog 创建阶段输出日志，无法创建文件时仍保留输出末尾
+// clean 阶段会删除 build 目录，其日志先写入临时文件，阶段结束后再移动到日志目录
+func (b *FlutterBuilderImpl) openStageLog(stage Stage) *stageLog {
+	log := &stageLog{path: filepath.Join(b.projectRoot, StageLogDir, string(stage)+".log")}
+
+	var err error
+	if stage == StageClean {
+		log.file, err = os.CreateTemp("", "flutterbuilder-"+string(stage)+"-*.log")
+		log.temp = true
+	} else if err = os.MkdirAll(filepath.Dir(log.path), 0755); err == nil {
+		log.file, err = os.Create(log.path)
+	}
+	if err != nil {
+		b.logger.Warning("无法创建阶段日志 %s: %v", log.path, err)
+		log.err = err
+	}
+	return log
+}
+
+// writeLine 写入一行输出
+func (l *stageLog) writeLine(line string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tail = append(l.tail, line)
+	if len(l.tail) > stageOutputTailLines {
+		l.tail = l.tail[len(l.tail)-stageOutputTailLines:]
+	}
+	if l.file != nil && l.err == nil {
+		_, l.err = fmt.Fprintln(l.file, line)
+	}
+}
+
+// close 关闭日志文件，返回日志路径（未能写入时为空）和输出末尾
+func (l *stageLog) close() (string, []string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tail := append([]string(nil), l.tail...)
+	if l.file == nil {
+		// 创建失败时已经提示过
+		return "", tail, nil
+	}
+
+	file := l.file
+	l.file = nil
+	if l.temp {
+		defer os.Remove(file.Name())
+		if l.err == nil {
+			l.err = copyStageLog(file, l.path)
+		}
+	}
+	if err := file.Close(); err != nil && l.err == nil {
+		l.err = err
+	}
+	if l.err != nil {
+		return "", tail, l.err
+	}
+	return l.path, tail, nil
+}
+
+// copyStageLog 将临时日志文件复制到最终路径
+func copyStageLog(file *os.File, path string) error {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, file); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// setStageLog 设置当前阶段的输出日志（nil 表示不在阶段中）
+func (b *FlutterBuilderImpl) setStageLog(log *stageLog) {
+	b.stageLogMu.Lock()
+	defer b.stageLogMu.Unlock()
+	b.stageLog = log
+}
+
+// currentStageLog 返回当前阶段的输出日志，不在阶段中时为nil
+func (b *FlutterBuilderImpl) currentStageLog() *stageLog {
+	b.stageLogMu.Lock()
+	defer b.stageLogMu.Unlock()
+	return b.stageLog
+}
+
+// runStage 执行单个阶段，期间的命令和钩子输出写入该阶段的日志文件
+func (b *FlutterBuilderImpl) runStage(stage Stage, run func() error) (StageResult, error) {
+	startTime := time.Now()
+	log := b.openStageLog(stage)
+	b.setStageLog(log)
+	b.attempts = nil
+	b.currentStage = stage
+	err := b.runWithStageTimeout(stage, run)
+	if err == nil {
+		b.currentStage = ""
+	}
+	b.setStageLog(nil)
+
+	result := StageResult{Stage: stage, Status: StageStatusSuccess, Duration: time.Since(startTime), Attempts: b.attempts}
+	b.attempts = nil
+	if err != nil {
+		result.Status = StageStatusFailed
+	}
+	var logErr error
+	result.LogFile, result.OutputTail, logErr = log.close()
+	if logErr != nil {
+		b.logger.Warning("写入阶段日志失败 %s: %v", log.path, logErr)
+	}
+	return result, err
+}
@@ -0,0 +1,160 @@
+//go:build !windows
+
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+	"github.com/mimicode/flutterbuilder/pkg/events"
+	"github.com/mimicode/flutterbuilder/pkg/hooks"
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+)
+
+// echoFlutterScript 模拟flutter命令：输出子命令名称，clean 删除 build 目录
+const echoFlutterScript = `#!/bin/sh
+echo "flutter $1 output"
+if [ "$1" = "clean" ]; then
+	rm -rf build
+fi
+exit 0
+`
+
+func TestStageLogs(t *testing.T) {
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "flutter"), []byte(echoFlutterScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// 钩子脚本通过 dart run 执行，模拟的 dart 输出一行后失败
+	if err := os.WriteFile(filepath.Join(binDir, "dart"), []byte("#!/bin/sh\necho \"dart $1 failed\"\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "hook.dart"), []byte("void main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	log := logger.New()
+	log.SetOutput(io.Discard, io.Discard)
+	b := NewFlutterBuilderWithLogger("apk", nil, project, log).(*FlutterBuilderImpl)
+	b.SetValidationConfig(&artifact.ArtifactValidationConfig{EnableValidation: false})
+	if err := b.RegisterHook(hooks.HookPreBuild, &hooks.HookConfig{ScriptPath: "hook.dart"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.RunContext(context.Background()); err == nil {
+		t.Fatal("前置钩子失败时构建应失败")
+	}
+
+	results := map[Stage]StageResult{}
+	for _, result := range b.GetStageResults() {
+		results[result.Stage] = result
+	}
+
+	// clean 阶段删除了 build 目录，日志仍应写入日志目录
+	clean := results[StageClean]
+	if want := filepath.Join(project, StageLogDir, "clean.log"); clean.LogFile != want {
+		t.Fatalf("clean 阶段日志路径期望 %s，实际 %q", want, clean.LogFile)
+	}
+	content, err := os.ReadFile(clean.LogFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "$ flutter clean") || !strings.Contains(string(content), "flutter clean output") {
+		t.Errorf("clean 阶段日志内容错误:\n%s", content)
+	}
+
+	build := results[StageBuild]
+	if build.Status != StageStatusFailed || build.LogFile == "" {
+		t.Fatalf("build 阶段记录错误: %+v", build)
+	}
+	content, err = os.ReadFile(build.LogFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "[pre_build 钩子] dart run hook.dart") || !strings.Contains(string(content), "dart run failed") {
+		t.Errorf("build 阶段日志应包含钩子输出:\n%s", content)
+	}
+	if tail := build.OutputTail; len(tail) == 0 || !strings.HasPrefix(tail[len(tail)-1], "(退出码: 1") {
+		t.Errorf("build 阶段输出末尾错误: %q", tail)
+	}
+}
+
+func TestStageLogRedactsDartDefines(t *testing.T) {
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "flutter"), []byte(echoFlutterScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	log := logger.New()
+	log.SetOutput(io.Discard, io.Discard)
+	b := NewFlutterBuilderWithLogger("apk", nil, t.TempDir(), log).(*FlutterBuilderImpl)
+	b.SetValidationConfig(&artifact.ArtifactValidationConfig{EnableValidation: false})
+	b.SetCustomArgs(map[string]interface{}{
+		"disable_default_args": true,
+		"dart_defines":         []string{"API_URL=https://api.example.com", "API_KEY=s3cr3t"},
+	})
+	var stream strings.Builder
+	b.SetEventHandler(func(event events.Event) {
+		data, err := json.Marshal(event)
+		if err != nil {
+			t.Error(err)
+		}
+		stream.Write(append(data, '\n'))
+	})
+	if err := b.RunContext(context.Background()); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	var build StageResult
+	for _, result := range b.GetStageResults() {
+		if result.Stage == StageBuild {
+			build = result
+		}
+	}
+	content, err := os.ReadFile(build.LogFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "--dart-define=API_KEY="+redactedValue) || strings.Contains(string(content), "s3cr3t") {
+		t.Errorf("阶段日志中的敏感 dart-define 应脱敏:\n%s", content)
+	}
+	if strings.Contains(strings.Join(build.OutputTail, "\n"), "s3cr3t") {
+		t.Errorf("阶段输出末尾中的敏感 dart-define 应脱敏: %q", build.OutputTail)
+	}
+	if !strings.Contains(stream.String(), "API_URL=https://api.example.com") || strings.Contains(stream.String(), "s3cr3t") {
+		t.Errorf("事件中的敏感 dart-define 应脱敏:\n%s", stream.String())
+	}
+}
+
+func TestStageLogTail(t *testing.T) {
+	b := newTestBuilder(t, "apk", nil)
+	log := b.openStageLog(StageBuild)
+	for i := 0; i < stageOutputTailLines+10; i++ {
+		log.writeLine(fmt.Sprintf("line %d", i))
+	}
+	path, tail, err := log.close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tail) != stageOutputTailLines || tail[0] != "line 10" {
+		t.Errorf("输出末尾应保留最后 %d 行，实际 %d 行，首行 %q", stageOutputTailLines, len(tail), tail[0])
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(content), "\n"); lines != stageOutputTailLines+10 {
+		t.Errorf("日志文件应包含全部 %d 行，实际 %d 行", stageOutputTailLines+10, lines)
+	}
+}
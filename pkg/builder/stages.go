@@ -0,0 +1,123 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Stage 构建阶段，名称与对应钩子类型（pre_<stage>/post_<stage>）一致
+type Stage string
+
+const (
+	StageClean         Stage = "clean"          // 清理
+	StageGetDeps       Stage = "get_deps"       // 获取依赖
+	StageCodeGen       Stage = "code_gen"       // 代码生成
+	StageSecurityCheck Stage = "security_check" // 安全检查
+	StageBuild         Stage = "build"          // 构建（包括产物验证）
+	StagePostProcess   Stage = "post_process"   // 后处理
+)
+
+// AllStages 按执行顺序返回所有构建阶段
+func AllStages() []Stage {
+	return []Stage{StageClean, StageGetDeps, StageCodeGen, StageSecurityCheck, StageBuild, StagePostProcess}
+}
+
+// IsValid 判断是否为支持的构建阶段
+func (s Stage) IsValid() bool {
+	return s.index() >= 0
+}
+
+// index 返回阶段在执行顺序中的位置，未知阶段返回-1
+func (s Stage) index() int {
+	for i, stage := range AllStages() {
+		if s == stage {
+			return i
+		}
+	}
+	return -1
+}
+
+// StageStatus 阶段执行状态
+type StageStatus string
+
+const (
+	StageStatusSuccess StageStatus = "success" // 执行成功
+	StageStatusFailed  StageStatus = "failed"  // 执行失败
+	StageStatusSkipped StageStatus = "skipped" // 按阶段选择跳过（前置/后置钩子同样跳过）
+)
+
+// StageResult 单个阶段的执行记录
+type StageResult struct {
+	Stage      Stage            // 阶段
+	Status     StageStatus      // 状态
+	Reason     string           // 跳过原因
+	Duration   time.Duration    // 耗时（跳过时为0）
+	LogFile    string           // 阶段输出日志文件（build/flutterbuilder-logs/<stage>.log，跳过或无法写入时为空）
+	OutputTail []string         // 阶段输出的最后若干行（命令和钩子输出）
+	Attempts   []CommandAttempt // 设置了重试策略时每次命令执行的记录
+}
+
+// StageSelection 阶段选择：跳过指定阶段、只执行指定阶段或从指定阶段继续
+type StageSelection struct {
+	Skip       []Stage // 跳过的阶段
+	Only       []Stage // 只执行的阶段（不能与 Skip、ResumeFrom 同时使用）
+	ResumeFrom Stage   // 从该阶段开始执行，之前的阶段全部跳过
+}
+
+// Validate 检查阶段名称和选项组合
+func (s *StageSelection) Validate() error {
+	if s == nil {
+		return nil
+	}
+	for _, stages := range [][]Stage{s.Skip, s.Only} {
+		for _, stage := range stages {
+			if !stage.IsValid() {
+				return invalidStageError(stage)
+			}
+		}
+	}
+	if s.ResumeFrom != "" && !s.ResumeFrom.IsValid() {
+		return invalidStageError(s.ResumeFrom)
+	}
+	if len(s.Only) > 0 && (len(s.Skip) > 0 || s.ResumeFrom != "") {
+		return fmt.Errorf("只执行指定阶段（only）不能与跳过阶段（skip）或继续阶段（resume-from）同时使用")
+	}
+	return nil
+}
+
+// skipReason 返回阶段是否跳过及原因
+func (s *StageSelection) skipReason(stage Stage) (bool, string) {
+	if s == nil {
+		return false, ""
+	}
+	if len(s.Only) > 0 && !containsStage(s.Only, stage) {
+		return true, "不在只执行的阶段中"
+	}
+	if s.ResumeFrom != "" && stage.index() < s.ResumeFrom.index() {
+		return true, fmt.Sprintf("从 %s 阶段继续", s.ResumeFrom)
+	}
+	if containsStage(s.Skip, stage) {
+		return true, "指定跳过"
+	}
+	return false, ""
+}
+
+// containsStage 判断阶段列表是否包含指定阶段
+func containsStage(stages []Stage, stage Stage) bool {
+	for _, s := range stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidStageError 未知阶段错误，列出可选阶段
+func invalidStageError(stage Stage) error {
+	names := make([]string, 0, len(AllStages()))
+	for _, s := range AllStages() {
+		names = append(names, string(s))
+	}
+	return fmt.Errorf("未知的构建阶段: %s（可选 %s）", stage, strings.Join(names, "、"))
+}
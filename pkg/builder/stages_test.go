@@ -0,0 +1,65 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStageSelectionValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection *StageSelection
+		wantErr   string
+	}{
+		{"nil", nil, ""},
+		{"skip", &StageSelection{Skip: []Stage{StageClean, StageGetDeps}}, ""},
+		{"skip and resume", &StageSelection{Skip: []Stage{StagePostProcess}, ResumeFrom: StageBuild}, ""},
+		{"unknown stage", &StageSelection{Skip: []Stage{"sign"}}, "未知的构建阶段"},
+		{"unknown resume", &StageSelection{ResumeFrom: "deps"}, "deps"},
+		{"only with skip", &StageSelection{Only: []Stage{StageBuild}, Skip: []Stage{StageClean}}, "不能与"},
+		{"only with resume", &StageSelection{Only: []Stage{StageBuild}, ResumeFrom: StageBuild}, "不能与"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.selection.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("期望无错误，实际 %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("期望包含 %q 的错误，实际 %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStageSelectionSkip(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection *StageSelection
+		skipped   []Stage
+	}{
+		{"all stages", nil, nil},
+		{"skip", &StageSelection{Skip: []Stage{StageClean, StageCodeGen}}, []Stage{StageClean, StageCodeGen}},
+		{"only", &StageSelection{Only: []Stage{StageBuild}}, []Stage{StageClean, StageGetDeps, StageCodeGen, StageSecurityCheck, StagePostProcess}},
+		{"resume", &StageSelection{ResumeFrom: StageBuild}, []Stage{StageClean, StageGetDeps, StageCodeGen, StageSecurityCheck}},
+		{"resume and skip", &StageSelection{ResumeFrom: StageSecurityCheck, Skip: []Stage{StagePostProcess}}, []Stage{StageClean, StageGetDeps, StageCodeGen, StagePostProcess}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, stage := range AllStages() {
+				skip, reason := tt.selection.skipReason(stage)
+				if want := containsStage(tt.skipped, stage); skip != want {
+					t.Errorf("阶段 %s 跳过期望 %v，实际 %v", stage, want, skip)
+				}
+				if skip && reason == "" {
+					t.Errorf("阶段 %s 跳过时应记录原因", stage)
+				}
+			}
+		})
+	}
+}
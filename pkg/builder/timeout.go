@@ -0,0 +1,103 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Timeouts 构建超时设置，0 表示不限制
+// 超时后终止正在运行的命令或钩子的整个进程树，iOS 构建的临时证书照常清理
+type Timeouts struct {
+	Build  time.Duration           // 整个构建的超时时间
+	Stages map[Stage]time.Duration // 各阶段的超时时间（包括阶段的前置/后置钩子和重试）
+}
+
+// Validate 检查阶段名称和超时时间
+func (t *Timeouts) Validate() error {
+	if t == nil {
+		return nil
+	}
+	if t.Build < 0 {
+		return fmt.Errorf("构建超时时间不能为负数")
+	}
+	for stage, timeout := range t.Stages {
+		if !stage.IsValid() {
+			return invalidStageError(stage)
+		}
+		if timeout < 0 {
+			return fmt.Errorf("阶段 %s 的超时时间不能为负数", stage)
+		}
+	}
+	return nil
+}
+
+// TimeoutError 构建或阶段超时错误，errors.Is(err, context.DeadlineExceeded) 同样成立
+type TimeoutError struct {
+	Stage   Stage         // 超时的阶段（整个构建超时时为当时正在执行的阶段，可能为空）
+	Timeout time.Duration // 超时时间
+	Build   bool          // true 表示整个构建超时，false 表示阶段超时
+}
+
+func (e *TimeoutError) Error() string {
+	if !e.Build {
+		return fmt.Sprintf("阶段 %s 执行超时（%v）", e.Stage, e.Timeout)
+	}
+	if e.Stage != "" {
+		return fmt.Sprintf("构建超时（%v），超时时正在执行阶段 %s", e.Timeout, e.Stage)
+	}
+	return fmt.Sprintf("构建超时（%v）", e.Timeout)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// SetTimeouts 设置构建和各阶段的超时时间（nil 表示不限制）
+func (b *FlutterBuilderImpl) SetTimeouts(timeouts *Timeouts) error {
+	if err := timeouts.Validate(); err != nil {
+		return err
+	}
+	b.timeouts = timeouts
+	return nil
+}
+
+// buildTimeout 返回整个构建的超时时间
+func (b *FlutterBuilderImpl) buildTimeout() time.Duration {
+	if b.timeouts == nil {
+		return 0
+	}
+	return b.timeouts.Build
+}
+
+// stageTimeout 返回阶段的超时时间
+func (b *FlutterBuilderImpl) stageTimeout(stage Stage) time.Duration {
+	if b.timeouts == nil {
+		return 0
+	}
+	return b.timeouts.Stages[stage]
+}
+
+// runWithStageTimeout 在阶段超时时间内执行 run，超时时返回 *TimeoutError
+func (b *FlutterBuilderImpl) runWithStageTimeout(stage Stage, run func() error) error {
+	timeout := b.stageTimeout(stage)
+	if timeout <= 0 {
+		return run()
+	}
+
+	parent := b.ctx
+	ctx, cancel := context.WithTimeout(b.buildContext(), timeout)
+	defer cancel()
+	b.ctx = ctx
+	defer func() {
+		b.ctx = parent
+	}()
+
+	err := run()
+	// 仅阶段自身的期限到达时视为阶段超时，外层取消或构建超时由 RunContext 处理
+	if err != nil && ctx.Err() == context.DeadlineExceeded && (parent == nil || parent.Err() == nil) {
+		b.logger.Error("阶段 %s 执行超时（%v），已终止正在运行的命令", stage, timeout)
+		return &TimeoutError{Stage: stage, Timeout: timeout}
+	}
+	return err
+}
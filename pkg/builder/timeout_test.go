@@ -0,0 +1,98 @@
+//go:build !windows
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+)
+
+// hangingFlutterScript 模拟flutter命令：pub get 启动子进程后一直等待
+const hangingFlutterScript = `#!/bin/sh
+if [ "$1" = "pub" ]; then
+	sleep 30
+fi
+exit 0
+`
+
+func TestTimeouts(t *testing.T) {
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "flutter"), []byte(hangingFlutterScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "dart"), []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// run 以给定的超时设置执行 clean 和 get_deps 阶段
+	run := func(timeouts *Timeouts) error {
+		t.Helper()
+		log := logger.New()
+		log.SetOutput(io.Discard, io.Discard)
+		b := NewFlutterBuilderWithLogger("apk", nil, t.TempDir(), log).(*FlutterBuilderImpl)
+		b.SetValidationConfig(&artifact.ArtifactValidationConfig{EnableValidation: false})
+		if err := b.SetStageSelection(&StageSelection{Only: []Stage{StageClean, StageGetDeps}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.SetTimeouts(timeouts); err != nil {
+			t.Fatal(err)
+		}
+		return b.RunContext(context.Background())
+	}
+
+	tests := []struct {
+		name     string
+		timeouts *Timeouts
+		build    bool
+	}{
+		{"stage", &Timeouts{Stages: map[Stage]time.Duration{StageClean: time.Minute, StageGetDeps: 200 * time.Millisecond}}, false},
+		{"build", &Timeouts{Build: 300 * time.Millisecond}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			err := run(tt.timeouts)
+			if elapsed := time.Since(start); elapsed > 10*time.Second {
+				t.Errorf("超时后应立即终止进程树，实际耗时 %v", elapsed)
+			}
+
+			var timeoutErr *TimeoutError
+			if !errors.As(err, &timeoutErr) {
+				t.Fatalf("期望超时错误，实际 %v", err)
+			}
+			if timeoutErr.Stage != StageGetDeps || timeoutErr.Build != tt.build {
+				t.Errorf("超时错误内容错误: %+v", timeoutErr)
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Error("超时错误应匹配 context.DeadlineExceeded")
+			}
+		})
+	}
+}
+
+func TestValidateTimeouts(t *testing.T) {
+	valid := &Timeouts{Build: time.Hour, Stages: map[Stage]time.Duration{StageBuild: 30 * time.Minute}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("合法的超时设置被拒绝: %v", err)
+	}
+
+	invalid := []*Timeouts{
+		{Build: -time.Second},
+		{Stages: map[Stage]time.Duration{"archive": time.Minute}},
+		{Stages: map[Stage]time.Duration{StageBuild: -time.Minute}},
+	}
+	for _, timeouts := range invalid {
+		if err := timeouts.Validate(); err == nil {
+			t.Errorf("非法的超时设置应被拒绝: %+v", timeouts)
+		}
+	}
+}
@@ -1,10 +1,13 @@
 package builder
 
 import (
+	"context"
 	"time"
 
 	"github.com/mimicode/flutterbuilder/pkg/artifact"
+	"github.com/mimicode/flutterbuilder/pkg/events"
 	"github.com/mimicode/flutterbuilder/pkg/hooks"
+	"github.com/mimicode/flutterbuilder/pkg/publish"
 	"github.com/mimicode/flutterbuilder/pkg/types"
 )
 
@@ -12,8 +15,20 @@ import (
 type Platform string
 
 const (
-	PlatformAPK Platform = "apk"
-	PlatformIOS Platform = "ios"
+	PlatformAPK   Platform = "apk"
+	PlatformAAB   Platform = "aab"
+	PlatformIOS   Platform = "ios"
+	PlatformWeb   Platform = "web"
+	PlatformLinux Platform = "linux"
+)
+
+// BuildMode 构建模式
+type BuildMode string
+
+const (
+	BuildModeDebug   BuildMode = "debug"
+	BuildModeProfile BuildMode = "profile"
+	BuildModeRelease BuildMode = "release"
 )
 
 // IOSConfig iOS构建配置
@@ -31,6 +46,7 @@ type BuildResult struct {
 // FlutterBuilder Flutter构建器接口
 type FlutterBuilder interface {
 	Run() error
+	RunContext(ctx context.Context) error // 可取消的构建，取消时终止进程树并清理证书
 	Clean() error
 	GetDependencies() error
 	RunCodeGeneration() error
@@ -47,8 +63,23 @@ type FlutterBuilder interface {
 	ClearHooks(hookType hooks.HookType)                                   // 清空指定类型的钩子
 	ClearAllHooks()                                                       // 清空所有钩子
 	// 验证相关方法
-	SetValidationConfig(config *artifact.ArtifactValidationConfig)       // 设置验证配置
-	GetValidationConfig() *artifact.ArtifactValidationConfig             // 获取验证配置
+	SetValidationConfig(config *artifact.ArtifactValidationConfig) // 设置验证配置
+	GetValidationConfig() *artifact.ArtifactValidationConfig       // 获取验证配置
+	GetValidationResult() *artifact.ValidationResult               // 获取最近一次产物验证结果（未执行验证时为nil）
+	// 阶段选择方法
+	SetStageSelection(selection *StageSelection) error      // 设置跳过、只执行或继续的阶段
+	GetStageResults() []StageResult                         // 获取最近一次构建各阶段的执行记录
+	SetIncremental(enabled bool)                            // 设置增量构建（依赖指纹未变化时跳过 clean、get_deps、code_gen）
+	SetRetryPolicies(policies map[Stage]*RetryPolicy) error // 设置 get_deps、code_gen、build 阶段命令的重试策略
+	SetTimeouts(timeouts *Timeouts) error                   // 设置构建和各阶段的超时时间
+	// 产物收集方法
+	SetOutput(output *OutputConfig) error // 设置产物收集的输出目录和命名模板（nil 表示不收集）
+	GetOutputFiles() []string             // 获取最近一次构建收集到输出目录的文件和目录
+	// 产物发布方法
+	SetPublishers(publishers []publish.Publisher) // 设置产物发布后端（构建成功且产物验证通过后依次发布）
+	GetPublishedURLs() []string                   // 获取最近一次构建发布的产物地址
+	// 事件方法
+	SetEventHandler(handler events.Handler) // 设置构建事件处理函数（阶段、钩子、命令、验证进度）
 }
 
 // CommandRunner 命令运行器接口
@@ -4,11 +4,9 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/signal"
 	"os/user"
 	"path/filepath"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/mimicode/flutterbuilder/pkg/executor"
@@ -27,15 +25,22 @@ type CertificateManagerImpl struct {
 	installedPPPath   string
 	tempPlistPath     string
 	cleanupRegistered bool
+	logger            *logger.Logger
 }
 
 // NewCertificateManager 创建新的证书管理器
 func NewCertificateManager(iosConfig *types.IOSConfig, projectRoot string) types.CertificateManager {
+	return NewCertificateManagerWithLogger(iosConfig, projectRoot, logger.Default())
+}
+
+// NewCertificateManagerWithLogger 创建使用指定日志实例的证书管理器
+func NewCertificateManagerWithLogger(iosConfig *types.IOSConfig, projectRoot string, log *logger.Logger) types.CertificateManager {
 	if iosConfig == nil {
 		return &CertificateManagerImpl{
 			projectRoot:     projectRoot,
-			executor:        executor.NewCommandExecutor(),
-			cleanupRegistry: NewCleanupRegistry(),
+			executor:        executor.NewCommandExecutorWithLogger(log),
+			cleanupRegistry: NewCleanupRegistryWithLogger(log),
+			logger:          log,
 		}
 	}
 
@@ -44,9 +49,10 @@ func NewCertificateManager(iosConfig *types.IOSConfig, projectRoot string) types
 	return &CertificateManagerImpl{
 		iosConfig:        iosConfig,
 		projectRoot:      projectRoot,
-		executor:         executor.NewCommandExecutor(),
+		executor:         executor.NewCommandExecutorWithLogger(log),
 		uniqueIdentifier: generator.Generate(),
-		cleanupRegistry:  NewCleanupRegistry(),
+		cleanupRegistry:  NewCleanupRegistryWithLogger(log),
+		logger:           log,
 	}
 }
 
@@ -56,16 +62,13 @@ func (c *CertificateManagerImpl) SetupCertificates() error {
 		return nil
 	}
 
-	logger.Info("设置iOS证书和描述文件 [标识符: %s]", c.uniqueIdentifier)
+	c.logger.Info("设置iOS证书和描述文件 [标识符: %s]", c.uniqueIdentifier)
 
 	// 注册清理资源
 	if err := c.registerCleanupResources(); err != nil {
 		return fmt.Errorf("注册清理资源失败: %w", err)
 	}
 
-	// 设置信号处理器确保异常情况下的清理
-	c.setupSignalHandler()
-
 	// 设置P12证书
 	if c.iosConfig.P12Cert != "" && c.iosConfig.CertPassword != "" {
 		c.ForceCleanupAll() // 先清理历史残留
@@ -89,11 +92,11 @@ func (c *CertificateManagerImpl) SetupCertificates() error {
 // CleanupCertificates 清理iOS证书配置
 func (c *CertificateManagerImpl) CleanupCertificates() error {
 	startTime := time.Now()
-	logger.Info("开始清理证书资源 [标识符: %s]", c.uniqueIdentifier)
+	c.logger.Info("开始清理证书资源 [标识符: %s]", c.uniqueIdentifier)
 
 	defer func() {
 		duration := time.Since(startTime)
-		logger.Info("证书清理完成，耗时: %v [标识符: %s]", duration, c.uniqueIdentifier)
+		c.logger.Info("证书清理完成，耗时: %v [标识符: %s]", duration, c.uniqueIdentifier)
 	}()
 
 	return c.ForceCleanupAll()
@@ -143,7 +146,7 @@ func (c *CertificateManagerImpl) GetUniqueIdentifier() string {
 
 // ForceCleanupAll 强制清理所有资源
 func (c *CertificateManagerImpl) ForceCleanupAll() error {
-	logger.Info("强制清理所有资源 [标识符: %s]", c.uniqueIdentifier)
+	c.logger.Info("强制清理所有资源 [标识符: %s]", c.uniqueIdentifier)
 
 	var errors []error
 
@@ -177,7 +180,7 @@ func (c *CertificateManagerImpl) ForceCleanupAll() error {
 		return fmt.Errorf("清理过程中发生错误: %v", errors)
 	}
 
-	logger.Success("资源清理完成 [标识符: %s]", c.uniqueIdentifier)
+	c.logger.Success("资源清理完成 [标识符: %s]", c.uniqueIdentifier)
 	return nil
 }
 
@@ -193,7 +196,7 @@ func (c *CertificateManagerImpl) setupTemporaryKeychain() error {
 	c.tempKeychainPath = filepath.Join(currentUser.HomeDir, "Library", "Keychains", keychainName)
 	keychainPassword := c.iosConfig.CertPassword
 
-	logger.Info("创建临时钥匙串: %s", keychainName)
+	c.logger.Info("创建临时钥匙串: %s", keychainName)
 
 	// 创建钥匙串
 	createCmd := []string{"security", "create-keychain", "-p", keychainPassword, c.tempKeychainPath}
@@ -217,7 +220,7 @@ func (c *CertificateManagerImpl) setupTemporaryKeychain() error {
 	// 解析当前钥匙串列表
 	currentKeychains := parseKeychainList(output)
 	if len(currentKeychains) > 0 {
-		logger.Info("当前钥匙串列表:\n%s", strings.Join(currentKeychains, "\n"))
+		c.logger.Info("当前钥匙串列表:\n%s", strings.Join(currentKeychains, "\n"))
 	}
 
 	// 添加临时钥匙串到搜索列表
@@ -230,7 +233,7 @@ func (c *CertificateManagerImpl) setupTemporaryKeychain() error {
 	}
 
 	// 导入P12证书
-	logger.Info("导入P12证书到临时钥匙串...")
+	c.logger.Info("导入P12证书到临时钥匙串...")
 	importCmd := []string{"security", "import", c.iosConfig.P12Cert, "-k", c.tempKeychainPath, "-P", c.iosConfig.CertPassword, "-T", "/usr/bin/codesign"}
 	if err := c.executor.RunCommand(importCmd, c.projectRoot); err != nil {
 		return fmt.Errorf("导入P12证书失败: %w", err)
@@ -242,7 +245,7 @@ func (c *CertificateManagerImpl) setupTemporaryKeychain() error {
 		return fmt.Errorf("设置证书访问权限失败: %w", err)
 	}
 
-	logger.Success("P12证书导入成功")
+	c.logger.Success("P12证书导入成功")
 	return nil
 }
 
@@ -265,23 +268,10 @@ func (c *CertificateManagerImpl) installProvisioningProfile() error {
 		return fmt.Errorf("复制描述文件失败: %w", err)
 	}
 
-	logger.Success("描述文件安装成功: %s", targetFileName)
+	c.logger.Success("描述文件安装成功: %s", targetFileName)
 	return nil
 }
 
-// setupSignalHandler 设置信号处理器
-func (c *CertificateManagerImpl) setupSignalHandler() {
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-	go func() {
-		<-sigChan
-		logger.Warning("接收到终止信号，正在清理资源...")
-		c.ForceCleanupAll()
-		os.Exit(1)
-	}()
-}
-
 // registerCleanupResources 注册清理资源
 func (c *CertificateManagerImpl) registerCleanupResources() error {
 	if c.cleanupRegistered {
@@ -348,14 +338,14 @@ func (c *CertificateManagerImpl) cleanupKeychain() error {
 	// 使用 security 命令删除钥匙串
 	cleanupCmd := []string{"security", "delete-keychain", c.tempKeychainPath}
 	if err := c.executor.RunCommand(cleanupCmd, c.projectRoot); err != nil {
-		logger.Warning("删除钥匙串失败: %s, 错误: %v", c.tempKeychainPath, err)
+		c.logger.Warning("删除钥匙串失败: %s, 错误: %v", c.tempKeychainPath, err)
 		// 尝试直接删除文件
 		if removeErr := os.Remove(c.tempKeychainPath); removeErr != nil {
 			return fmt.Errorf("删除钥匙串文件失败: %w", removeErr)
 		}
 	}
 
-	logger.Info("已删除钥匙串: %s", c.tempKeychainPath)
+	c.logger.Info("已删除钥匙串: %s", c.tempKeychainPath)
 	c.tempKeychainPath = ""
 	return nil
 }
@@ -372,7 +362,7 @@ func (c *CertificateManagerImpl) cleanupProvisioningProfile() error {
 		return fmt.Errorf("删除描述文件失败: %w", err)
 	}
 
-	logger.Info("已删除描述文件: %s", c.installedPPPath)
+	c.logger.Info("已删除描述文件: %s", c.installedPPPath)
 	c.installedPPPath = ""
 	return nil
 }
@@ -22,12 +22,19 @@ type CleanupRegistry interface {
 type CleanupRegistryImpl struct {
 	mu        sync.RWMutex
 	resources map[string][]types.CleanupResource
+	logger    *logger.Logger
 }
 
 // NewCleanupRegistry 创建清理注册表
 func NewCleanupRegistry() CleanupRegistry {
+	return NewCleanupRegistryWithLogger(logger.Default())
+}
+
+// NewCleanupRegistryWithLogger 创建使用指定日志实例的清理注册表
+func NewCleanupRegistryWithLogger(log *logger.Logger) CleanupRegistry {
 	return &CleanupRegistryImpl{
 		resources: make(map[string][]types.CleanupResource),
+		logger:    log,
 	}
 }
 
@@ -37,7 +44,7 @@ func (cr *CleanupRegistryImpl) Register(identifier string, resources []types.Cle
 	defer cr.mu.Unlock()
 	
 	cr.resources[identifier] = resources
-	logger.Info("已注册清理资源 [标识符: %s, 资源数量: %d]", identifier, len(resources))
+	cr.logger.Info("已注册清理资源 [标识符: %s, 资源数量: %d]", identifier, len(resources))
 	return nil
 }
 
@@ -65,7 +72,7 @@ func (cr *CleanupRegistryImpl) Cleanup(identifier string) error {
 		return fmt.Errorf("清理过程中发生错误: %v", errors)
 	}
 	
-	logger.Success("资源清理完成 [标识符: %s]", identifier)
+	cr.logger.Success("资源清理完成 [标识符: %s]", identifier)
 	return nil
 }
 
@@ -124,14 +131,14 @@ func (cr *CleanupRegistryImpl) cleanupKeychain(keychainPath string) error {
 	// 使用 security 命令删除钥匙串
 	cmd := exec.Command("security", "delete-keychain", keychainPath)
 	if err := cmd.Run(); err != nil {
-		logger.Warning("删除钥匙串失败: %s, 错误: %v", keychainPath, err)
+		cr.logger.Warning("删除钥匙串失败: %s, 错误: %v", keychainPath, err)
 		// 尝试直接删除文件
 		if removeErr := os.Remove(keychainPath); removeErr != nil {
 			return fmt.Errorf("删除钥匙串文件失败: %w", removeErr)
 		}
 	}
 	
-	logger.Info("已删除钥匙串: %s", keychainPath)
+	cr.logger.Info("已删除钥匙串: %s", keychainPath)
 	return nil
 }
 
@@ -147,7 +154,7 @@ func (cr *CleanupRegistryImpl) cleanupProvisioningProfile(profilePath string) er
 		return fmt.Errorf("删除描述文件失败: %w", err)
 	}
 	
-	logger.Info("已删除描述文件: %s", profilePath)
+	cr.logger.Info("已删除描述文件: %s", profilePath)
 	return nil
 }
 
@@ -163,6 +170,6 @@ func (cr *CleanupRegistryImpl) cleanupFile(filePath string) error {
 		return fmt.Errorf("删除文件失败: %w", err)
 	}
 	
-	logger.Info("已删除文件: %s", filePath)
+	cr.logger.Info("已删除文件: %s", filePath)
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,730 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+	"github.com/mimicode/flutterbuilder/pkg/builder"
+	"github.com/mimicode/flutterbuilder/pkg/hooks"
+	"github.com/mimicode/flutterbuilder/pkg/publish"
+	"github.com/mimicode/flutterbuilder/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// platforms 支持的平台（同时也是 platforms 覆盖段的合法键）
+var platforms = []string{"apk", "aab", "ios", "web", "linux"}
+
+// File 构建配置文件（YAML 或 JSON）
+//
+// 顶层字段为所有平台共用的配置，platforms 下按平台名覆盖，
+// 所有字符串值支持 ${VAR}、${VAR:-默认值}、${VAR:?错误信息} 环境变量展开，$$ 表示字面量 $。
+type File struct {
+	Platform   string `json:"platform,omitempty"`    // 默认构建平台（build 命令使用）
+	SourcePath string `json:"source_path,omitempty"` // 项目路径，相对路径基于配置文件所在目录
+	Settings
+	Platforms map[string]*Settings `json:"platforms,omitempty"` // 按平台覆盖的配置
+	Matrix    []*MatrixEntry       `json:"matrix,omitempty"`    // 构建矩阵（matrix 命令使用）
+
+	dir string // 配置文件所在目录
+}
+
+// MatrixEntry 构建矩阵中的一项，在对应平台的设置之上叠加本项设置
+type MatrixEntry struct {
+	Name     string `json:"name,omitempty"` // 名称（可选，作为构建ID）
+	Platform string `json:"platform"`       // 构建平台
+	Settings
+}
+
+// Settings 构建设置，可出现在顶层或 platforms 覆盖段中
+type Settings struct {
+	Flavor        string                 `json:"flavor,omitempty"`         // 产品风味
+	Target        string                 `json:"target,omitempty"`         // 入口文件
+	Mode          string                 `json:"mode,omitempty"`           // 构建模式 debug/profile/release
+	SplitPerABI   *bool                  `json:"split_per_abi,omitempty"`  // 按ABI拆分APK
+	WebRenderer   string                 `json:"web_renderer,omitempty"`   // Web渲染器
+	BaseHref      string                 `json:"base_href,omitempty"`      // Web部署子路径
+	DartDefines   DartDefines            `json:"dart_defines,omitempty"`   // --dart-define 键值
+	CustomArgs    map[string]interface{} `json:"custom_args,omitempty"`    // 其他自定义构建参数
+	Hooks         map[string][]*Hook     `json:"hooks,omitempty"`          // 钩子，键为钩子类型（如 pre_build）
+	Validation    *Validation            `json:"validation,omitempty"`     // 产物验证设置
+	IOS           *IOS                   `json:"ios,omitempty"`            // iOS签名配置
+	Retry         map[string]*Retry      `json:"retry,omitempty"`          // 重试策略，键为阶段名（get_deps、code_gen、build）
+	Timeout       Duration               `json:"timeout,omitempty"`        // 整个构建的超时时间
+	StageTimeouts map[string]Duration    `json:"stage_timeouts,omitempty"` // 各阶段的超时时间，键为阶段名
+	Output        *Output                `json:"output,omitempty"`         // 产物收集设置
+	Publish       *Publish               `json:"publish,omitempty"`        // 产物发布设置
+
+	dir string // 配置文件所在目录，用于解析iOS证书路径
+}
+
+// Hook 钩子配置，timeout 支持 "30s"、"2m" 形式或秒数
+type Hook struct {
+	ScriptPath      string            `json:"script_path"`
+	Args            []string          `json:"args,omitempty"`
+	Timeout         Duration          `json:"timeout,omitempty"`
+	ContinueOnError bool              `json:"continue_on_error,omitempty"`
+	WorkingDir      string            `json:"working_dir,omitempty"`
+	Environment     map[string]string `json:"environment,omitempty"`
+}
+
+// Validation 产物验证设置，未设置的字段使用默认值
+type Validation struct {
+	Enabled        *bool               `json:"enabled,omitempty"`         // 是否启用产物验证
+	IntegrityCheck *bool               `json:"integrity_check,omitempty"` // 是否启用完整性检查
+	MinSize        int64               `json:"min_size,omitempty"`        // 最小文件大小（字节）
+	MaxSize        int64               `json:"max_size,omitempty"`        // 最大文件大小（字节）
+	SHA512         *bool               `json:"sha512,omitempty"`          // 是否同时计算SHA-512并写出 SHA512SUMS
+	Manifest       *ManifestValidation `json:"manifest,omitempty"`        // APK清单的预期值，在覆盖段中整体替换
+}
+
+// ManifestValidation APK清单的预期值，未设置的字段不检查；设置了本段但未设置版本时使用 --build-number/--build-name
+type ManifestValidation struct {
+	Package     string `json:"package,omitempty"`      // 包名（applicationId）
+	VersionCode int64  `json:"version_code,omitempty"` // 版本号
+	VersionName string `json:"version_name,omitempty"` // 版本名称
+	MinSdk      int    `json:"min_sdk,omitempty"`      // 最低 SDK 版本
+	TargetSdk   int    `json:"target_sdk,omitempty"`   // 目标 SDK 版本
+	Debuggable  *bool  `json:"debuggable,omitempty"`   // 是否可调试
+}
+
+// Output 产物收集设置，dir 的相对路径基于配置文件所在目录，dir 和 name 中可使用命名模板占位符
+type Output struct {
+	Dir  string `json:"dir,omitempty"`  // 输出目录
+	Name string `json:"name,omitempty"` // 命名模板，如 {app}-{version}+{build}-{flavor}-{platform}.{ext}
+}
+
+// Publish 产物发布设置，每个发布后端在覆盖段中整体替换
+type Publish struct {
+	Filesystem *FilesystemPublish `json:"filesystem,omitempty"` // 发布到本地或网络目录
+	S3         *S3Publish         `json:"s3,omitempty"`         // 发布到 S3 兼容的对象存储
+}
+
+// FilesystemPublish 目录发布设置，dir 的相对路径基于配置文件所在目录
+type FilesystemPublish struct {
+	Dir     string `json:"dir"`                // 发布目录
+	BaseURL string `json:"base_url,omitempty"` // 发布目录对外的访问地址
+}
+
+// S3Publish 对象存储发布设置，未设置 access_key 时使用环境变量 AWS_ACCESS_KEY_ID、AWS_SECRET_ACCESS_KEY
+type S3Publish struct {
+	Endpoint         string `json:"endpoint,omitempty"`           // 服务地址，如 https://minio.example.com:9000
+	Region           string `json:"region,omitempty"`             // 区域，默认 us-east-1
+	Bucket           string `json:"bucket"`                       // 存储桶
+	Prefix           string `json:"prefix,omitempty"`             // 对象路径前缀
+	AccessKey        string `json:"access_key,omitempty"`         // 访问凭证
+	SecretKey        string `json:"secret_key,omitempty"`         // 访问凭证
+	SessionToken     string `json:"session_token,omitempty"`      // 临时凭证的会话令牌
+	VirtualHostStyle bool   `json:"virtual_host_style,omitempty"` // 使用 bucket.endpoint 形式的地址
+	PublicURL        string `json:"public_url,omitempty"`         // 对外的访问地址前缀（如 CDN 地址）
+}
+
+// Retry 阶段命令的重试策略，backoff、max_backoff 支持 "10s" 形式或秒数
+type Retry struct {
+	Attempts   int      `json:"attempts"`              // 最大执行次数（包括首次）
+	Backoff    Duration `json:"backoff,omitempty"`     // 首次重试前的等待时间，之后每次翻倍
+	MaxBackoff Duration `json:"max_backoff,omitempty"` // 等待时间上限
+	RetryOn    []string `json:"retry_on,omitempty"`    // 仅当输出匹配其中任一正则表达式时重试
+}
+
+// IOS iOS签名配置，证书和描述文件的相对路径基于配置文件所在目录
+type IOS struct {
+	P12Cert             string `json:"p12_cert,omitempty"`
+	CertPassword        string `json:"cert_password,omitempty"`
+	ProvisioningProfile string `json:"provisioning_profile,omitempty"`
+	TeamID              string `json:"team_id,omitempty"`
+	BundleID            string `json:"bundle_id,omitempty"`
+}
+
+// Duration 时长，JSON/YAML 中可写为 "30s" 形式的字符串或秒数
+type Duration time.Duration
+
+// UnmarshalJSON 解析时长
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case float64:
+		*d = Duration(time.Duration(v * float64(time.Second)))
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("无效的时长: %s", v)
+		}
+		*d = Duration(parsed)
+	default:
+		return fmt.Errorf("无效的时长: %s", string(data))
+	}
+	return nil
+}
+
+// DartDefines --dart-define 列表，可写为 "KEY=VALUE" 列表或键值映射
+type DartDefines []string
+
+// UnmarshalJSON 解析列表或映射形式的 dart defines（映射按键排序）
+func (d *DartDefines) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*d = list
+		return nil
+	}
+
+	// 数字按原文保留，避免整数被格式化为 1e+07 或丢失精度
+	var values map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return fmt.Errorf("dart_defines 必须是 KEY=VALUE 列表或键值映射")
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	defines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		defines = append(defines, fmt.Sprintf("%s=%v", key, values[key]))
+	}
+	*d = defines
+	return nil
+}
+
+// Load 加载构建配置文件，.json 按JSON解析，其余按YAML解析
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	var raw interface{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		// 数字按原文保留，重新编码时不丢失大整数的精度
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err = decoder.Decode(&raw); err == nil {
+			if _, tokenErr := decoder.Token(); tokenErr != io.EOF {
+				err = fmt.Errorf("JSON 之后存在多余内容")
+			}
+		}
+	} else {
+		err = yaml.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析配置文件失败 %s: %w", path, err)
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+
+	// 展开环境变量后统一按JSON结构解码，未知字段视为错误以便发现拼写问题
+	expanded, err := expandValue(raw)
+	if err != nil {
+		return nil, fmt.Errorf("配置文件 %s: %w", path, err)
+	}
+	normalized, err := json.Marshal(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("配置文件 %s: %w", path, err)
+	}
+
+	file := &File{}
+	decoder := json.NewDecoder(bytes.NewReader(normalized))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(file); err != nil {
+		return nil, fmt.Errorf("配置文件格式错误 %s: %w", path, err)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("解析配置文件路径失败: %w", err)
+	}
+	file.dir = filepath.Dir(absPath)
+
+	if err := file.validate(); err != nil {
+		return nil, fmt.Errorf("配置文件 %s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+// validate 检查平台、构建模式和钩子类型
+func (f *File) validate() error {
+	if f.Platform != "" && !isPlatform(f.Platform) {
+		return fmt.Errorf("不支持的平台: %s", f.Platform)
+	}
+	if err := f.Settings.validate(); err != nil {
+		return err
+	}
+	for platform, settings := range f.Platforms {
+		if !isPlatform(platform) {
+			return fmt.Errorf("platforms 中包含不支持的平台: %s", platform)
+		}
+		if settings == nil {
+			continue
+		}
+		if err := settings.validate(); err != nil {
+			return fmt.Errorf("platforms.%s: %w", platform, err)
+		}
+		if err := settings.validatePlatform(platform); err != nil {
+			return fmt.Errorf("platforms.%s: %w", platform, err)
+		}
+	}
+	for i, entry := range f.Matrix {
+		if entry == nil || !isPlatform(entry.Platform) {
+			return fmt.Errorf("matrix 第 %d 项的平台无效（可选 %s）", i+1, strings.Join(platforms, "、"))
+		}
+		if err := entry.Settings.validate(); err != nil {
+			return fmt.Errorf("matrix 第 %d 项: %w", i+1, err)
+		}
+		if err := entry.Settings.validatePlatform(entry.Platform); err != nil {
+			return fmt.Errorf("matrix 第 %d 项: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// validate 检查单个设置段
+func (s *Settings) validate() error {
+	switch s.Mode {
+	case "", "debug", "profile", "release":
+	default:
+		return fmt.Errorf("无效的构建模式: %s（可选 debug、profile、release）", s.Mode)
+	}
+	for hookType, hookList := range s.Hooks {
+		if !hooks.HookType(hookType).IsValid() {
+			return fmt.Errorf("未知的钩子类型: %s", hookType)
+		}
+		for _, hook := range hookList {
+			if hook == nil || hook.ScriptPath == "" {
+				return fmt.Errorf("钩子 %s 缺少 script_path", hookType)
+			}
+		}
+	}
+	// 阶段名和超时时间按构建器的规则检查
+	if err := s.Timeouts().Validate(); err != nil {
+		return err
+	}
+	if s.Publish != nil {
+		if s.Publish.Filesystem != nil && s.Publish.Filesystem.Dir == "" {
+			return fmt.Errorf("publish.filesystem 缺少 dir")
+		}
+		if s.Publish.S3 != nil && s.Publish.S3.Bucket == "" {
+			return fmt.Errorf("publish.s3 缺少 bucket")
+		}
+	}
+	// 阶段、次数和重试条件按构建器的规则检查
+	if err := builder.ValidateRetryPolicies(s.RetryPolicies()); err != nil {
+		return fmt.Errorf("retry: %w", err)
+	}
+	return nil
+}
+
+// validatePlatform 检查只适用于部分平台的设置
+func (s *Settings) validatePlatform(platform string) error {
+	if s.Validation != nil && s.Validation.Manifest != nil && platform != "apk" {
+		return fmt.Errorf("validation.manifest 仅支持 apk 平台（%s 构建不会检查清单）", platform)
+	}
+	return nil
+}
+
+// ResolvedSourcePath 返回配置的项目路径（相对路径基于配置文件目录），未配置时返回空
+func (f *File) ResolvedSourcePath() string {
+	return resolvePath(f.dir, f.SourcePath)
+}
+
+// Resolve 返回指定平台的最终设置：顶层设置叠加 platforms.<platform> 覆盖段
+func (f *File) Resolve(platform string) (*Settings, error) {
+	if !isPlatform(platform) {
+		return nil, fmt.Errorf("不支持的平台: %s", platform)
+	}
+
+	resolved := f.Settings.clone()
+	if override := f.Platforms[platform]; override != nil {
+		resolved.merge(override)
+	}
+	resolved.dir = f.dir
+	// 顶层的 validation.manifest 对所有平台生效，只能用于 apk 构建
+	if err := resolved.validatePlatform(platform); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// ResolveEntry 返回构建矩阵中一项的最终设置：平台设置叠加本项设置
+func (f *File) ResolveEntry(entry *MatrixEntry) (*Settings, error) {
+	resolved, err := f.Resolve(entry.Platform)
+	if err != nil {
+		return nil, err
+	}
+	resolved.merge(&entry.Settings)
+	return resolved, nil
+}
+
+// clone 复制设置，避免合并时修改原始配置
+func (s *Settings) clone() *Settings {
+	c := *s
+	c.DartDefines = append(DartDefines(nil), s.DartDefines...)
+	c.CustomArgs = make(map[string]interface{}, len(s.CustomArgs))
+	for key, value := range s.CustomArgs {
+		c.CustomArgs[key] = value
+	}
+	c.Hooks = make(map[string][]*Hook, len(s.Hooks))
+	for hookType, hookList := range s.Hooks {
+		c.Hooks[hookType] = hookList
+	}
+	if s.Validation != nil {
+		validation := *s.Validation
+		c.Validation = &validation
+	}
+	if s.IOS != nil {
+		ios := *s.IOS
+		c.IOS = &ios
+	}
+	if s.Output != nil {
+		output := *s.Output
+		c.Output = &output
+	}
+	if s.Publish != nil {
+		publishSettings := *s.Publish
+		c.Publish = &publishSettings
+	}
+	c.Retry = make(map[string]*Retry, len(s.Retry))
+	for stage, retry := range s.Retry {
+		c.Retry[stage] = retry
+	}
+	c.StageTimeouts = make(map[string]Duration, len(s.StageTimeouts))
+	for stage, timeout := range s.StageTimeouts {
+		c.StageTimeouts[stage] = timeout
+	}
+	return &c
+}
+
+// merge 用覆盖段中设置的字段覆盖当前设置
+// 标量非空即覆盖；custom_args、stage_timeouts 按键覆盖；dart_defines 按 KEY 覆盖；hooks 按钩子类型、retry 按阶段整体替换
+func (s *Settings) merge(o *Settings) {
+	overrideString(&s.Flavor, o.Flavor)
+	overrideString(&s.Target, o.Target)
+	overrideString(&s.Mode, o.Mode)
+	overrideString(&s.WebRenderer, o.WebRenderer)
+	overrideString(&s.BaseHref, o.BaseHref)
+	if o.SplitPerABI != nil {
+		s.SplitPerABI = o.SplitPerABI
+	}
+
+	for _, define := range o.DartDefines {
+		s.DartDefines = s.DartDefines.Set(define)
+	}
+	for key, value := range o.CustomArgs {
+		s.CustomArgs[key] = value
+	}
+	for hookType, hookList := range o.Hooks {
+		s.Hooks[hookType] = hookList
+	}
+	for stage, retry := range o.Retry {
+		s.Retry[stage] = retry
+	}
+	if o.Timeout != 0 {
+		s.Timeout = o.Timeout
+	}
+	for stage, timeout := range o.StageTimeouts {
+		s.StageTimeouts[stage] = timeout
+	}
+
+	if o.Validation != nil {
+		if s.Validation == nil {
+			s.Validation = &Validation{}
+		}
+		if o.Validation.Enabled != nil {
+			s.Validation.Enabled = o.Validation.Enabled
+		}
+		if o.Validation.IntegrityCheck != nil {
+			s.Validation.IntegrityCheck = o.Validation.IntegrityCheck
+		}
+		if o.Validation.MinSize != 0 {
+			s.Validation.MinSize = o.Validation.MinSize
+		}
+		if o.Validation.MaxSize != 0 {
+			s.Validation.MaxSize = o.Validation.MaxSize
+		}
+		if o.Validation.SHA512 != nil {
+			s.Validation.SHA512 = o.Validation.SHA512
+		}
+		if o.Validation.Manifest != nil {
+			s.Validation.Manifest = o.Validation.Manifest
+		}
+	}
+
+	if o.IOS != nil {
+		if s.IOS == nil {
+			s.IOS = &IOS{}
+		}
+		overrideString(&s.IOS.P12Cert, o.IOS.P12Cert)
+		overrideString(&s.IOS.CertPassword, o.IOS.CertPassword)
+		overrideString(&s.IOS.ProvisioningProfile, o.IOS.ProvisioningProfile)
+		overrideString(&s.IOS.TeamID, o.IOS.TeamID)
+		overrideString(&s.IOS.BundleID, o.IOS.BundleID)
+	}
+
+	if o.Output != nil {
+		if s.Output == nil {
+			s.Output = &Output{}
+		}
+		overrideString(&s.Output.Dir, o.Output.Dir)
+		overrideString(&s.Output.Name, o.Output.Name)
+	}
+
+	if o.Publish != nil {
+		if s.Publish == nil {
+			s.Publish = &Publish{}
+		}
+		if o.Publish.Filesystem != nil {
+			s.Publish.Filesystem = o.Publish.Filesystem
+		}
+		if o.Publish.S3 != nil {
+			s.Publish.S3 = o.Publish.S3
+		}
+	}
+}
+
+// Set 设置 KEY=VALUE，已存在同名 KEY 时替换
+func (d DartDefines) Set(define string) DartDefines {
+	key := strings.SplitN(define, "=", 2)[0]
+	for i, existing := range d {
+		if strings.SplitN(existing, "=", 2)[0] == key {
+			d[i] = define
+			return d
+		}
+	}
+	return append(d, define)
+}
+
+// BuildCustomArgs 返回传递给构建器的自定义参数（SetCustomArgs 使用的键名）
+func (s *Settings) BuildCustomArgs() map[string]interface{} {
+	args := make(map[string]interface{}, len(s.CustomArgs)+8)
+	for key, value := range s.CustomArgs {
+		args[key] = normalizeArg(value)
+	}
+
+	if s.Flavor != "" {
+		args["flavor"] = s.Flavor
+	}
+	if s.Target != "" {
+		args["target"] = s.Target
+	}
+	if s.Mode != "" {
+		args["build_mode"] = s.Mode
+	}
+	if s.SplitPerABI != nil {
+		args["split_per_abi"] = *s.SplitPerABI
+	}
+	if s.WebRenderer != "" {
+		args["web_renderer"] = s.WebRenderer
+	}
+	if s.BaseHref != "" {
+		args["base_href"] = s.BaseHref
+	}
+	if len(s.DartDefines) > 0 {
+		defines, _ := args["dart_defines"].([]string)
+		args["dart_defines"] = append(defines, s.DartDefines...)
+	}
+	return args
+}
+
+// HooksConfig 返回钩子配置，未配置钩子时返回nil
+func (s *Settings) HooksConfig() *hooks.HooksConfig {
+	if len(s.Hooks) == 0 {
+		return nil
+	}
+
+	hooksConfig := &hooks.HooksConfig{Hooks: make(map[hooks.HookType][]*hooks.HookConfig)}
+	for hookType, hookList := range s.Hooks {
+		for _, hook := range hookList {
+			hooksConfig.Hooks[hooks.HookType(hookType)] = append(hooksConfig.Hooks[hooks.HookType(hookType)], &hooks.HookConfig{
+				ScriptPath:      hook.ScriptPath,
+				Args:            hook.Args,
+				Timeout:         time.Duration(hook.Timeout),
+				ContinueOnError: hook.ContinueOnError,
+				WorkingDir:      hook.WorkingDir,
+				Environment:     hook.Environment,
+			})
+		}
+	}
+	return hooksConfig
+}
+
+// ValidationConfig 返回产物验证配置，未配置时返回nil（使用默认配置）
+func (s *Settings) ValidationConfig() *artifact.ArtifactValidationConfig {
+	if s.Validation == nil {
+		return nil
+	}
+
+	config := artifact.GetDefaultValidationConfig()
+	if s.Validation.Enabled != nil {
+		config.EnableValidation = *s.Validation.Enabled
+	}
+	if s.Validation.IntegrityCheck != nil {
+		config.EnableIntegrityCheck = *s.Validation.IntegrityCheck
+	}
+	config.CustomMinSize = s.Validation.MinSize
+	config.CustomMaxSize = s.Validation.MaxSize
+	if s.Validation.SHA512 != nil {
+		config.EnableSHA512 = *s.Validation.SHA512
+	}
+	if manifest := s.Validation.Manifest; manifest != nil {
+		config.ExpectedManifest = &artifact.ManifestExpectation{
+			Package:          manifest.Package,
+			VersionCode:      manifest.VersionCode,
+			VersionName:      manifest.VersionName,
+			MinSdkVersion:    manifest.MinSdk,
+			TargetSdkVersion: manifest.TargetSdk,
+			Debuggable:       manifest.Debuggable,
+		}
+	}
+	return config
+}
+
+// IOSConfig 返回iOS签名配置，未配置时返回nil
+func (s *Settings) IOSConfig() *types.IOSConfig {
+	if s.IOS == nil {
+		return nil
+	}
+	return &types.IOSConfig{
+		P12Cert:             resolvePath(s.dir, s.IOS.P12Cert),
+		CertPassword:        s.IOS.CertPassword,
+		ProvisioningProfile: resolvePath(s.dir, s.IOS.ProvisioningProfile),
+		TeamID:              s.IOS.TeamID,
+		BundleID:            s.IOS.BundleID,
+	}
+}
+
+// OutputDir 返回产物收集目录（相对路径基于配置文件目录），未配置时返回空
+func (s *Settings) OutputDir() string {
+	if s.Output == nil {
+		return ""
+	}
+	return resolvePath(s.dir, s.Output.Dir)
+}
+
+// OutputName 返回产物命名模板，未配置时返回空
+func (s *Settings) OutputName() string {
+	if s.Output == nil {
+		return ""
+	}
+	return s.Output.Name
+}
+
+// RetryPolicies 返回各阶段命令的重试策略（值为空的阶段对应nil），未配置时返回nil
+func (s *Settings) RetryPolicies() map[builder.Stage]*builder.RetryPolicy {
+	if len(s.Retry) == 0 {
+		return nil
+	}
+	policies := make(map[builder.Stage]*builder.RetryPolicy, len(s.Retry))
+	for stage, retry := range s.Retry {
+		if retry == nil {
+			policies[builder.Stage(stage)] = nil
+			continue
+		}
+		policies[builder.Stage(stage)] = &builder.RetryPolicy{
+			Attempts:   retry.Attempts,
+			Backoff:    time.Duration(retry.Backoff),
+			MaxBackoff: time.Duration(retry.MaxBackoff),
+			RetryOn:    retry.RetryOn,
+		}
+	}
+	return policies
+}
+
+// Timeouts 返回构建和各阶段的超时时间，未配置时返回nil
+func (s *Settings) Timeouts() *builder.Timeouts {
+	if s.Timeout == 0 && len(s.StageTimeouts) == 0 {
+		return nil
+	}
+	timeouts := &builder.Timeouts{Build: time.Duration(s.Timeout)}
+	for stage, timeout := range s.StageTimeouts {
+		if timeouts.Stages == nil {
+			timeouts.Stages = make(map[builder.Stage]time.Duration)
+		}
+		timeouts.Stages[builder.Stage(stage)] = time.Duration(timeout)
+	}
+	return timeouts
+}
+
+// Publishers 返回配置的发布后端（目录在前、对象存储在后），未配置时返回nil
+func (s *Settings) Publishers() []publish.Publisher {
+	if s.Publish == nil {
+		return nil
+	}
+
+	var publishers []publish.Publisher
+	if fs := s.Publish.Filesystem; fs != nil {
+		publishers = append(publishers, &publish.FilesystemPublisher{
+			Dir:     resolvePath(s.dir, fs.Dir),
+			BaseURL: fs.BaseURL,
+		})
+	}
+	if s3 := s.Publish.S3; s3 != nil {
+		publishers = append(publishers, &publish.S3Publisher{
+			Endpoint:         s3.Endpoint,
+			Region:           s3.Region,
+			Bucket:           s3.Bucket,
+			Prefix:           s3.Prefix,
+			AccessKey:        s3.AccessKey,
+			SecretKey:        s3.SecretKey,
+			SessionToken:     s3.SessionToken,
+			VirtualHostStyle: s3.VirtualHostStyle,
+			PublicURL:        s3.PublicURL,
+		})
+	}
+	return publishers
+}
+
+// normalizeArg 将JSON解码得到的字符串数组转换为 []string，与构建器的类型化读取保持一致
+func normalizeArg(value interface{}) interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		str, ok := item.(string)
+		if !ok {
+			return value
+		}
+		strs = append(strs, str)
+	}
+	return strs
+}
+
+// resolvePath 将相对路径解析为基于 dir 的路径，空路径保持为空
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) || dir == "" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// overrideString 覆盖值非空时替换
+func overrideString(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+// isPlatform 判断是否为支持的平台
+func isPlatform(platform string) bool {
+	for _, p := range platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,525 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/builder"
+	"github.com/mimicode/flutterbuilder/pkg/hooks"
+	"github.com/mimicode/flutterbuilder/pkg/publish"
+)
+
+const sampleYAML = `
+platform: apk
+source_path: app
+flavor: dev
+target: lib/main_dev.dart
+dart_defines:
+  API_URL: ${TEST_API_URL}
+  CHANNEL: ${TEST_CHANNEL:-internal}
+custom_args:
+  flutter_build_args: ["--build-number", "${TEST_BUILD_NUMBER}"]
+  disable_default_args: false
+hooks:
+  pre_build:
+    - script_path: scripts/pre_build.dart
+      timeout: 2m
+validation:
+  integrity_check: false
+  max_size: 104857600
+ios:
+  p12_cert: certs/dist.p12
+  cert_password: ${TEST_CERT_PASSWORD}
+  team_id: TEAM123456
+platforms:
+  aab:
+    flavor: prod
+    mode: profile
+    dart_defines: ["CHANNEL=play"]
+  ios:
+    ios:
+      bundle_id: com.example.app
+`
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadYAML(t *testing.T) {
+	t.Setenv("TEST_API_URL", "https://api.example.com")
+	t.Setenv("TEST_BUILD_NUMBER", "42")
+	t.Setenv("TEST_CERT_PASSWORD", "secret")
+
+	path := writeFile(t, "flutterbuilder.yaml", sampleYAML)
+	file, err := Load(path)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	if file.Platform != "apk" {
+		t.Errorf("platform 期望 apk，实际 %s", file.Platform)
+	}
+	if want := filepath.Join(filepath.Dir(path), "app"); file.ResolvedSourcePath() != want {
+		t.Errorf("source_path 期望 %s，实际 %s", want, file.ResolvedSourcePath())
+	}
+
+	apk, err := file.Resolve("apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := apk.BuildCustomArgs()
+	if args["flavor"] != "dev" || args["target"] != "lib/main_dev.dart" {
+		t.Errorf("flavor/target 错误: %v", args)
+	}
+	wantDefines := []string{"API_URL=https://api.example.com", "CHANNEL=internal"}
+	if !reflect.DeepEqual(args["dart_defines"], wantDefines) {
+		t.Errorf("dart_defines 期望 %v，实际 %v", wantDefines, args["dart_defines"])
+	}
+	if !reflect.DeepEqual(args["flutter_build_args"], []string{"--build-number", "42"}) {
+		t.Errorf("flutter_build_args 应转换为 []string: %#v", args["flutter_build_args"])
+	}
+	if _, ok := args["build_mode"]; ok {
+		t.Error("未设置 mode 时不应传递 build_mode")
+	}
+
+	hooksConfig := apk.HooksConfig()
+	if hooksConfig == nil || len(hooksConfig.Hooks[hooks.HookPreBuild]) != 1 {
+		t.Fatalf("钩子配置错误: %+v", hooksConfig)
+	}
+	if hook := hooksConfig.Hooks[hooks.HookPreBuild][0]; hook.Timeout != 2*time.Minute || hook.ScriptPath != "scripts/pre_build.dart" {
+		t.Errorf("钩子字段错误: %+v", hook)
+	}
+
+	validation := apk.ValidationConfig()
+	if !validation.EnableValidation || validation.EnableIntegrityCheck || validation.CustomMaxSize != 104857600 {
+		t.Errorf("验证配置错误: %+v", validation)
+	}
+}
+
+func TestResolvePlatformOverride(t *testing.T) {
+	t.Setenv("TEST_CERT_PASSWORD", "secret")
+	path := writeFile(t, "flutterbuilder.yaml", sampleYAML)
+	file, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aab, err := file.Resolve("aab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := aab.BuildCustomArgs()
+	if args["flavor"] != "prod" || args["build_mode"] != "profile" {
+		t.Errorf("覆盖段未生效: %v", args)
+	}
+	// 同名 KEY 被覆盖，其余保留
+	wantDefines := []string{"API_URL=", "CHANNEL=play"}
+	if !reflect.DeepEqual(args["dart_defines"], wantDefines) {
+		t.Errorf("dart_defines 期望 %v，实际 %v", wantDefines, args["dart_defines"])
+	}
+
+	ios, err := file.Resolve("ios")
+	if err != nil {
+		t.Fatal(err)
+	}
+	iosConfig := ios.IOSConfig()
+	if iosConfig.TeamID != "TEAM123456" || iosConfig.BundleID != "com.example.app" || iosConfig.CertPassword != "secret" {
+		t.Errorf("iOS配置合并错误: %+v", iosConfig)
+	}
+	if want := filepath.Join(filepath.Dir(path), "certs", "dist.p12"); iosConfig.P12Cert != want {
+		t.Errorf("证书路径期望 %s，实际 %s", want, iosConfig.P12Cert)
+	}
+
+	// 覆盖段不影响顶层配置
+	apk, _ := file.Resolve("apk")
+	if apk.BuildCustomArgs()["flavor"] != "dev" {
+		t.Error("覆盖段修改了顶层配置")
+	}
+}
+
+func TestResolveRetry(t *testing.T) {
+	path := writeFile(t, "flutterbuilder.yaml", `
+retry:
+  get_deps:
+    attempts: 3
+    backoff: 5s
+    retry_on: ["Connection (reset|refused)"]
+platforms:
+  aab:
+    retry:
+      build:
+        attempts: 2
+        max_backoff: 1m
+`)
+	file, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aab, err := file.Resolve("aab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	getDeps, build := aab.Retry["get_deps"], aab.Retry["build"]
+	if getDeps == nil || getDeps.Attempts != 3 || time.Duration(getDeps.Backoff) != 5*time.Second || len(getDeps.RetryOn) != 1 {
+		t.Errorf("get_deps 重试策略错误: %+v", getDeps)
+	}
+	if build == nil || build.Attempts != 2 || time.Duration(build.MaxBackoff) != time.Minute {
+		t.Errorf("build 重试策略错误: %+v", build)
+	}
+	if policy := aab.RetryPolicies()[builder.StageGetDeps]; policy == nil || policy.Backoff != 5*time.Second || policy.RetryOn[0] != "Connection (reset|refused)" {
+		t.Errorf("转换后的重试策略错误: %+v", policy)
+	}
+
+	apk, _ := file.Resolve("apk")
+	if _, ok := apk.Retry["build"]; ok {
+		t.Error("覆盖段修改了顶层重试策略")
+	}
+}
+
+func TestResolveTimeouts(t *testing.T) {
+	path := writeFile(t, "flutterbuilder.yaml", `
+timeout: 1h
+stage_timeouts:
+  get_deps: 10m
+  build: 30m
+platforms:
+  ios:
+    timeout: 2h
+    stage_timeouts:
+      build: 90m
+`)
+	file, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ios, err := file.Resolve("ios")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(ios.Timeout) != 2*time.Hour {
+		t.Errorf("timeout 应被覆盖: %v", time.Duration(ios.Timeout))
+	}
+	if time.Duration(ios.StageTimeouts["build"]) != 90*time.Minute || time.Duration(ios.StageTimeouts["get_deps"]) != 10*time.Minute {
+		t.Errorf("stage_timeouts 应按阶段覆盖: %v", ios.StageTimeouts)
+	}
+	if timeouts := ios.Timeouts(); timeouts.Build != 2*time.Hour || timeouts.Stages[builder.StageBuild] != 90*time.Minute {
+		t.Errorf("转换后的超时设置错误: %+v", timeouts)
+	}
+
+	apk, _ := file.Resolve("apk")
+	if time.Duration(apk.Timeout) != time.Hour || time.Duration(apk.StageTimeouts["build"]) != 30*time.Minute {
+		t.Errorf("覆盖段修改了顶层超时设置: %v %v", apk.Timeout, apk.StageTimeouts)
+	}
+}
+
+func TestResolveOutput(t *testing.T) {
+	path := writeFile(t, "flutterbuilder.yaml", `
+output:
+  dir: dist/{version}
+platforms:
+  web:
+    output:
+      name: "{app}-{version}-web"
+`)
+	file, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	web, err := file.Resolve("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if web.OutputDir() != filepath.Join(filepath.Dir(path), "dist/{version}") {
+		t.Errorf("输出目录应基于配置文件目录: %s", web.OutputDir())
+	}
+	if web.OutputName() != "{app}-{version}-web" {
+		t.Errorf("命名模板应被覆盖: %s", web.OutputName())
+	}
+
+	apk, _ := file.Resolve("apk")
+	if apk.OutputName() != "" || apk.OutputDir() == "" {
+		t.Errorf("覆盖段修改了顶层产物收集设置: %+v", apk.Output)
+	}
+	if file.Platforms["web"].Output.Dir != "" {
+		t.Error("合并不应修改覆盖段")
+	}
+}
+
+func TestResolvePublishers(t *testing.T) {
+	t.Setenv("MINIO_SECRET", "minio123")
+	path := writeFile(t, "flutterbuilder.yaml", `
+publish:
+  filesystem:
+    dir: builds
+  s3:
+    endpoint: http://minio:9000
+    bucket: app-builds
+    prefix: shop
+    access_key: minio
+    secret_key: ${MINIO_SECRET}
+platforms:
+  web:
+    publish:
+      s3:
+        bucket: web-builds
+`)
+	file, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apk, _ := file.Resolve("apk")
+	publishers := apk.Publishers()
+	if len(publishers) != 2 {
+		t.Fatalf("应有两个发布后端: %v", publishers)
+	}
+	fs, ok := publishers[0].(*publish.FilesystemPublisher)
+	if !ok || fs.Dir != filepath.Join(filepath.Dir(path), "builds") {
+		t.Errorf("发布目录应基于配置文件目录: %+v", publishers[0])
+	}
+	s3, ok := publishers[1].(*publish.S3Publisher)
+	if !ok || s3.Endpoint != "http://minio:9000" || s3.SecretKey != "minio123" || s3.Prefix != "shop" {
+		t.Errorf("对象存储设置错误: %+v", publishers[1])
+	}
+
+	// 覆盖段中的发布后端整体替换
+	web, _ := file.Resolve("web")
+	if s3 := web.Publishers()[1].(*publish.S3Publisher); s3.Bucket != "web-builds" || s3.Endpoint != "" {
+		t.Errorf("覆盖段应整体替换 publish.s3: %+v", s3)
+	}
+
+	if _, err := Load(writeFile(t, "bad.yaml", "publish:\n  s3:\n    endpoint: http://minio:9000\n")); err == nil {
+		t.Error("缺少 bucket 时应报错")
+	}
+}
+
+func TestResolveManifestValidation(t *testing.T) {
+	path := writeFile(t, "flutterbuilder.yaml", `
+validation:
+  manifest:
+    package: com.example.shop
+    target_sdk: 34
+    debuggable: false
+platforms:
+  apk:
+    validation:
+      manifest:
+        package: com.example.shop.dev
+`)
+	file, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 覆盖段中的 manifest 整体替换
+	apk, err := file.Resolve("apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := apk.ValidationConfig().ExpectedManifest; expected.Package != "com.example.shop.dev" || expected.TargetSdkVersion != 0 {
+		t.Errorf("覆盖段应整体替换 validation.manifest: %+v", expected)
+	}
+	if !apk.ValidationConfig().EnableValidation {
+		t.Error("未设置的验证开关应使用默认值")
+	}
+
+	// 顶层的 manifest 对其他平台同样生效，非 apk 平台不检查清单，应报错
+	if _, err := file.Resolve("aab"); err == nil || !strings.Contains(err.Error(), "validation.manifest") {
+		t.Errorf("非 apk 平台设置 validation.manifest 应报错: %v", err)
+	}
+
+	path = writeFile(t, "flutterbuilder.yaml", `
+validation:
+  manifest:
+    package: com.example.shop
+    target_sdk: 34
+    debuggable: false
+`)
+	if file, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+	apk, err = file.Resolve("apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := apk.ValidationConfig().ExpectedManifest
+	if expected == nil || expected.Package != "com.example.shop" || expected.TargetSdkVersion != 34 ||
+		expected.Debuggable == nil || *expected.Debuggable {
+		t.Errorf("清单预期值错误: %+v", expected)
+	}
+
+	path = writeFile(t, "flutterbuilder.yaml", `
+platforms:
+  aab:
+    validation:
+      manifest:
+        package: com.example.shop
+`)
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "platforms.aab") {
+		t.Errorf("platforms.aab 中设置 validation.manifest 应报错: %v", err)
+	}
+}
+
+func TestResolveMatrixEntry(t *testing.T) {
+	path := writeFile(t, "flutterbuilder.yaml", `
+flavor: dev
+dart_defines: ["CHANNEL=internal"]
+platforms:
+  aab:
+    mode: profile
+matrix:
+  - platform: apk
+  - name: play
+    platform: aab
+    flavor: prod
+    dart_defines: ["CHANNEL=play"]
+`)
+	file, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Matrix) != 2 || file.Matrix[1].Name != "play" {
+		t.Fatalf("matrix 解析错误: %+v", file.Matrix)
+	}
+
+	apk, err := file.ResolveEntry(file.Matrix[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args := apk.BuildCustomArgs(); args["flavor"] != "dev" {
+		t.Errorf("未设置的字段应继承顶层配置: %v", args)
+	}
+
+	aab, err := file.ResolveEntry(file.Matrix[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := aab.BuildCustomArgs()
+	if args["flavor"] != "prod" || args["build_mode"] != "profile" {
+		t.Errorf("矩阵项应叠加在平台设置之上: %v", args)
+	}
+	if !reflect.DeepEqual(args["dart_defines"], []string{"CHANNEL=play"}) {
+		t.Errorf("dart_defines 错误: %v", args["dart_defines"])
+	}
+
+	if _, err := Load(writeFile(t, "flutterbuilder.yaml", "matrix:\n  - flavor: dev\n")); err == nil || !strings.Contains(err.Error(), "matrix") {
+		t.Errorf("缺少平台的矩阵项应报错，实际 %v", err)
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	path := writeFile(t, "flutterbuilder.json", `{
+		"platform": "web",
+		"base_href": "/admin/",
+		"web_renderer": "canvaskit",
+		"hooks": {"post_build": [{"script_path": "scripts/upload.dart", "timeout": 45}]}
+	}`)
+	file, err := Load(path)
+	if err != nil {
+		t.Fatalf("加载JSON配置失败: %v", err)
+	}
+
+	web, err := file.Resolve("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := web.BuildCustomArgs()
+	if args["base_href"] != "/admin/" || args["web_renderer"] != "canvaskit" {
+		t.Errorf("Web参数错误: %v", args)
+	}
+	if hook := web.HooksConfig().Hooks[hooks.HookPostBuild][0]; hook.Timeout != 45*time.Second {
+		t.Errorf("数字形式的 timeout 应按秒解析: %v", hook.Timeout)
+	}
+	if web.ValidationConfig() != nil || web.IOSConfig() != nil {
+		t.Error("未配置验证和iOS时应返回nil")
+	}
+}
+
+func TestDartDefinesNumbers(t *testing.T) {
+	want := DartDefines{"BIG=9007199254740993", "BUILD_TS=10000000", "DEBUG=true", "RATIO=0.5"}
+	files := map[string]string{
+		"flutterbuilder.yaml": "dart_defines:\n  BUILD_TS: 10000000\n  BIG: 9007199254740993\n  RATIO: 0.5\n  DEBUG: true\n",
+		"flutterbuilder.json": `{"dart_defines": {"BUILD_TS": 10000000, "BIG": 9007199254740993, "RATIO": 0.5, "DEBUG": true}}`,
+	}
+	for name, content := range files {
+		file, err := Load(writeFile(t, name, content))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(file.DartDefines, want) {
+			t.Errorf("%s: 数字取值应按原样保留，期望 %v，实际 %v", name, want, file.DartDefines)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown field", "flavour: dev\n", "flavour"},
+		{"invalid platform", "platform: windows\n", "不支持的平台"},
+		{"invalid override platform", "platforms:\n  macos:\n    flavor: dev\n", "macos"},
+		{"invalid mode", "mode: fast\n", "无效的构建模式"},
+		{"unknown hook type", "hooks:\n  before_build:\n    - script_path: a.dart\n", "before_build"},
+		{"missing script path", "hooks:\n  pre_build:\n    - timeout: 10s\n", "script_path"},
+		{"invalid timeout", "hooks:\n  pre_build:\n    - script_path: a.dart\n      timeout: soon\n", "无效的时长"},
+		{"required env", "ios:\n  cert_password: ${TEST_UNSET_PASSWORD:?请设置证书密码}\n", "请设置证书密码"},
+		{"retry stage", "retry:\n  clean:\n    attempts: 2\n", "clean"},
+		{"retry pattern", "retry:\n  build:\n    attempts: 2\n    retry_on: [\"(\"]\n", "重试条件无效"},
+		{"timeout stage", "stage_timeouts:\n  archive: 10m\n", "archive"},
+		{"negative timeout", "timeout: -1m\n", "构建超时时间不能为负数"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeFile(t, "flutterbuilder.yaml", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("期望包含 %q 的错误，实际 %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, err := Load(writeFile(t, "flutterbuilder.json", `{"platform": "web"} }`)); err == nil {
+		t.Error("JSON 之后存在多余内容时应返回错误")
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("配置文件不存在时应返回错误")
+	}
+}
+
+func TestExpandString(t *testing.T) {
+	t.Setenv("TEST_NAME", "world")
+
+	tests := map[string]string{
+		"hello ${TEST_NAME}":      "hello world",
+		"hello $TEST_NAME":        "hello world",
+		"${TEST_EMPTY_VAR:-dflt}": "dflt",
+		"${TEST_NAME:-dflt}":      "world",
+		"price: $$5":              "price: $5",
+		"no variables":            "no variables",
+	}
+	for input, want := range tests {
+		got, err := expandString(input)
+		if err != nil {
+			t.Errorf("展开 %q 失败: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("展开 %q 期望 %q，实际 %q", input, want, got)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// expandValue 递归展开配置中所有字符串值里的环境变量
+func expandValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return expandString(v)
+	case []interface{}:
+		for i, item := range v {
+			expanded, err := expandValue(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+		return v, nil
+	case map[string]interface{}:
+		for key, item := range v {
+			expanded, err := expandValue(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = expanded
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		// 非字符串键的YAML映射统一转换为字符串键
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			expanded, err := expandValue(item)
+			if err != nil {
+				return nil, err
+			}
+			converted[fmt.Sprint(key)] = expanded
+		}
+		return converted, nil
+	default:
+		return value, nil
+	}
+}
+
+// expandString 展开 $VAR、${VAR}、${VAR:-默认值} 和 ${VAR:?错误信息}，$$ 表示字面量 $
+func expandString(s string) (string, error) {
+	var expandErr error
+	expanded := os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+
+		if key, message, ok := strings.Cut(name, ":?"); ok {
+			if value := os.Getenv(key); value != "" {
+				return value
+			}
+			if expandErr == nil {
+				if message == "" {
+					message = "未设置"
+				}
+				expandErr = fmt.Errorf("环境变量 %s: %s", key, message)
+			}
+			return ""
+		}
+
+		if key, fallback, ok := strings.Cut(name, ":-"); ok {
+			if value := os.Getenv(key); value != "" {
+				return value
+			}
+			return fallback
+		}
+
+		return os.Getenv(name)
+	})
+	return expanded, expandErr
+}
@@ -0,0 +1,197 @@
+// Package diagnosis 根据失败命令的输出识别常见的 Gradle、Xcode、CocoaPods 和 pub 错误，给出原因和修复建议
+package diagnosis
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 诊断代码
+const (
+	CodeGradleOutOfMemory        = "GRADLE_OOM"                    // Gradle 堆内存不足
+	CodeGradleMetaspace          = "GRADLE_METASPACE"              // Gradle Metaspace 不足
+	CodeKotlinVersionMismatch    = "KOTLIN_VERSION_MISMATCH"       // Kotlin 版本不兼容
+	CodeAndroidLicenses          = "ANDROID_SDK_LICENSES"          // Android SDK 许可未接受
+	CodeCocoaPodsOutdated        = "COCOAPODS_SPECS_OUTDATED"      // CocoaPods 规格仓库过期
+	CodeSigningIdentityNotFound  = "CODE_SIGNING_IDENTITY"         // 找不到签名证书
+	CodeProvisioningProfile      = "PROVISIONING_PROFILE_MISMATCH" // 描述文件不匹配
+	CodePubVersionSolvingFailure = "PUB_VERSION_SOLVING"           // pub 依赖版本求解失败
+)
+
+// excerptContext 摘录中匹配行前后各保留的行数
+const excerptContext = 2
+
+// Diagnosis 一条诊断结果
+type Diagnosis struct {
+	Code       string // 诊断代码（如 GRADLE_OOM）
+	Summary    string // 问题描述
+	Suggestion string // 修复建议
+	Excerpt    string // 输出中匹配的片段（匹配行及其前后各两行）
+}
+
+// Rule 诊断规则：输出中任一行匹配 Patterns 之一时给出诊断
+type Rule struct {
+	Code       string
+	Patterns   []*regexp.Regexp
+	Summary    string
+	Suggestion string
+}
+
+// Rules 内置诊断规则，按顺序匹配，每条规则最多产生一条诊断
+var Rules = []Rule{
+	{
+		Code: CodeGradleMetaspace,
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`OutOfMemoryError: Metaspace`),
+		},
+		Summary:    "Gradle 构建时 Metaspace 内存不足",
+		Suggestion: "在 android/gradle.properties 的 org.gradle.jvmargs 中增加 -XX:MaxMetaspaceSize=1g（或更大），并执行 ./gradlew --stop 停止旧的守护进程",
+	},
+	{
+		Code: CodeGradleOutOfMemory,
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`OutOfMemoryError: Java heap space`),
+			regexp.MustCompile(`GC overhead limit exceeded`),
+			regexp.MustCompile(`(?i)JVM heap space is exhausted`),
+		},
+		Summary:    "Gradle 构建时 Java 堆内存不足",
+		Suggestion: "在 android/gradle.properties 中增大 org.gradle.jvmargs 的 -Xmx（如 -Xmx4g），CI 机器内存较小时减少并行构建数",
+	},
+	{
+		Code: CodeKotlinVersionMismatch,
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`compiled with an incompatible version of Kotlin`),
+			regexp.MustCompile(`requires a newer version of the Kotlin Gradle plugin`),
+			regexp.MustCompile(`The binary version of its metadata is .*, expected version is`),
+		},
+		Summary:    "依赖库使用的 Kotlin 版本高于项目的 Kotlin Gradle 插件版本",
+		Suggestion: "升级 android/settings.gradle（或 android/build.gradle）中的 org.jetbrains.kotlin.android 插件版本，使其不低于错误信息中要求的版本",
+	},
+	{
+		Code: CodeAndroidLicenses,
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`You have not accepted the license agreements`),
+			regexp.MustCompile(`(?i)licen[cs]es? (for package .* )?(have )?not (been )?accepted`),
+		},
+		Summary:    "Android SDK 组件的许可协议未接受，无法自动安装所需的 SDK 包",
+		Suggestion: "执行 flutter doctor --android-licenses（或 sdkmanager --licenses）接受全部许可，CI 中可使用 yes | sdkmanager --licenses",
+	},
+	{
+		Code: CodeCocoaPodsOutdated,
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`CocoaPods could not find compatible versions for pod`),
+			regexp.MustCompile(`out-of-date source repos`),
+			regexp.MustCompile(`None of your spec sources contain a spec satisfying`),
+		},
+		Summary:    "CocoaPods 本地规格仓库过期，找不到所需版本的 Pod",
+		Suggestion: "在 ios 目录执行 pod repo update（或 pod install --repo-update）；依赖版本确实冲突时删除 Podfile.lock 后重新安装",
+	},
+	{
+		Code: CodeSigningIdentityNotFound,
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`No signing certificate ".*" found`),
+			regexp.MustCompile(`No code signing identities found`),
+			regexp.MustCompile(`(?i)no identity found`),
+		},
+		Summary:    "找不到可用的代码签名证书",
+		Suggestion: "确认 P12 证书已导入钥匙串且与 Team ID 匹配（security find-identity -v -p codesigning），CI 中使用 --p12-cert 和 --cert-password 动态导入",
+	},
+	{
+		Code: CodeProvisioningProfile,
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`Provisioning profile ".*" (doesn't|does not) (include|match)`),
+			regexp.MustCompile(`No profiles for '.*' were found`),
+			regexp.MustCompile(`doesn't match the entitlements file's value`),
+			regexp.MustCompile(`requires a provisioning profile`),
+		},
+		Summary:    "描述文件与 Bundle ID、签名证书或 entitlements 不匹配",
+		Suggestion: "确认 --provisioning-profile 对应的 Bundle ID 与 --bundle-id 一致、包含当前签名证书，并包含项目启用的全部 Capabilities",
+	},
+	{
+		Code: CodePubVersionSolvingFailure,
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`version solving failed`),
+		},
+		Summary:    "pub 依赖版本求解失败，pubspec.yaml 中的依赖版本约束互相冲突或与 Dart SDK 版本不兼容",
+		Suggestion: "根据输出中的依赖链调整 pubspec.yaml 的版本约束，或执行 flutter pub outdated 查看可用版本；确认 Flutter 版本满足 environment.sdk 要求",
+	},
+}
+
+// Diagnose 按 Rules 扫描命令输出，返回匹配的诊断（无匹配时为nil）
+func Diagnose(lines []string) []Diagnosis {
+	var diagnoses []Diagnosis
+	for _, rule := range Rules {
+		if i := rule.match(lines); i >= 0 {
+			diagnoses = append(diagnoses, Diagnosis{
+				Code:       rule.Code,
+				Summary:    rule.Summary,
+				Suggestion: rule.Suggestion,
+				Excerpt:    excerpt(lines, i),
+			})
+		}
+	}
+	return diagnoses
+}
+
+// match 返回第一个匹配行的位置，无匹配时返回-1
+func (r Rule) match(lines []string) int {
+	for i, line := range lines {
+		for _, pattern := range r.Patterns {
+			if pattern.MatchString(line) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// excerpt 截取第 i 行及其前后各 excerptContext 行
+func excerpt(lines []string, i int) string {
+	start, end := i-excerptContext, i+excerptContext+1
+	if start < 0 {
+		start = 0
+	}
+	if end > len(lines) {
+		end = len(lines)
+	}
+	return strings.Join(lines[start:end], "\n")
+}
+
+// Error 附带诊断结果的错误，错误信息末尾列出各诊断的描述和建议
+type Error struct {
+	Err       error
+	Diagnoses []Diagnosis
+}
+
+func (e *Error) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Err.Error())
+	sb.WriteString("\n诊断:")
+	for _, d := range e.Diagnoses {
+		fmt.Fprintf(&sb, "\n  [%s] %s\n    建议: %s", d.Code, d.Summary, d.Suggestion)
+	}
+	return sb.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WrapError 为错误附加诊断结果，err 为nil或没有诊断时原样返回
+func WrapError(err error, diagnoses []Diagnosis) error {
+	if err == nil || len(diagnoses) == 0 {
+		return err
+	}
+	return &Error{Err: err, Diagnoses: diagnoses}
+}
+
+// FromError 返回错误链中附带的诊断结果
+func FromError(err error) []Diagnosis {
+	var diagErr *Error
+	if errors.As(err, &diagErr) {
+		return diagErr.Diagnoses
+	}
+	return nil
+}
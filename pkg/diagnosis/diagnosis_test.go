@@ -0,0 +1,71 @@
+package diagnosis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{"gradle oom", "> Task :app:mergeDexRelease\nExpiring Daemon because JVM heap space is exhausted\njava.lang.OutOfMemoryError: Java heap space", []string{CodeGradleOutOfMemory}},
+		{"metaspace", "Execution failed for task ':app:compileReleaseKotlin'.\n> java.lang.OutOfMemoryError: Metaspace", []string{CodeGradleMetaspace}},
+		{"kotlin", "e: Module was compiled with an incompatible version of Kotlin. The binary version of its metadata is 1.9.0, expected version is 1.7.1.", []string{CodeKotlinVersionMismatch}},
+		{"licenses", "Failed to install the following Android SDK packages as some licences have not been accepted.", []string{CodeAndroidLicenses}},
+		{"cocoapods", "[!] CocoaPods could not find compatible versions for pod \"Firebase/Core\":", []string{CodeCocoaPodsOutdated}},
+		{"signing", "error: No signing certificate \"iOS Distribution\" found: No \"iOS Distribution\" signing certificate matching team ID", []string{CodeSigningIdentityNotFound}},
+		{"profile", "error: Provisioning profile \"App Store\" doesn't include the currently selected device", []string{CodeProvisioningProfile}},
+		{"pub", "Because app depends on http ^2.0.0 which doesn't match any versions, version solving failed.", []string{CodePubVersionSolvingFailure}},
+		{"multiple", "version solving failed\nerror: No profiles for 'com.example.app' were found", []string{CodeProvisioningProfile, CodePubVersionSolvingFailure}},
+		{"unknown", "FAILURE: Build failed with an exception.", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range Diagnose(strings.Split(tt.output, "\n")) {
+				got = append(got, d.Code)
+				if d.Summary == "" || d.Suggestion == "" || d.Excerpt == "" {
+					t.Errorf("诊断 %s 缺少描述、建议或摘录: %+v", d.Code, d)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("期望诊断 %v，实际 %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDiagnoseExcerpt(t *testing.T) {
+	lines := []string{"a", "b", "c", "version solving failed", "d", "e", "f"}
+	diagnoses := Diagnose(lines)
+	if len(diagnoses) != 1 || diagnoses[0].Excerpt != "b\nc\nversion solving failed\nd\ne" {
+		t.Errorf("摘录应包含匹配行及前后各两行: %+v", diagnoses)
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	base := errors.New("命令执行失败")
+	if WrapError(base, nil) != base {
+		t.Error("没有诊断时应返回原错误")
+	}
+
+	diagnoses := Diagnose([]string{"version solving failed"})
+	err := WrapError(base, diagnoses)
+	if !errors.Is(err, base) {
+		t.Error("附带诊断的错误应能解包为原错误")
+	}
+	if !strings.Contains(err.Error(), CodePubVersionSolvingFailure) || !strings.Contains(err.Error(), "建议") {
+		t.Errorf("错误信息应包含诊断: %v", err)
+	}
+
+	wrapped := fmt.Errorf("构建失败: %w", err)
+	if got := FromError(wrapped); len(got) != 1 || got[0].Code != CodePubVersionSolvingFailure {
+		t.Errorf("应能从错误链中取出诊断: %+v", got)
+	}
+}
@@ -0,0 +1,170 @@
+// Package events 定义构建过程中的结构化事件，供界面和看板实时展示构建进度
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+	"github.com/mimicode/flutterbuilder/pkg/hooks"
+)
+
+// Type 事件类型
+type Type string
+
+const (
+	TypeStageStarted      Type = "stage_started"       // 阶段开始
+	TypeStageFinished     Type = "stage_finished"      // 阶段结束（包括跳过的阶段）
+	TypeHookStarted       Type = "hook_started"        // 钩子脚本开始
+	TypeHookFinished      Type = "hook_finished"       // 钩子脚本结束
+	TypeCommandStarted    Type = "command_started"     // 外部命令开始
+	TypeCommandOutputLine Type = "command_output_line" // 外部命令输出一行
+	TypeCommandExited     Type = "command_exited"      // 外部命令退出
+	TypeValidationCheck   Type = "validation_check"    // 产物验证检查项
+	TypeBuildFinished     Type = "build_finished"      // 构建结束
+)
+
+// Event 构建事件，具体类型为本包中的 *StageStarted、*HookFinished 等，可通过类型断言区分
+type Event interface {
+	// Type 返回事件类型
+	Type() Type
+	// EventMeta 返回事件公共字段
+	EventMeta() Meta
+
+	setMeta(meta Meta)
+}
+
+// Meta 事件公共字段
+type Meta struct {
+	BuildID string    // 构建ID
+	Time    time.Time // 事件时间
+}
+
+// EventMeta 返回事件公共字段
+func (m *Meta) EventMeta() Meta {
+	return *m
+}
+
+func (m *Meta) setMeta(meta Meta) {
+	*m = meta
+}
+
+// StageStarted 阶段开始，Index 从1开始，Total 为阶段总数（即日志中的 [n/6]）
+type StageStarted struct {
+	Meta
+	Stage string
+	Index int
+	Total int
+}
+
+// StageFinished 阶段结束，Status 为 success、failed 或 skipped（跳过时 Reason 为原因）
+type StageFinished struct {
+	Meta
+	Stage    string
+	Index    int
+	Total    int
+	Status   string
+	Reason   string
+	Duration time.Duration
+	Error    error
+}
+
+// HookStarted 钩子脚本开始，Index/Total 为该钩子类型中的位置
+type HookStarted struct {
+	Meta
+	HookType   hooks.HookType
+	ScriptPath string
+	Index      int
+	Total      int
+}
+
+// HookFinished 钩子脚本结束
+type HookFinished struct {
+	Meta
+	HookType   hooks.HookType
+	ScriptPath string
+	Result     *hooks.HookResult
+}
+
+// CommandStarted 外部命令（flutter、xcodebuild 等）开始
+type CommandStarted struct {
+	Meta
+	Command []string
+	Dir     string
+}
+
+// CommandOutputLine 外部命令输出的一行，Stream 为 stdout 或 stderr
+type CommandOutputLine struct {
+	Meta
+	Command []string
+	Stream  string
+	Line    string
+}
+
+// CommandExited 外部命令退出，命令未能启动时 ExitCode 为 -1
+type CommandExited struct {
+	Meta
+	Command  []string
+	ExitCode int
+	Duration time.Duration
+	Error    error
+}
+
+// ValidationCheck 产物验证的一个检查项
+type ValidationCheck struct {
+	Meta
+	Detail artifact.ValidationDetail
+}
+
+// BuildFinished 构建结束
+type BuildFinished struct {
+	Meta
+	Success    bool
+	Duration   time.Duration
+	OutputPath string
+	Artifacts  []artifact.ArtifactFile
+	Error      error
+}
+
+func (*StageStarted) Type() Type      { return TypeStageStarted }
+func (*StageFinished) Type() Type     { return TypeStageFinished }
+func (*HookStarted) Type() Type       { return TypeHookStarted }
+func (*HookFinished) Type() Type      { return TypeHookFinished }
+func (*CommandStarted) Type() Type    { return TypeCommandStarted }
+func (*CommandOutputLine) Type() Type { return TypeCommandOutputLine }
+func (*CommandExited) Type() Type     { return TypeCommandExited }
+func (*ValidationCheck) Type() Type   { return TypeValidationCheck }
+func (*BuildFinished) Type() Type     { return TypeBuildFinished }
+
+// Handler 事件处理函数
+type Handler func(Event)
+
+// Emitter 事件发送器：填充构建ID和时间后串行调用处理函数，nil 发送器不发送任何事件
+//
+// 命令的标准输出和标准错误在不同的 goroutine 中读取，Emitter 保证处理函数不会被并发调用。
+// 处理函数在构建流程中同步执行，耗时操作（如网络推送）应转交给其他 goroutine。
+type Emitter struct {
+	mu      sync.Mutex
+	buildID string
+	handler Handler
+}
+
+// NewEmitter 创建事件发送器，handler 为nil时返回nil
+func NewEmitter(buildID string, handler Handler) *Emitter {
+	if handler == nil {
+		return nil
+	}
+	return &Emitter{buildID: buildID, handler: handler}
+}
+
+// Emit 发送事件
+func (e *Emitter) Emit(event Event) {
+	if e == nil {
+		return
+	}
+	event.setMeta(Meta{BuildID: e.buildID, Time: time.Now()})
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.handler(event)
+}
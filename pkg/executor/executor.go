@@ -1,58 +1,85 @@
 package executor
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io"
 	"os/exec"
 	"runtime"
 	"strings"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/diagnosis"
+	"github.com/mimicode/flutterbuilder/pkg/logger"
 )
 
+// processWaitDelay 取消后等待输出管道关闭的最长时间
+const processWaitDelay = 5 * time.Second
+
+// diagnosisOutputLines 命令失败时用于诊断的输出末尾行数
+const diagnosisOutputLines = 2000
+
 // CommandExecutor 命令执行器接口
 type CommandExecutor interface {
 	RunCommand(cmd []string, cwd string) error
 	RunCommandWithOutput(cmd []string, cwd string) (string, error)
+	// RunCommandContext 运行命令，ctx 取消时终止整个进程树
+	RunCommandContext(ctx context.Context, cmd []string, cwd string) error
+	// RunCommandWithOutputContext 运行命令并捕获输出，ctx 取消时终止整个进程树
+	RunCommandWithOutputContext(ctx context.Context, cmd []string, cwd string) (string, error)
 }
 
 // CommandExecutorImpl 命令执行器实现
-type CommandExecutorImpl struct{}
+type CommandExecutorImpl struct {
+	logger   *logger.Logger
+	observer CommandObserver
+}
 
 // NewCommandExecutor 创建新的命令执行器
 func NewCommandExecutor() CommandExecutor {
-	return &CommandExecutorImpl{}
+	return NewCommandExecutorWithLogger(logger.Default())
+}
+
+// NewCommandExecutorWithLogger 创建使用指定日志实例的命令执行器
+// 日志实例带构建ID时，子进程的实时输出每行以 [buildID] 开头
+func NewCommandExecutorWithLogger(log *logger.Logger) CommandExecutor {
+	return &CommandExecutorImpl{logger: log}
 }
 
 // RunCommand 运行命令（实时输出到控制台）
 func (e *CommandExecutorImpl) RunCommand(cmd []string, cwd string) error {
+	return e.RunCommandContext(context.Background(), cmd, cwd)
+}
+
+// RunCommandContext 运行命令（实时输出到控制台），支持取消
+func (e *CommandExecutorImpl) RunCommandContext(ctx context.Context, cmd []string, cwd string) error {
 	if len(cmd) == 0 {
 		return fmt.Errorf("命令不能为空")
 	}
 
-	var command *exec.Cmd
-
-	if runtime.GOOS == "windows" {
-		// Windows下使用cmd /c
-		args := []string{"/c"}
-		args = append(args, cmd...)
-		command = exec.Command("cmd", args...)
-	} else {
-		// Unix系统下直接使用命令和参数，不通过shell
-		command = exec.Command(cmd[0], cmd[1:]...)
-	}
-
-	command.Dir = cwd
+	command := newCommand(ctx, cmd, cwd)
+	e.logger.Debug("执行命令: %s (工作目录: %s)", strings.Join(cmd, " "), cwd)
 
 	// 将标准输出和标准错误直接连接到控制台，实现实时输出
-	command.Stdout = os.Stdout
-	command.Stderr = os.Stderr
+	stdout, stderr := e.logger.Output()
+	command.Stdout = e.logger.Writer(stdout)
+	command.Stderr = e.logger.Writer(stderr)
 
 	// 执行命令
-	err := command.Run()
+	output, err := e.run(command, cmd, cwd)
 	if err != nil {
 		// 构建包含详细信息的错误消息
 		cmdStr := strings.Join(cmd, " ")
-		return fmt.Errorf("命令执行失败: %s\n工作目录: %s\n命令: %s",
-			err.Error(), cwd, cmdStr)
+		if ctx.Err() != nil {
+			return fmt.Errorf("命令已取消: %s: %w", cmdStr, ctx.Err())
+		}
+		// 根据输出识别常见错误并附加修复建议
+		return diagnosis.WrapError(&CommandError{
+			Err:    fmt.Errorf("命令执行失败: %s\n工作目录: %s\n命令: %s", err.Error(), cwd, cmdStr),
+			Output: output,
+		}, diagnosis.Diagnose(output))
 	}
 
 	return nil
@@ -60,28 +87,112 @@ func (e *CommandExecutorImpl) RunCommand(cmd []string, cwd string) error {
 
 // RunCommandWithOutput 运行命令并捕获输出
 func (e *CommandExecutorImpl) RunCommandWithOutput(cmd []string, cwd string) (string, error) {
+	return e.RunCommandWithOutputContext(context.Background(), cmd, cwd)
+}
+
+// RunCommandWithOutputContext 运行命令并捕获输出，支持取消
+func (e *CommandExecutorImpl) RunCommandWithOutputContext(ctx context.Context, cmd []string, cwd string) (string, error) {
 	if len(cmd) == 0 {
 		return "", fmt.Errorf("命令不能为空")
 	}
 
+	command := newCommand(ctx, cmd, cwd)
+	e.logger.Debug("执行命令: %s (工作目录: %s)", strings.Join(cmd, " "), cwd)
+
+	var output bytes.Buffer
+	command.Stdout = &output
+	lines, err := e.run(command, cmd, cwd)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("命令已取消: %s: %w", strings.Join(cmd, " "), ctx.Err())
+		}
+		return "", diagnosis.WrapError(&CommandError{
+			Err:    fmt.Errorf("命令执行失败: %w", err),
+			Output: lines,
+		}, diagnosis.Diagnose(lines))
+	}
+
+	return strings.TrimSpace(output.String()), nil
+}
+
+// CommandError 命令执行失败的错误，携带输出（标准输出和标准错误合并）的最后若干行
+type CommandError struct {
+	Err    error
+	Output []string
+}
+
+func (e *CommandError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// CommandOutput 返回错误链中失败命令的输出，不是命令执行失败时返回nil
+func CommandOutput(err error) []string {
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Output
+	}
+	return nil
+}
+
+// run 执行命令，返回输出（标准输出和标准错误合并）的最后若干行
+// 设置了观察者时将输出按行转发给观察者并通知开始和退出
+func (e *CommandExecutorImpl) run(command *exec.Cmd, cmd []string, cwd string) ([]string, error) {
+	observer := e.observer
+	tail := &outputTail{limit: diagnosisOutputLines}
+	outLines := newLineWriter(observer, tail, cmd, "stdout")
+	errLines := newLineWriter(observer, tail, cmd, "stderr")
+	command.Stdout = teeWriter(command.Stdout, outLines)
+	command.Stderr = teeWriter(command.Stderr, errLines)
+
+	if observer != nil {
+		observer.CommandStarted(cmd, cwd)
+	}
+	startTime := time.Now()
+	err := command.Run()
+	outLines.Flush()
+	errLines.Flush()
+	if observer != nil {
+		observer.CommandExited(cmd, commandExitCode(command, err), time.Since(startTime), err)
+	}
+	return tail.snapshot(), err
+}
+
+// teeWriter 同时写入 w 和 lines，w 为nil时只写入 lines
+func teeWriter(w io.Writer, lines io.Writer) io.Writer {
+	if w == nil {
+		return lines
+	}
+	return io.MultiWriter(w, lines)
+}
+
+// newCommand 创建绑定到 ctx 的命令，取消时终止整个进程树
+func newCommand(ctx context.Context, cmd []string, cwd string) *exec.Cmd {
 	var command *exec.Cmd
 
 	if runtime.GOOS == "windows" {
 		// Windows下使用cmd /c
 		args := []string{"/c"}
 		args = append(args, cmd...)
-		command = exec.Command("cmd", args...)
+		command = exec.CommandContext(ctx, "cmd", args...)
 	} else {
 		// Unix系统下直接使用命令和参数，不通过shell
-		command = exec.Command(cmd[0], cmd[1:]...)
+		command = exec.CommandContext(ctx, cmd[0], cmd[1:]...)
 	}
 
 	command.Dir = cwd
 
-	output, err := command.Output()
-	if err != nil {
-		return "", fmt.Errorf("命令执行失败: %w", err)
+	// 仅在可取消时使用独立进程组，否则保持终端 Ctrl+C 直接作用于子进程
+	if ctx.Done() != nil {
+		PrepareProcessGroup(command)
+	}
+	command.Cancel = func() error {
+		return KillProcessTree(command)
 	}
+	command.WaitDelay = processWaitDelay
 
-	return strings.TrimSpace(string(output)), nil
+	return command
 }
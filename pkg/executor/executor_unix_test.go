@@ -0,0 +1,140 @@
+//go:build !windows
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/diagnosis"
+	"github.com/mimicode/flutterbuilder/pkg/logger"
+)
+
+func TestRunCommandContext_KillsProcessTree(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	// 子shell派生一个后台进程并记录其PID，模拟 flutter -> gradle 的进程树
+	script := "sleep 30 & echo $! > " + pidFile + "; wait"
+	start := time.Now()
+	err := NewCommandExecutor().RunCommandContext(ctx, []string{"sh", "-c", script}, "")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("预期返回取消错误，实际得到: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > processWaitDelay {
+		t.Errorf("取消后命令未及时退出，耗时: %v", elapsed)
+	}
+
+	content, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("读取子进程PID失败: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		t.Fatalf("解析子进程PID失败: %v", err)
+	}
+
+	// 子进程应随进程组一起被终止
+	deadline := time.Now().Add(5 * time.Second)
+	for processAlive(pid) {
+		if time.Now().After(deadline) {
+			syscall.Kill(pid, syscall.SIGKILL)
+			t.Fatalf("子进程 %d 在取消后仍在运行", pid)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// processAlive 检查进程是否仍在运行（已退出但未被回收的僵尸进程视为已终止）
+func processAlive(pid int) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return true
+	}
+	// 格式: pid (comm) state ...
+	fields := strings.Fields(string(stat[strings.LastIndex(string(stat), ")")+1:]))
+	return len(fields) == 0 || fields[0] != "Z"
+}
+
+func TestRunCommandWithOutputContext(t *testing.T) {
+	output, err := NewCommandExecutor().RunCommandWithOutputContext(context.Background(), []string{"echo", "hello"}, "")
+	if err != nil {
+		t.Fatalf("执行命令失败: %v", err)
+	}
+	if output != "hello" {
+		t.Errorf("预期输出 hello，实际得到 %q", output)
+	}
+}
+
+// recordingObserver 记录命令事件
+type recordingObserver struct {
+	mu       sync.Mutex
+	started  int
+	lines    []string
+	exitCode int
+}
+
+func (o *recordingObserver) CommandStarted(cmd []string, cwd string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.started++
+}
+
+func (o *recordingObserver) CommandOutputLine(cmd []string, stream, line string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.lines = append(o.lines, stream+":"+line)
+}
+
+func (o *recordingObserver) CommandExited(cmd []string, exitCode int, duration time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.exitCode = exitCode
+}
+
+func TestCommandObserver(t *testing.T) {
+	observer := &recordingObserver{}
+	e := NewCommandExecutor().(*CommandExecutorImpl)
+	e.SetObserver(observer)
+
+	output, err := e.RunCommandWithOutputContext(context.Background(),
+		[]string{"sh", "-c", "printf 'a\\r\\nb\\n'; printf 'tail' >&2; exit 3"}, "")
+	if err == nil {
+		t.Fatal("预期命令失败")
+	}
+	if output != "" {
+		t.Errorf("失败时不应返回输出，实际 %q", output)
+	}
+
+	want := []string{"stdout:a", "stdout:b", "stderr:tail"}
+	if observer.started != 1 || observer.exitCode != 3 || strings.Join(observer.lines, ",") != strings.Join(want, ",") {
+		t.Errorf("观察者记录错误: 开始=%d 退出码=%d 输出=%v", observer.started, observer.exitCode, observer.lines)
+	}
+}
+
+func TestRunCommandContextDiagnosis(t *testing.T) {
+	log := logger.New()
+	log.SetOutput(io.Discard, io.Discard)
+	err := NewCommandExecutorWithLogger(log).RunCommandContext(context.Background(),
+		[]string{"sh", "-c", "echo 'Resolving dependencies...'; echo 'version solving failed.' >&2; exit 1"}, "")
+	if err == nil {
+		t.Fatal("预期命令失败")
+	}
+	diagnoses := diagnosis.FromError(err)
+	if len(diagnoses) != 1 || diagnoses[0].Code != diagnosis.CodePubVersionSolvingFailure {
+		t.Errorf("应诊断为 pub 版本求解失败: %+v", diagnoses)
+	}
+}
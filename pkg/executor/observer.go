@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CommandObserver 命令执行观察者，接收命令开始、逐行输出和退出通知
+// 标准输出和标准错误在不同的 goroutine 中读取，实现需自行处理并发
+type CommandObserver interface {
+	// CommandStarted 命令开始执行
+	CommandStarted(cmd []string, cwd string)
+	// CommandOutputLine 命令输出一行（不含换行符），stream 为 stdout 或 stderr
+	CommandOutputLine(cmd []string, stream, line string)
+	// CommandExited 命令退出，未能启动时 exitCode 为 -1
+	CommandExited(cmd []string, exitCode int, duration time.Duration, err error)
+}
+
+// SetObserver 设置命令执行观察者（nil 表示不通知）
+func (e *CommandExecutorImpl) SetObserver(observer CommandObserver) {
+	e.observer = observer
+}
+
+// commandExitCode 从命令错误中提取退出码
+func commandExitCode(command *exec.Cmd, err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if command.ProcessState != nil {
+		return command.ProcessState.ExitCode()
+	}
+	if err != nil {
+		return -1
+	}
+	return 0
+}
+
+// outputTail 保留命令输出（标准输出和标准错误合并）的最后若干行，用于失败诊断
+type outputTail struct {
+	mu    sync.Mutex
+	limit int
+	lines []string
+}
+
+func (t *outputTail) add(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lines = append(t.lines, line)
+	if len(t.lines) > t.limit {
+		t.lines = t.lines[len(t.lines)-t.limit:]
+	}
+}
+
+func (t *outputTail) snapshot() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.lines...)
+}
+
+// lineWriter 将写入的数据按行拆分后记录到输出末尾并通知观察者（可为nil），Flush 输出末尾不完整的一行
+type lineWriter struct {
+	mu       sync.Mutex
+	observer CommandObserver
+	tail     *outputTail
+	cmd      []string
+	stream   string
+	buf      []byte
+}
+
+func newLineWriter(observer CommandObserver, tail *outputTail, cmd []string, stream string) *lineWriter {
+	return &lineWriter{observer: observer, tail: tail, cmd: cmd, stream: stream}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush 输出缓冲中剩余的不完整行
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.emit(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *lineWriter) emit(data []byte) {
+	line := strings.TrimRight(string(data), "\r")
+	w.tail.add(line)
+	if w.observer != nil {
+		w.observer.CommandOutputLine(w.cmd, w.stream, line)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package executor
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// PrepareProcessGroup Windows下无需设置，进程树由 taskkill /T 终止
+func PrepareProcessGroup(cmd *exec.Cmd) {}
+
+// KillProcessTree 终止进程及其派生的子进程（如 gradle）
+func KillProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+	if err := kill.Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package hooks
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -8,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mimicode/flutterbuilder/pkg/executor"
 	"github.com/mimicode/flutterbuilder/pkg/logger"
 )
 
@@ -18,43 +20,71 @@ const DefaultTimeout = 30 * time.Second
 type HookExecutorImpl struct {
 	registry    *HookRegistry
 	projectRoot string
+	logger      *logger.Logger
+	observer    HookObserver
 }
 
 // NewHookExecutor 创建新的钩子执行器
 func NewHookExecutor(projectRoot string) HookExecutor {
+	return NewHookExecutorWithLogger(projectRoot, logger.Default())
+}
+
+// NewHookExecutorWithLogger 创建使用指定日志实例的钩子执行器
+func NewHookExecutorWithLogger(projectRoot string, log *logger.Logger) HookExecutor {
 	return &HookExecutorImpl{
 		registry: &HookRegistry{
 			hooks: make(map[HookType][]*HookConfig),
 		},
 		projectRoot: projectRoot,
+		logger:      log,
 	}
 }
 
 // ExecuteHooks 执行指定类型的所有钩子
-func (h *HookExecutorImpl) ExecuteHooks(hookType HookType, context *HookContext) ([]*HookResult, error) {
+func (h *HookExecutorImpl) ExecuteHooks(hookType HookType, hookContext *HookContext) ([]*HookResult, error) {
+	return h.ExecuteHooksContext(context.Background(), hookType, hookContext)
+}
+
+// ExecuteHooksContext 执行指定类型的所有钩子，ctx 取消时终止正在运行的脚本
+func (h *HookExecutorImpl) ExecuteHooksContext(ctx context.Context, hookType HookType, hookContext *HookContext) ([]*HookResult, error) {
 	hooks := h.registry.hooks[hookType]
 	if len(hooks) == 0 {
 		return nil, nil // 没有钩子需要执行
 	}
 
-	logger.Info("执行 %s 钩子 (%d个)...", hookType, len(hooks))
+	h.logger.Info("执行 %s 钩子 (%d个)...", hookType, len(hooks))
 
 	var results []*HookResult
 	for i, hook := range hooks {
-		logger.Info("  [%d/%d] 执行钩子: %s", i+1, len(hooks), hook.ScriptPath)
+		if ctx.Err() != nil {
+			return results, fmt.Errorf("钩子执行已取消: %w", ctx.Err())
+		}
+
+		h.logger.Info("  [%d/%d] 执行钩子: %s", i+1, len(hooks), hook.ScriptPath)
+		if h.observer != nil {
+			h.observer.HookStarted(hookType, hook, i+1, len(hooks))
+		}
 
-		result := h.executeHook(hook, context)
+		result := h.executeHook(ctx, hook, hookContext)
 		results = append(results, result)
+		if h.observer != nil {
+			h.observer.HookFinished(hookType, hook, result)
+		}
+
+		// 取消时忽略 ContinueOnError，直接终止
+		if ctx.Err() != nil {
+			return results, fmt.Errorf("钩子执行已取消: %s: %w", hook.ScriptPath, ctx.Err())
+		}
 
 		if !result.Success && !hook.ContinueOnError {
-			logger.Error("钩子执行失败，终止构建流程: %s", hook.ScriptPath)
+			h.logger.Error("钩子执行失败，终止构建流程: %s", hook.ScriptPath)
 			return results, fmt.Errorf("钩子执行失败: %s", hook.ScriptPath)
 		}
 
 		if !result.Success {
-			logger.Warning("钩子执行失败但继续构建: %s (错误: %v)", hook.ScriptPath, result.Error)
+			h.logger.Warning("钩子执行失败但继续构建: %s (错误: %v)", hook.ScriptPath, result.Error)
 		} else {
-			logger.Success("钩子执行成功: %s (耗时: %.2fs)", hook.ScriptPath, result.Duration.Seconds())
+			h.logger.Success("钩子执行成功: %s (耗时: %.2fs)", hook.ScriptPath, result.Duration.Seconds())
 		}
 	}
 
@@ -62,7 +92,7 @@ func (h *HookExecutorImpl) ExecuteHooks(hookType HookType, context *HookContext)
 }
 
 // executeHook 执行单个钩子
-func (h *HookExecutorImpl) executeHook(hook *HookConfig, context *HookContext) *HookResult {
+func (h *HookExecutorImpl) executeHook(ctx context.Context, hook *HookConfig, context *HookContext) *HookResult {
 	startTime := time.Now()
 	result := &HookResult{
 		Success: false,
@@ -106,6 +136,9 @@ func (h *HookExecutorImpl) executeHook(hook *HookConfig, context *HookContext) *
 	cmd.Env = append(cmd.Env, fmt.Sprintf("FLUTTER_BUILDER_PLATFORM=%s", context.Platform))
 	cmd.Env = append(cmd.Env, fmt.Sprintf("FLUTTER_BUILDER_PROJECT_ROOT=%s", context.ProjectRoot))
 	cmd.Env = append(cmd.Env, fmt.Sprintf("FLUTTER_BUILDER_BUILD_STAGE=%s", context.BuildStage))
+	if buildID := h.logger.BuildID(); buildID != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("FLUTTER_BUILDER_BUILD_ID=%s", buildID))
+	}
 
 	// 添加自定义环境变量
 	if hook.Environment != nil {
@@ -121,7 +154,7 @@ func (h *HookExecutorImpl) executeHook(hook *HookConfig, context *HookContext) *
 	}
 
 	// 执行命令
-	output, err := h.runCommandWithTimeout(cmd, timeout)
+	output, err := h.runCommandWithTimeout(ctx, cmd, timeout)
 	result.Duration = time.Since(startTime)
 	result.Output = output
 
@@ -140,7 +173,7 @@ func (h *HookExecutorImpl) executeHook(hook *HookConfig, context *HookContext) *
 }
 
 // runCommandWithTimeout 带超时的命令执行
-func (h *HookExecutorImpl) runCommandWithTimeout(cmd *exec.Cmd, timeout time.Duration) (string, error) {
+func (h *HookExecutorImpl) runCommandWithTimeout(ctx context.Context, cmd *exec.Cmd, timeout time.Duration) (string, error) {
 	// 创建超时上下文
 	done := make(chan error, 1)
 	var output strings.Builder
@@ -149,6 +182,11 @@ func (h *HookExecutorImpl) runCommandWithTimeout(cmd *exec.Cmd, timeout time.Dur
 	cmd.Stdout = &output
 	cmd.Stderr = &output
 
+	// 可取消时使用独立进程组，便于终止脚本派生的子进程
+	if ctx.Done() != nil {
+		executor.PrepareProcessGroup(cmd)
+	}
+
 	// 启动命令
 	if err := cmd.Start(); err != nil {
 		return "", fmt.Errorf("启动钩子脚本失败: %w", err)
@@ -165,10 +203,13 @@ func (h *HookExecutorImpl) runCommandWithTimeout(cmd *exec.Cmd, timeout time.Dur
 		return output.String(), err
 	case <-time.After(timeout):
 		// 超时，杀死进程
-		if cmd.Process != nil {
-			cmd.Process.Kill()
-		}
+		executor.KillProcessTree(cmd)
 		return output.String(), fmt.Errorf("钩子脚本执行超时 (%v)", timeout)
+	case <-ctx.Done():
+		// 取消，杀死进程树并等待退出
+		executor.KillProcessTree(cmd)
+		<-done
+		return output.String(), fmt.Errorf("钩子脚本已取消: %w", ctx.Err())
 	}
 }
 
@@ -247,3 +288,8 @@ func (h *HookExecutorImpl) ClearHooks(hookType HookType) {
 func (h *HookExecutorImpl) ClearAllHooks() {
 	h.registry.hooks = make(map[HookType][]*HookConfig)
 }
+
+// SetObserver 设置钩子执行观察者
+func (h *HookExecutorImpl) SetObserver(observer HookObserver) {
+	h.observer = observer
+}
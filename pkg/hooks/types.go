@@ -1,6 +1,9 @@
 package hooks
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // HookType 钩子类型
 type HookType string
@@ -23,6 +26,28 @@ const (
 	HookPostPostProcess   HookType = "post_post_process"   // 后处理后
 )
 
+// AllHookTypes 返回所有支持的钩子类型
+func AllHookTypes() []HookType {
+	return []HookType{
+		HookPreClean, HookPostClean,
+		HookPreGetDeps, HookPostGetDeps,
+		HookPreCodeGen, HookPostCodeGen,
+		HookPreSecurityCheck, HookPostSecurityCheck,
+		HookPreBuild, HookPostBuild,
+		HookPrePostProcess, HookPostPostProcess,
+	}
+}
+
+// IsValid 判断是否为支持的钩子类型
+func (t HookType) IsValid() bool {
+	for _, hookType := range AllHookTypes() {
+		if t == hookType {
+			return true
+		}
+	}
+	return false
+}
+
 // HookConfig 钩子配置
 type HookConfig struct {
 	// ScriptPath 脚本文件路径（相对于项目根目录）
@@ -93,6 +118,9 @@ type HookExecutor interface {
 	// ExecuteHooks 执行指定类型的所有钩子
 	ExecuteHooks(hookType HookType, context *HookContext) ([]*HookResult, error)
 
+	// ExecuteHooksContext 执行指定类型的所有钩子，ctx 取消时终止正在运行的脚本
+	ExecuteHooksContext(ctx context.Context, hookType HookType, hookContext *HookContext) ([]*HookResult, error)
+
 	// RegisterHook 注册钩子
 	RegisterHook(hookType HookType, config *HookConfig) error
 
@@ -110,6 +138,17 @@ type HookExecutor interface {
 
 	// ClearAllHooks 清空所有钩子
 	ClearAllHooks()
+
+	// SetObserver 设置钩子执行观察者（nil 表示不通知）
+	SetObserver(observer HookObserver)
+}
+
+// HookObserver 钩子执行观察者，接收每个钩子脚本的开始和结束通知
+type HookObserver interface {
+	// HookStarted 钩子开始执行，index 从1开始，total 为该类型的钩子数
+	HookStarted(hookType HookType, hook *HookConfig, index, total int)
+	// HookFinished 钩子执行结束
+	HookFinished(hookType HookType, hook *HookConfig, result *HookResult)
 }
 
 // HooksConfig 钩子配置集合
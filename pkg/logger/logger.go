@@ -2,9 +2,10 @@ package logger
 
 import (
 	"fmt"
-	"log"
+	"io"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/fatih/color"
 )
@@ -31,158 +32,315 @@ type ExternalLogger interface {
 	Printf(format string, args ...interface{})
 }
 
-var (
-	currentLevel   = InfoLevel
-	debugLogger    *log.Logger
-	infoLogger     *log.Logger
-	warningLogger  *log.Logger
-	errorLogger    *log.Logger
-	externalLogger ExternalLogger // 外部日志接口
-)
+// std 包级函数使用的默认日志实例
+var std = New()
+
+// Logger 日志实例，级别和外部日志接口仅对本实例生效
+// 每个构建持有独立实例，多个构建并发执行时互不影响
+type Logger struct {
+	mu       sync.RWMutex
+	level    LogLevel
+	external ExternalLogger
+	buildID  string    // 构建ID，非空时作为每条日志的前缀
+	out      io.Writer // 日志和命令标准输出（nil 表示标准输出）
+	errOut   io.Writer // 错误日志和命令标准错误（nil 表示标准错误）
+}
+
+// New 创建新的日志实例（默认Info级别）
+func New() *Logger {
+	return &Logger{level: InfoLevel}
+}
 
-// 初始化日志记录器
-func init() {
-	debugLogger = log.New(os.Stdout, "", 0)
-	infoLogger = log.New(os.Stdout, "", 0)
-	warningLogger = log.New(os.Stdout, "", 0)
-	errorLogger = log.New(os.Stderr, "", 0)
+// NewWithBuildID 创建带构建ID的日志实例，每条日志（包括转发给外部日志接口的）以 [buildID] 开头
+func NewWithBuildID(buildID string) *Logger {
+	l := New()
+	l.buildID = buildID
+	return l
+}
+
+// Default 返回包级函数使用的默认日志实例
+func Default() *Logger {
+	return std
 }
 
 // SetLevel 设置日志级别
-func SetLevel(level LogLevel) {
-	currentLevel = level
+func (l *Logger) SetLevel(level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
 }
 
 // SetExternalLogger 设置外部日志接口
-func SetExternalLogger(logger ExternalLogger) {
-	externalLogger = logger
+func (l *Logger) SetExternalLogger(logger ExternalLogger) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.external = logger
 }
 
 // ClearExternalLogger 清除外部日志接口
-func ClearExternalLogger() {
-	externalLogger = nil
+func (l *Logger) ClearExternalLogger() {
+	l.SetExternalLogger(nil)
+}
+
+// SetOutput 设置控制台日志和子进程输出的目标（nil 表示标准输出/标准错误）
+// 例如 JSON 输出模式下将日志全部写入标准错误，保持标准输出只包含结果文档
+func (l *Logger) SetOutput(out, errOut io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out = out
+	l.errOut = errOut
+}
+
+// Output 返回控制台日志和子进程输出的目标
+func (l *Logger) Output() (io.Writer, io.Writer) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	out, errOut := l.out, l.errOut
+	if out == nil {
+		out = color.Output
+	}
+	if errOut == nil {
+		errOut = color.Error
+	}
+	return out, errOut
+}
+
+// BuildID 返回日志实例的构建ID
+func (l *Logger) BuildID() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.buildID
+}
+
+// Writer 返回写入 w 的输出流，设置了构建ID时在每行开头添加 [buildID] 前缀
+// 用于区分并发构建中子进程的实时输出
+func (l *Logger) Writer(w io.Writer) io.Writer {
+	buildID := l.BuildID()
+	if buildID == "" {
+		return w
+	}
+	return &prefixWriter{w: w, prefix: []byte("[" + buildID + "] "), lineStart: true}
+}
+
+// state 读取当前级别、外部日志接口和构建ID
+func (l *Logger) state() (LogLevel, ExternalLogger, string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.level, l.external, l.buildID
+}
+
+// withBuildID 为格式串添加构建ID前缀
+func withBuildID(buildID, format string) string {
+	if buildID == "" {
+		return format
+	}
+	return "[" + strings.ReplaceAll(buildID, "%", "%%") + "] " + format
 }
 
 // Debug 调试日志
-func Debug(format string, args ...interface{}) {
-	if externalLogger != nil {
-		externalLogger.Debug(format, args...)
+func (l *Logger) Debug(format string, args ...interface{}) {
+	level, external, buildID := l.state()
+	format = withBuildID(buildID, format)
+	if external != nil {
+		external.Debug(format, args...)
 		return
 	}
 
-	if currentLevel <= DebugLevel {
+	if level <= DebugLevel {
+		out, _ := l.Output()
 		message := fmt.Sprintf(format, args...)
 		if color.NoColor {
-			debugLogger.Printf("[DEBUG] %s", message)
+			fmt.Fprintf(out, "[DEBUG] %s\n", message)
 		} else {
-			color.Cyan("🔍 %s", message)
+			color.New(color.FgCyan).Fprintf(out, "🔍 %s\n", message)
 		}
 	}
 }
 
 // Info 信息日志
-func Info(format string, args ...interface{}) {
-	if externalLogger != nil {
-		externalLogger.Info(format, args...)
+func (l *Logger) Info(format string, args ...interface{}) {
+	level, external, buildID := l.state()
+	format = withBuildID(buildID, format)
+	if external != nil {
+		external.Info(format, args...)
 		return
 	}
 
-	if currentLevel <= InfoLevel {
+	if level <= InfoLevel {
+		out, _ := l.Output()
 		message := fmt.Sprintf(format, args...)
 		if color.NoColor {
-			infoLogger.Printf("[INFO] %s", message)
+			fmt.Fprintf(out, "[INFO] %s\n", message)
 		} else {
-			color.Blue("ℹ %s", message)
+			color.New(color.FgBlue).Fprintf(out, "ℹ %s\n", message)
 		}
 	}
 }
 
 // Warning 警告日志
-func Warning(format string, args ...interface{}) {
-	if externalLogger != nil {
-		externalLogger.Warning(format, args...)
+func (l *Logger) Warning(format string, args ...interface{}) {
+	level, external, buildID := l.state()
+	format = withBuildID(buildID, format)
+	if external != nil {
+		external.Warning(format, args...)
 		return
 	}
 
-	if currentLevel <= WarningLevel {
+	if level <= WarningLevel {
+		out, _ := l.Output()
 		message := fmt.Sprintf(format, args...)
 		if color.NoColor {
-			warningLogger.Printf("[WARNING] %s", message)
+			fmt.Fprintf(out, "[WARNING] %s\n", message)
 		} else {
-			color.Yellow("⚠ %s", message)
+			color.New(color.FgYellow).Fprintf(out, "⚠ %s\n", message)
 		}
 	}
 }
 
 // Error 错误日志
-func Error(format string, args ...interface{}) {
-	if externalLogger != nil {
-		externalLogger.Error(format, args...)
+func (l *Logger) Error(format string, args ...interface{}) {
+	level, external, buildID := l.state()
+	format = withBuildID(buildID, format)
+	if external != nil {
+		external.Error(format, args...)
 		return
 	}
 
-	if currentLevel <= ErrorLevel {
+	if level <= ErrorLevel {
+		out, errOut := l.Output()
 		message := fmt.Sprintf(format, args...)
 		if color.NoColor {
-			errorLogger.Printf("[ERROR] %s", message)
+			fmt.Fprintf(errOut, "[ERROR] %s\n", message)
 		} else {
-			color.Red("✗ %s", message)
+			color.New(color.FgRed).Fprintf(out, "✗ %s\n", message)
 		}
 	}
 }
 
 // Success 成功日志
-func Success(format string, args ...interface{}) {
-	if externalLogger != nil {
-		externalLogger.Success(format, args...)
+func (l *Logger) Success(format string, args ...interface{}) {
+	level, external, buildID := l.state()
+	format = withBuildID(buildID, format)
+	if external != nil {
+		external.Success(format, args...)
 		return
 	}
 
-	if currentLevel <= InfoLevel {
+	if level <= InfoLevel {
+		out, _ := l.Output()
 		message := fmt.Sprintf(format, args...)
 		if color.NoColor {
-			infoLogger.Printf("[SUCCESS] %s", message)
+			fmt.Fprintf(out, "[SUCCESS] %s\n", message)
 		} else {
-			color.Green("✓ %s", message)
+			color.New(color.FgGreen).Fprintf(out, "✓ %s\n", message)
 		}
 	}
 }
 
 // Header 标题日志
-func Header(title string) {
-	if externalLogger != nil {
-		externalLogger.Header(title)
+func (l *Logger) Header(title string) {
+	_, external, buildID := l.state()
+	if buildID != "" {
+		title = "[" + buildID + "] " + title
+	}
+	if external != nil {
+		external.Header(title)
 		return
 	}
 
+	out, _ := l.Output()
 	separator := strings.Repeat("=", 50)
 	if color.NoColor {
-		fmt.Println(separator)
-		fmt.Println(title)
-		fmt.Println(separator)
+		fmt.Fprintf(out, "%s\n%s\n%s\n", separator, title, separator)
 	} else {
-		color.Cyan(separator)
-		color.Cyan(title)
-		color.Cyan(separator)
+		color.New(color.FgCyan).Fprintf(out, "%s\n%s\n%s\n", separator, title, separator)
 	}
 }
 
 // Println 普通输出
-func Println(args ...interface{}) {
-	if externalLogger != nil {
-		externalLogger.Println(args...)
+func (l *Logger) Println(args ...interface{}) {
+	_, external, buildID := l.state()
+	if buildID != "" && len(args) > 0 {
+		args = append([]interface{}{"[" + buildID + "]"}, args...)
+	}
+	if external != nil {
+		external.Println(args...)
 		return
 	}
-	fmt.Println(args...)
+	out, _ := l.Output()
+	fmt.Fprintln(out, args...)
 }
 
 // Printf 格式化输出
-func Printf(format string, args ...interface{}) {
-	if externalLogger != nil {
-		externalLogger.Printf(format, args...)
+func (l *Logger) Printf(format string, args ...interface{}) {
+	_, external, buildID := l.state()
+	format = withBuildID(buildID, format)
+	if external != nil {
+		external.Printf(format, args...)
 		return
 	}
-	fmt.Printf(format, args...)
+	out, _ := l.Output()
+	fmt.Fprintf(out, format, args...)
+}
+
+// SetLevel 设置默认实例的日志级别
+func SetLevel(level LogLevel) {
+	std.SetLevel(level)
+}
+
+// SetExternalLogger 设置默认实例的外部日志接口
+func SetExternalLogger(logger ExternalLogger) {
+	std.SetExternalLogger(logger)
+}
+
+// SetOutput 设置默认实例的控制台输出目标
+func SetOutput(out, errOut io.Writer) {
+	std.SetOutput(out, errOut)
+}
+
+// ClearExternalLogger 清除默认实例的外部日志接口
+func ClearExternalLogger() {
+	std.ClearExternalLogger()
+}
+
+// Debug 调试日志
+func Debug(format string, args ...interface{}) {
+	std.Debug(format, args...)
+}
+
+// Info 信息日志
+func Info(format string, args ...interface{}) {
+	std.Info(format, args...)
+}
+
+// Warning 警告日志
+func Warning(format string, args ...interface{}) {
+	std.Warning(format, args...)
+}
+
+// Error 错误日志
+func Error(format string, args ...interface{}) {
+	std.Error(format, args...)
+}
+
+// Success 成功日志
+func Success(format string, args ...interface{}) {
+	std.Success(format, args...)
+}
+
+// Header 标题日志
+func Header(title string) {
+	std.Header(title)
+}
+
+// Println 普通输出
+func Println(args ...interface{}) {
+	std.Println(args...)
+}
+
+// Printf 格式化输出
+func Printf(format string, args ...interface{}) {
+	std.Printf(format, args...)
 }
 
 // Fprintf 格式化输出到指定writer
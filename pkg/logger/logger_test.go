@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
 )
 
@@ -38,3 +40,119 @@ func TestPrintFunctions(t *testing.T) {
 	Println("测试Println")
 	Printf("测试Printf: %s", "参数")
 }
+
+type countingLogger struct {
+	count int
+}
+
+func (c *countingLogger) Debug(format string, args ...interface{})   { c.count++ }
+func (c *countingLogger) Info(format string, args ...interface{})    { c.count++ }
+func (c *countingLogger) Warning(format string, args ...interface{}) { c.count++ }
+func (c *countingLogger) Error(format string, args ...interface{})   { c.count++ }
+func (c *countingLogger) Success(format string, args ...interface{}) { c.count++ }
+func (c *countingLogger) Header(title string)                        { c.count++ }
+func (c *countingLogger) Println(args ...interface{})                { c.count++ }
+func (c *countingLogger) Printf(format string, args ...interface{})  { c.count++ }
+
+func TestLoggerInstanceIsolation(t *testing.T) {
+	// 实例的级别和外部日志接口不影响其他实例及包级默认实例
+	first, second := &countingLogger{}, &countingLogger{}
+
+	a := New()
+	a.SetExternalLogger(first)
+	a.SetLevel(DebugLevel)
+
+	b := New()
+	b.SetExternalLogger(second)
+
+	a.Debug("a")
+	b.Info("b")
+	b.Warning("b")
+	Info("默认实例")
+
+	if first.count != 1 {
+		t.Errorf("实例 a 应记录 1 条日志，实际 %d", first.count)
+	}
+	if second.count != 2 {
+		t.Errorf("实例 b 应记录 2 条日志，实际 %d", second.count)
+	}
+
+	b.ClearExternalLogger()
+	b.Info("清除后输出到控制台")
+	if second.count != 2 {
+		t.Errorf("清除外部日志接口后不应再转发，实际 %d", second.count)
+	}
+}
+
+type recordingLogger struct {
+	countingLogger
+	messages []string
+}
+
+func (r *recordingLogger) Info(format string, args ...interface{}) {
+	r.messages = append(r.messages, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingLogger) Header(title string) {
+	r.messages = append(r.messages, title)
+}
+
+func TestLoggerBuildID(t *testing.T) {
+	external := &recordingLogger{}
+	l := NewWithBuildID("apk-1")
+	l.SetExternalLogger(external)
+
+	l.Info("进度 %d%%", 50)
+	l.Header("构建")
+
+	want := []string{"[apk-1] 进度 50%", "[apk-1] 构建"}
+	if len(external.messages) != len(want) {
+		t.Fatalf("期望 %d 条日志，实际 %v", len(want), external.messages)
+	}
+	for i := range want {
+		if external.messages[i] != want[i] {
+			t.Errorf("第 %d 条日志期望 %q，实际 %q", i, want[i], external.messages[i])
+		}
+	}
+
+	if New().BuildID() != "" {
+		t.Error("默认实例不应带构建ID")
+	}
+}
+
+func TestLoggerWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	// 无构建ID时原样输出
+	if w := New().Writer(&buf); w != &buf {
+		t.Error("无构建ID时应直接返回原输出流")
+	}
+
+	w := NewWithBuildID("ios-2").Writer(&buf)
+	fmt.Fprint(w, "第一行\n第二")
+	fmt.Fprint(w, "行\n\n第三行")
+
+	want := "[ios-2] 第一行\n[ios-2] 第二行\n[ios-2] \n[ios-2] 第三行"
+	if buf.String() != want {
+		t.Errorf("期望 %q，实际 %q", want, buf.String())
+	}
+}
+
+func TestLoggerSetOutput(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewWithBuildID("apk-3")
+	l.SetOutput(&out, &errOut)
+
+	l.Info("开始构建")
+	l.Header("APK Build")
+	l.Printf("大小: %d\n", 10)
+
+	for _, want := range []string{"[apk-3] 开始构建", "[apk-3] APK Build", "[apk-3] 大小: 10"} {
+		if !bytes.Contains(out.Bytes(), []byte(want)) {
+			t.Errorf("输出中缺少 %q: %q", want, out.String())
+		}
+	}
+	if stdout, stderr := l.Output(); stdout != &out || stderr != &errOut {
+		t.Error("Output 应返回设置的输出目标")
+	}
+}
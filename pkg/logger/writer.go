@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// prefixWriter 在每行开头添加前缀的输出流
+type prefixWriter struct {
+	mu        sync.Mutex
+	w         io.Writer
+	prefix    []byte
+	lineStart bool
+}
+
+// Write 写入数据，按换行符拆分并为每个新行添加前缀
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var buf bytes.Buffer
+	for _, b := range data {
+		if p.lineStart {
+			buf.Write(p.prefix)
+			p.lineStart = false
+		}
+		buf.WriteByte(b)
+		if b == '\n' {
+			p.lineStart = true
+		}
+	}
+
+	if _, err := p.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
@@ -0,0 +1,79 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mimicode/flutterbuilder/internal/fsutil"
+)
+
+// FilesystemPublisher 将产物复制到本地目录或挂载的网络目录（如 NFS）
+type FilesystemPublisher struct {
+	// Dir 发布目录（不存在时自动创建）
+	Dir string
+	// BaseURL 发布目录对外的访问地址（如 https://builds.example.com/app），为空时返回 file:// 地址
+	BaseURL string
+}
+
+// Name 返回发布目录
+func (p *FilesystemPublisher) Name() string {
+	return p.Dir
+}
+
+// Validate 检查发布目录
+func (p *FilesystemPublisher) Validate() error {
+	if p.Dir == "" {
+		return fmt.Errorf("发布目录不能为空")
+	}
+	if p.BaseURL != "" {
+		if _, err := url.Parse(p.BaseURL); err != nil {
+			return fmt.Errorf("无效的访问地址: %w", err)
+		}
+	}
+	return nil
+}
+
+// Publish 复制文件或目录到 Dir/key。文件先写入同目录下的临时文件再重命名，
+// 避免下载方读到未写完的文件
+func (p *FilesystemPublisher) Publish(ctx context.Context, key, localPath string) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	err = walkFiles(key, localPath, func(key, file string, info os.FileInfo) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fsutil.CopyFile(file, filepath.Join(p.Dir, filepath.FromSlash(key)), info.Mode().Perm())
+	})
+	if err != nil {
+		return "", fmt.Errorf("发布 %s 失败: %w", localPath, err)
+	}
+	return p.url(key)
+}
+
+// url 返回 key 的访问地址
+func (p *FilesystemPublisher) url(key string) (string, error) {
+	if p.BaseURL != "" {
+		return strings.TrimSuffix(p.BaseURL, "/") + "/" + escapeKey(key), nil
+	}
+	absPath, err := filepath.Abs(filepath.Join(p.Dir, filepath.FromSlash(key)))
+	if err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(absPath)}).String(), nil
+}
+
+// escapeKey 对发布路径的每一段进行 URL 转义（保留 /）
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
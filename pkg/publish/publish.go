@@ -0,0 +1,74 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Publisher 产物发布后端，构建成功且产物验证通过后调用。
+// 同一个 Publisher 可能被并发的多个构建（构建矩阵）同时使用，实现需要并发安全
+type Publisher interface {
+	// Name 返回发布目标的描述（用于日志，如 s3://bucket/prefix）
+	Name() string
+	// Publish 将本地文件或目录发布为 key（使用 / 分隔的相对路径），返回可访问的地址。
+	// 目录按相对路径逐个发布其中的文件，返回目录的地址
+	Publish(ctx context.Context, key, localPath string) (string, error)
+}
+
+// CleanKey 检查并规范化发布路径：使用 / 分隔，不能为空、不能是绝对路径或包含 ..
+func CleanKey(key string) (string, error) {
+	key = strings.ReplaceAll(key, `\`, "/")
+	cleaned := path.Clean(key)
+	if key == "" || cleaned == "." || path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("无效的发布路径: %q", key)
+	}
+	return cleaned, nil
+}
+
+// joinKey 拼接发布路径前缀和 key，前缀为空时返回 key
+func joinKey(prefix, key string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return key
+	}
+	return prefix + "/" + key
+}
+
+// walkFiles 遍历本地文件或目录，对其中每个文件调用 fn（key 为发布路径）。
+// 指向文件的符号链接按目标文件发布，指向目录的符号链接不展开
+func walkFiles(key, localPath string, fn func(key, file string, info os.FileInfo) error) error {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fn(key, localPath, info)
+	}
+
+	return filepath.Walk(localPath, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if info, err = os.Stat(file); err != nil {
+				return err
+			}
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(localPath, file)
+		if err != nil {
+			return err
+		}
+		return fn(key+"/"+filepath.ToSlash(rel), file, info)
+	})
+}
@@ -0,0 +1,78 @@
+package publish
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCleanKey(t *testing.T) {
+	tests := map[string]string{
+		"app.apk":            "app.apk",
+		"shop/./1.0/app.apk": "shop/1.0/app.apk",
+		`shop\1.0\app.apk`:   "shop/1.0/app.apk",
+		"shop/../app.apk":    "app.apk",
+		"":                   "",
+		"/etc/passwd":        "",
+		"../app.apk":         "",
+		"shop/../../app.apk": "",
+	}
+	for key, want := range tests {
+		got, err := CleanKey(key)
+		if want == "" {
+			if err == nil {
+				t.Errorf("CleanKey(%q) 应返回错误，实际: %q", key, got)
+			}
+		} else if got != want || err != nil {
+			t.Errorf("CleanKey(%q) = %q, %v，应为 %q", key, got, err, want)
+		}
+	}
+}
+
+func TestFilesystemPublisher(t *testing.T) {
+	src := t.TempDir()
+	apk := filepath.Join(src, "app.apk")
+	os.WriteFile(apk, []byte("apk"), 0644)
+	debugInfo := filepath.Join(src, "debug-info")
+	os.MkdirAll(debugInfo, 0755)
+	os.WriteFile(filepath.Join(debugInfo, "app.symbols"), []byte("symbols"), 0644)
+
+	dest := filepath.Join(t.TempDir(), "nfs", "builds")
+	publisher := &FilesystemPublisher{Dir: dest}
+	if err := publisher.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := publisher.Publish(context.Background(), "shop/app+1.apk", apk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dest, "shop", "app+1.apk")); string(data) != "apk" {
+		t.Error("文件未发布到目标目录")
+	}
+	if url != "file://"+filepath.ToSlash(filepath.Join(dest, "shop", "app+1.apk")) {
+		t.Errorf("file:// 地址错误: %s", url)
+	}
+
+	publisher.BaseURL = "https://builds.example.com/"
+	url, err = publisher.Publish(context.Background(), "shop/debug info", debugInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "https://builds.example.com/shop/debug%20info" {
+		t.Errorf("访问地址错误: %s", url)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dest, "shop", "debug info", "app.symbols")); string(data) != "symbols" {
+		t.Error("目录未完整发布")
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(dest, "shop"))
+	if len(entries) != 2 {
+		t.Errorf("发布目录中不应残留临时文件: %v", entries)
+	}
+
+	if _, err := publisher.Publish(context.Background(), "../escape.apk", apk); err == nil {
+		t.Error("发布路径不能超出发布目录")
+	}
+}
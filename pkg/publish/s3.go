@@ -0,0 +1,317 @@
+package publish
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mimicode/flutterbuilder/pkg/artifact"
+)
+
+// DefaultS3Region 未设置区域时使用的区域（MinIO 默认区域）
+const DefaultS3Region = "us-east-1"
+
+// S3Publisher 将产物上传到 S3 兼容的对象存储（AWS S3、MinIO、OSS、COS 等），
+// 使用 AWS Signature Version 4 签名的 PutObject 请求逐个上传文件
+type S3Publisher struct {
+	// Endpoint 服务地址（如 https://minio.example.com:9000），为空时使用 https://s3.<Region>.amazonaws.com
+	Endpoint string
+	// Region 区域，为空时使用 DefaultS3Region
+	Region string
+	// Bucket 存储桶
+	Bucket string
+	// Prefix 对象路径前缀（如 shop/android）
+	Prefix string
+	// AccessKey、SecretKey、SessionToken 访问凭证（SessionToken 仅临时凭证需要），
+	// AccessKey 为空时使用环境变量 AWS_ACCESS_KEY_ID、AWS_SECRET_ACCESS_KEY、AWS_SESSION_TOKEN
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	// VirtualHostStyle 使用 bucket.endpoint 形式的地址，默认使用 endpoint/bucket 形式（MinIO 需要）
+	VirtualHostStyle bool
+	// PublicURL 对象对外的访问地址前缀（如 CDN 地址），为空时返回对象的上传地址
+	PublicURL string
+	// Client HTTP 客户端，为空时使用 http.DefaultClient
+	Client *http.Client
+
+	now func() time.Time // 签名时间（测试使用）
+}
+
+// Name 返回 s3://bucket/prefix
+func (p *S3Publisher) Name() string {
+	return "s3://" + joinKey(p.Bucket, strings.Trim(p.Prefix, "/"))
+}
+
+// Validate 检查存储桶、服务地址和访问凭证
+func (p *S3Publisher) Validate() error {
+	if p.Bucket == "" {
+		return fmt.Errorf("S3 存储桶不能为空")
+	}
+	if accessKey, secretKey, _ := p.credentials(); accessKey == "" || secretKey == "" {
+		return fmt.Errorf("S3 访问凭证不能为空（access key 和 secret key，或环境变量 AWS_ACCESS_KEY_ID、AWS_SECRET_ACCESS_KEY）")
+	}
+	if _, err := p.endpoint(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Publish 上传文件或目录中的每个文件为 Prefix/key，返回对象（目录为 key/ 前缀）的地址
+func (p *S3Publisher) Publish(ctx context.Context, key, localPath string) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return "", fmt.Errorf("发布 %s 失败: %w", localPath, err)
+	}
+	err = walkFiles(key, localPath, func(key, file string, info os.FileInfo) error {
+		return p.putObject(ctx, joinKey(p.Prefix, key), file, info.Size())
+	})
+	if err != nil {
+		return "", fmt.Errorf("发布 %s 失败: %w", localPath, err)
+	}
+
+	objectKey := joinKey(p.Prefix, key)
+	if info.IsDir() {
+		objectKey += "/"
+	}
+	if p.PublicURL != "" {
+		return strings.TrimSuffix(p.PublicURL, "/") + "/" + awsEscape(objectKey), nil
+	}
+	objectURL, err := p.objectURL(objectKey)
+	if err != nil {
+		return "", err
+	}
+	return objectURL.String(), nil
+}
+
+// putObject 上传单个文件
+func (p *S3Publisher) putObject(ctx context.Context, key, file string, size int64) error {
+	// 请求体哈希与校验和清单使用同一计算方式
+	payloadHash, err := artifact.FileSHA256(file)
+	if err != nil {
+		return err
+	}
+	objectURL, err := p.objectURL(key)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var body io.Reader = f
+	if size == 0 {
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+	accessKey, secretKey, sessionToken := p.credentials()
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+	signV4(req, payloadHash, accessKey, secretKey, p.region(), p.signTime())
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return responseError(key, resp)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// responseError 将 S3 错误响应（XML）转换为错误
+func responseError(key string, resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var s3Err struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(data, &s3Err) == nil && s3Err.Code != "" {
+		return fmt.Errorf("上传 %s 失败: %s %s（HTTP %d）", key, s3Err.Code, s3Err.Message, resp.StatusCode)
+	}
+	return fmt.Errorf("上传 %s 失败: HTTP %d %s", key, resp.StatusCode, strings.TrimSpace(string(data)))
+}
+
+// credentials 返回访问凭证，未设置 AccessKey 时读取环境变量
+func (p *S3Publisher) credentials() (accessKey, secretKey, sessionToken string) {
+	if p.AccessKey != "" {
+		return p.AccessKey, p.SecretKey, p.SessionToken
+	}
+	return os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN")
+}
+
+// region 返回区域
+func (p *S3Publisher) region() string {
+	if p.Region == "" {
+		return DefaultS3Region
+	}
+	return p.Region
+}
+
+// signTime 返回签名时间
+func (p *S3Publisher) signTime() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
+
+// endpoint 解析服务地址
+func (p *S3Publisher) endpoint() (*url.URL, error) {
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + p.region() + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("无效的 S3 服务地址: %s（应为 http:// 或 https:// 地址）", p.Endpoint)
+	}
+	return u, nil
+}
+
+// objectURL 返回对象的地址（路径按 SigV4 规则转义）
+func (p *S3Publisher) objectURL(key string) (*url.URL, error) {
+	endpoint, err := p.endpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	host, objectPath := endpoint.Host, strings.TrimSuffix(endpoint.Path, "/")+"/"+p.Bucket+"/"+key
+	if p.VirtualHostStyle {
+		host, objectPath = p.Bucket+"."+endpoint.Host, strings.TrimSuffix(endpoint.Path, "/")+"/"+key
+	}
+	return url.Parse(endpoint.Scheme + "://" + host + awsEscape(objectPath))
+}
+
+// signV4 使用 AWS Signature Version 4 签名请求（设置 X-Amz-Date、X-Amz-Content-Sha256 和 Authorization）。
+// 签名包括 Host 和请求中已设置的全部请求头
+func signV4(req *http.Request, payloadHash, accessKey, secretKey, region string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		normalized := make([]string, len(values))
+		for i, value := range values {
+			normalized[i] = strings.Join(strings.Fields(value), " ")
+		}
+		headers[strings.ToLower(name)] = strings.Join(normalized, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{date, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery 返回按键排序并转义的查询字符串
+func canonicalQuery(query url.Values) string {
+	var pairs []string
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, queryEscape(key)+"="+queryEscape(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape 按 SigV4 规则转义：除字母、数字、- _ . ~ 和 / 外全部转义
+func awsEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~/", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// queryEscape 按 SigV4 规则转义查询参数（/ 同样转义）
+func queryEscape(s string) string {
+	return strings.ReplaceAll(awsEscape(s), "/", "%2F")
+}
+
+// hmacSHA256 计算 HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -18,12 +18,19 @@ type SecurityChecker interface {
 // SecurityCheckerImpl 安全配置检查器实现
 type SecurityCheckerImpl struct {
 	projectRoot string
+	logger      *logger.Logger
 }
 
 // NewSecurityChecker 创建新的安全配置检查器
 func NewSecurityChecker(projectRoot string) SecurityChecker {
+	return NewSecurityCheckerWithLogger(projectRoot, logger.Default())
+}
+
+// NewSecurityCheckerWithLogger 创建使用指定日志实例的安全配置检查器
+func NewSecurityCheckerWithLogger(projectRoot string, log *logger.Logger) SecurityChecker {
 	return &SecurityCheckerImpl{
 		projectRoot: projectRoot,
+		logger:      log,
 	}
 }
 
@@ -32,17 +39,17 @@ func (s *SecurityCheckerImpl) CheckAndroidSecurity() error {
 	// 检查ProGuard规则文件
 	proguardFile := filepath.Join(s.projectRoot, "android", "app", "proguard-rules.pro")
 	if _, err := os.Stat(proguardFile); err == nil {
-		logger.Success("ProGuard配置文件存在")
+		s.logger.Success("ProGuard配置文件存在")
 	} else {
-		logger.Warning("ProGuard规则文件未找到")
+		s.logger.Warning("ProGuard规则文件未找到")
 	}
 
 	// 检查签名配置
 	keyProperties := filepath.Join(s.projectRoot, "android", "key.properties")
 	if _, err := os.Stat(keyProperties); err == nil {
-		logger.Success("发布签名配置存在")
+		s.logger.Success("发布签名配置存在")
 	} else {
-		logger.Warning("签名配置文件未找到 - 将使用调试签名")
+		s.logger.Warning("签名配置文件未找到 - 将使用调试签名")
 	}
 
 	return nil
@@ -52,14 +59,14 @@ func (s *SecurityCheckerImpl) CheckAndroidSecurity() error {
 func (s *SecurityCheckerImpl) CheckIOSSecurity() error {
 	iosProject := filepath.Join(s.projectRoot, "ios", "Runner.xcodeproj")
 	if _, err := os.Stat(iosProject); err == nil {
-		logger.Success("iOS项目配置存在")
+		s.logger.Success("iOS项目配置存在")
 	} else {
 		return fmt.Errorf("iOS项目未找到")
 	}
 
 	// 检查动态证书配置
 	if runtime.GOOS != "darwin" {
-		logger.Warning("iOS构建需要macOS环境")
+		s.logger.Warning("iOS构建需要macOS环境")
 	}
 
 	return nil